@@ -0,0 +1,152 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"aicode/ai/chatmodel"
+	"aicode/consts"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// defaultMaxSteps 单次生成允许的最大模型调用轮数，防止模型陷入无限工具调用
+const defaultMaxSteps = 30
+
+// ErrMaxStepsExceeded 模型调用轮数超过上限仍未结束
+var ErrMaxStepsExceeded = errors.New("代码生成超过最大工具调用轮数")
+
+// Request 代码生成 agent 请求
+type Request struct {
+	Model    string                                  // 请求的模型名
+	AppId    string                                  // 应用 id，工具读写范围限定在该应用目录
+	Messages []*schema.Message                       // 系统提示词 + 历史 + 本轮提问
+	Stream   bool                                    // 是否以流式调用模型，流式时通过 OnDelta 推送增量文本
+	Allow    func(meta chatmodel.ChatModelMeta) bool // 过滤降级链上调用方无权使用的模型
+	MaxSteps int                                     // 最大模型调用轮数，<= 0 时使用默认值
+}
+
+// Callbacks agent 执行过程中的回调，均可为 nil
+type Callbacks struct {
+	OnDelta      func(content string)                                              // 模型输出的增量文本（仅流式）
+	OnToolCall   func(call schema.ToolCall)                                        // 模型发起工具调用
+	OnToolResult func(call schema.ToolCall, result string, err error)              // 工具执行完毕
+	OnStep       func(model string, prompt []*schema.Message, msg *schema.Message) // 每轮模型调用完成，prompt 为本轮发送的消息，可用于记录 token 用量
+}
+
+// Result 代码生成 agent 结果
+type Result struct {
+	Model   string // 最后一轮实际响应的模型名
+	Content string // 模型最终的文本回复
+}
+
+// Run 执行工具调用循环：调用模型 -> 执行模型请求的工具 -> 回填工具结果，直到模型不再调用工具
+// 工具执行失败时将错误信息作为工具结果回填，由模型自行修正
+func Run(ctx context.Context, req Request, cb Callbacks) (*Result, error) {
+	tools, err := newAppTools(req.AppId)
+	if err != nil {
+		return nil, fmt.Errorf("创建工具失败: %w", err)
+	}
+	toolMap := make(map[string]tool.InvokableTool, len(tools))
+	toolInfos := make([]*schema.ToolInfo, 0, len(tools))
+	for _, t := range tools {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("获取工具信息失败: %w", err)
+		}
+		toolMap[info.Name] = t
+		toolInfos = append(toolInfos, info)
+	}
+
+	respType := consts.ChatRespTypeGenerate
+	if req.Stream {
+		respType = consts.ChatRespTypeStream
+	}
+	maxSteps := req.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = defaultMaxSteps
+	}
+
+	messages := append([]*schema.Message{}, req.Messages...)
+	for step := 0; step < maxSteps; step++ {
+		result, err := chatmodel.ResilientToolChat(ctx, req.Model, messages, respType, req.Allow, toolInfos)
+		if err != nil {
+			return nil, err
+		}
+		msg := result.Message
+		if req.Stream {
+			if msg, err = collectStream(result.Stream, cb.OnDelta); err != nil {
+				return nil, err
+			}
+		}
+		if cb.OnStep != nil {
+			cb.OnStep(result.Model, messages, msg)
+		}
+		messages = append(messages, msg)
+
+		if len(msg.ToolCalls) == 0 {
+			return &Result{Model: result.Model, Content: msg.Content}, nil
+		}
+		for _, call := range msg.ToolCalls {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			output := invokeTool(ctx, toolMap, call, cb)
+			messages = append(messages, schema.ToolMessage(output, call.ID,
+				schema.WithToolName(call.Function.Name)))
+		}
+	}
+	return nil, ErrMaxStepsExceeded
+}
+
+// invokeTool 执行单个工具调用并触发回调，返回回填给模型的工具结果
+func invokeTool(ctx context.Context, toolMap map[string]tool.InvokableTool,
+	call schema.ToolCall, cb Callbacks) string {
+	if cb.OnToolCall != nil {
+		cb.OnToolCall(call)
+	}
+	var output string
+	var err error
+	if t, ok := toolMap[call.Function.Name]; ok {
+		output, err = t.InvokableRun(ctx, call.Function.Arguments)
+	} else {
+		err = fmt.Errorf("未知的工具: %s", call.Function.Name)
+	}
+	if cb.OnToolResult != nil {
+		cb.OnToolResult(call, output, err)
+	}
+	if err != nil {
+		return "错误: " + err.Error()
+	}
+	return output
+}
+
+// collectStream 读取一轮流式输出，推送增量文本并合并为完整消息（含工具调用）
+func collectStream(stream *schema.StreamReader[*schema.Message],
+	onDelta func(content string)) (*schema.Message, error) {
+	defer stream.Close()
+	chunks := make([]*schema.Message, 0, 64)
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if chunk == nil {
+			continue
+		}
+		chunks = append(chunks, chunk)
+		if chunk.Content != "" && onDelta != nil {
+			onDelta(chunk.Content)
+		}
+	}
+	if len(chunks) == 0 {
+		return schema.AssistantMessage("", nil), nil
+	}
+	return schema.ConcatMessages(chunks)
+}
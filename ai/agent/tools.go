@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"aicode/file"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+// 工具名称，与系统提示词中的说明保持一致
+const (
+	ToolWriteFile  = "write_file"
+	ToolReadFile   = "read_file"
+	ToolListDir    = "list_dir"
+	ToolDeleteFile = "delete_file"
+)
+
+// writeFileInput write_file 工具参数
+type writeFileInput struct {
+	Path    string `json:"path" jsonschema:"description=相对于工程根目录的文件路径，使用 / 分隔，如 src/main.js"`
+	Content string `json:"content" jsonschema:"description=文件的完整内容，会覆盖已有文件"`
+}
+
+// pathInput read_file / delete_file 工具参数
+type pathInput struct {
+	Path string `json:"path" jsonschema:"description=相对于工程根目录的文件路径，使用 / 分隔"`
+}
+
+// listDirInput list_dir 工具参数
+type listDirInput struct {
+	Path string `json:"path,omitempty" jsonschema:"description=相对于工程根目录的目录路径，为空时列出整个工程"`
+}
+
+// newAppTools 创建作用域限定在应用目录内的文件工具，路径越界由 file 包统一校验
+func newAppTools(appId string) ([]tool.InvokableTool, error) {
+	writeTool, err := utils.InferTool(ToolWriteFile, "创建或覆盖写入工程中的一个文件",
+		func(ctx context.Context, in writeFileInput) (string, error) {
+			if err := file.WriteAppFile(appId, in.Path, in.Content); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("已写入 %s（%d 字节）", in.Path, len(in.Content)), nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	readTool, err := utils.InferTool(ToolReadFile, "读取工程中一个文件的完整内容",
+		func(ctx context.Context, in pathInput) (string, error) {
+			return file.ReadAppFile(appId, in.Path)
+		})
+	if err != nil {
+		return nil, err
+	}
+	listTool, err := utils.InferTool(ToolListDir, "递归列出工程目录下的全部文件与目录",
+		func(ctx context.Context, in listDirInput) (string, error) {
+			entries, err := file.ListAppDir(appId, in.Path)
+			if err != nil {
+				return "", err
+			}
+			data, err := json.Marshal(entries)
+			if err != nil {
+				return "", err
+			}
+			return string(data), nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	deleteTool, err := utils.InferTool(ToolDeleteFile, "删除工程中的一个文件",
+		func(ctx context.Context, in pathInput) (string, error) {
+			if err := file.DeleteAppFile(appId, in.Path); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("已删除 %s", in.Path), nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return []tool.InvokableTool{writeTool, readTool, listTool, deleteTool}, nil
+}
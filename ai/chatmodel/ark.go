@@ -0,0 +1,30 @@
+package chatmodel
+
+import (
+	"context"
+	"errors"
+
+	"aicode/config"
+
+	"github.com/cloudwego/eino-ext/components/model/ark"
+	"github.com/cloudwego/eino/components/model"
+)
+
+// newArkFactory 火山方舟，model 填写推理接入点 id；base_url 为空时使用 SDK 默认地址
+func newArkFactory(provider config.ChatModelProviderConfig) (ChatModelFactory, error) {
+	// 校验参数
+	if provider.APIKey == "" {
+		return nil, errors.New("火山方舟 API 密钥不能为空")
+	}
+	if provider.Model == "" {
+		return nil, errors.New("火山方舟 模型接入点不能为空")
+	}
+
+	return func(ctx context.Context) (model.BaseChatModel, error) {
+		return ark.NewChatModel(ctx, &ark.ChatModelConfig{
+			APIKey:  provider.APIKey,
+			Model:   provider.Model,
+			BaseURL: provider.BaseURL,
+		})
+	}, nil
+}
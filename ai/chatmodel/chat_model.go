@@ -9,23 +9,67 @@ import (
 
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
+	"github.com/sirupsen/logrus"
 )
 
 type ChatModelFactory func(ctx context.Context) (model.BaseChatModel, error)
 
+// providerBuilder 根据供应商配置构造聊天模型工厂，配置不完整时返回错误
+type providerBuilder func(provider config.ChatModelProviderConfig) (ChatModelFactory, error)
+
 var chatModelRegistry = make(map[string]ChatModelFactory)
 
+// providerBuilders 供应商类型 -> 工厂构造函数，新增供应商时在此追加
+var providerBuilders = map[consts.ChatModelProviderType]providerBuilder{
+	consts.ChatModelProviderDeepSeek: newDeepSeekFactory,
+	consts.ChatModelProviderOpenAI:   newOpenAIFactory,
+	consts.ChatModelProviderOllama:   newOllamaFactory,
+	consts.ChatModelProviderQwen:     newQwenFactory,
+	consts.ChatModelProviderArk:      newArkFactory,
+}
+
+// InitChatModel 按配置注册全部已启用的聊天模型
+// 单个供应商未启用或配置不完整时仅跳过并记录日志，不影响其他模型及服务启动
 func InitChatModel(cfg *config.Config) (map[string]ChatModelFactory, error) {
-	err := initDeepSeek(cfg)
-	if err != nil {
-		return nil, err
+	initResiliencePolicy(cfg.AI.Resilience)
+	for _, provider := range cfg.AI.GetProviders() {
+		if !provider.Enabled {
+			logrus.Infof("聊天模型 %s 未启用，跳过注册", provider.Name)
+			continue
+		}
+		if provider.Name == "" {
+			logrus.Warnf("聊天模型供应商 %s 缺少 name 配置，跳过注册", provider.Type)
+			continue
+		}
+		build, ok := providerBuilders[consts.ChatModelProviderType(provider.Type)]
+		if !ok {
+			logrus.Warnf("聊天模型 %s 的供应商类型 %s 不受支持，跳过注册", provider.Name, provider.Type)
+			continue
+		}
+		factory, err := build(provider)
+		if err != nil {
+			logrus.Warnf("聊天模型 %s 配置不完整，跳过注册: %v", provider.Name, err)
+			continue
+		}
+		if _, exists := chatModelRegistry[provider.Name]; exists {
+			logrus.Warnf("聊天模型 %s 重复配置，后者覆盖前者", provider.Name)
+		}
+		registerChatModel(newChatModelMeta(provider), factory)
+		logrus.Infof("注册聊天模型成功: %s (%s/%s)", provider.Name, provider.Type, provider.Model)
+	}
+	if len(chatModelRegistry) == 0 {
+		logrus.Warn("未注册任何聊天模型，AI 相关接口将不可用")
 	}
 	return chatModelRegistry, nil
 }
 
-// registerChatModel 注册聊天模型进入工厂
-func registerChatModel(name string, factory ChatModelFactory) {
-	chatModelRegistry[name] = factory
+// registerChatModel 注册聊天模型进入工厂，同时记录模型能力描述
+func registerChatModel(meta ChatModelMeta, factory ChatModelFactory) {
+	if _, exists := chatModelRegistry[meta.Name]; !exists {
+		chatModelNames = append(chatModelNames, meta.Name)
+	}
+	chatModelRegistry[meta.Name] = factory
+	chatModelMetas[meta.Name] = meta
 }
 
 func GetChatModel(ctx context.Context, name string) (model.BaseChatModel, error) {
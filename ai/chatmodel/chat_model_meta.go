@@ -0,0 +1,70 @@
+package chatmodel
+
+import (
+	"slices"
+	"time"
+
+	"aicode/config"
+	"aicode/consts"
+)
+
+// ChatModelMeta 已注册聊天模型的能力描述，供模型列表接口与权限校验使用
+type ChatModelMeta struct {
+	Name            string
+	DisplayName     string
+	Provider        consts.ChatModelProviderType
+	RespTypes       []consts.ChatRespType
+	ContextWindow   int
+	SupportToolCall bool
+	SupportVision   bool
+	AllowedRoles    []string
+	Timeout         time.Duration
+	Fallbacks       []string
+}
+
+// chatModelMetas 模型名 -> 能力描述
+var chatModelMetas = make(map[string]ChatModelMeta)
+
+// chatModelNames 按注册顺序保存模型名，保证列表输出顺序与配置一致
+var chatModelNames = make([]string, 0)
+
+// newChatModelMeta 根据供应商配置构造能力描述
+func newChatModelMeta(provider config.ChatModelProviderConfig) ChatModelMeta {
+	displayName := provider.DisplayName
+	if displayName == "" {
+		displayName = provider.Name
+	}
+	return ChatModelMeta{
+		Name:        provider.Name,
+		DisplayName: displayName,
+		Provider:    consts.ChatModelProviderType(provider.Type),
+		// 所有供应商均通过 GetGenarateRespType 同时支持非流式与流式
+		RespTypes:       []consts.ChatRespType{consts.ChatRespTypeGenerate, consts.ChatRespTypeStream},
+		ContextWindow:   provider.ContextWindow,
+		SupportToolCall: provider.SupportToolCall,
+		SupportVision:   provider.SupportVision,
+		AllowedRoles:    provider.AllowedRoles,
+		Timeout:         time.Duration(provider.TimeoutSeconds) * time.Second,
+		Fallbacks:       provider.Fallbacks,
+	}
+}
+
+// AllowRole 判断指定用户角色是否允许使用该模型，未配置 AllowedRoles 时不限制
+func (m ChatModelMeta) AllowRole(role string) bool {
+	return len(m.AllowedRoles) == 0 || slices.Contains(m.AllowedRoles, role)
+}
+
+// ListChatModelMetas 按注册顺序返回全部已注册模型的能力描述
+func ListChatModelMetas() []ChatModelMeta {
+	metas := make([]ChatModelMeta, 0, len(chatModelNames))
+	for _, name := range chatModelNames {
+		metas = append(metas, chatModelMetas[name])
+	}
+	return metas
+}
+
+// GetChatModelMeta 获取指定模型的能力描述
+func GetChatModelMeta(name string) (ChatModelMeta, bool) {
+	meta, ok := chatModelMetas[name]
+	return meta, ok
+}
@@ -0,0 +1,29 @@
+package chatmodel
+
+import (
+	"context"
+	"errors"
+
+	"aicode/config"
+
+	"github.com/cloudwego/eino-ext/components/model/ollama"
+	"github.com/cloudwego/eino/components/model"
+)
+
+// newOllamaFactory 本地部署的 Ollama，无需 API 密钥
+func newOllamaFactory(provider config.ChatModelProviderConfig) (ChatModelFactory, error) {
+	// 校验参数
+	if provider.BaseURL == "" {
+		return nil, errors.New("Ollama BaseURL 不能为空")
+	}
+	if provider.Model == "" {
+		return nil, errors.New("Ollama 模型不能为空")
+	}
+
+	return func(ctx context.Context) (model.BaseChatModel, error) {
+		return ollama.NewChatModel(ctx, &ollama.ChatModelConfig{
+			BaseURL: provider.BaseURL,
+			Model:   provider.Model,
+		})
+	}, nil
+}
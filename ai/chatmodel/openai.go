@@ -0,0 +1,30 @@
+package chatmodel
+
+import (
+	"context"
+	"errors"
+
+	"aicode/config"
+
+	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino/components/model"
+)
+
+// newOpenAIFactory OpenAI 及兼容 OpenAI 协议的接口（如各类中转、自建网关）
+func newOpenAIFactory(provider config.ChatModelProviderConfig) (ChatModelFactory, error) {
+	// 校验参数
+	if provider.APIKey == "" {
+		return nil, errors.New("OpenAI API 密钥不能为空")
+	}
+	if provider.Model == "" {
+		return nil, errors.New("OpenAI 模型不能为空")
+	}
+
+	return func(ctx context.Context) (model.BaseChatModel, error) {
+		return openai.NewChatModel(ctx, &openai.ChatModelConfig{
+			APIKey:  provider.APIKey,
+			Model:   provider.Model,
+			BaseURL: provider.BaseURL,
+		})
+	}, nil
+}
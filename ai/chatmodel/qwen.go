@@ -0,0 +1,36 @@
+package chatmodel
+
+import (
+	"context"
+	"errors"
+
+	"aicode/config"
+
+	"github.com/cloudwego/eino-ext/components/model/qwen"
+	"github.com/cloudwego/eino/components/model"
+)
+
+// qwenDefaultBaseURL 通义千问 DashScope 兼容模式接口地址
+const qwenDefaultBaseURL = "https://dashscope.aliyuncs.com/compatible-mode/v1"
+
+func newQwenFactory(provider config.ChatModelProviderConfig) (ChatModelFactory, error) {
+	// 校验参数
+	if provider.APIKey == "" {
+		return nil, errors.New("通义千问 API 密钥不能为空")
+	}
+	if provider.Model == "" {
+		return nil, errors.New("通义千问 模型不能为空")
+	}
+	baseURL := provider.BaseURL
+	if baseURL == "" {
+		baseURL = qwenDefaultBaseURL
+	}
+
+	return func(ctx context.Context) (model.BaseChatModel, error) {
+		return qwen.NewChatModel(ctx, &qwen.ChatModelConfig{
+			APIKey:  provider.APIKey,
+			Model:   provider.Model,
+			BaseURL: baseURL,
+		})
+	}, nil
+}
@@ -0,0 +1,314 @@
+package chatmodel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"aicode/config"
+	"aicode/consts"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/sirupsen/logrus"
+)
+
+// errAttemptTimeout 单次调用超过模型配置的超时时间
+var errAttemptTimeout = errors.New("模型调用超时")
+
+// transientErrorKeywords 各供应商 SDK 错误信息中代表瞬时故障的关键字（统一转小写匹配）
+var transientErrorKeywords = []string{
+	"status code: 5", "status code: 429", "status: 5", "status: 429",
+	"500 internal", "502 bad gateway", "503 service unavailable", "504 gateway",
+	"too many requests", "rate limit", "server error", "overloaded",
+	"timeout", "timed out", "connection reset", "connection refused",
+	"unexpected eof", "broken pipe",
+}
+
+// resiliencePolicy 重试策略，InitChatModel 时从配置加载
+var resiliencePolicy = retryPolicy{
+	maxRetries:     2,
+	initialBackoff: 500 * time.Millisecond,
+	maxBackoff:     5 * time.Second,
+}
+
+type retryPolicy struct {
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// ChatResult 带容错的模型调用结果
+type ChatResult struct {
+	Model   string                                // 实际给出响应的模型名（发生降级时与请求的模型不同）
+	Message *schema.Message                       // 非流式调用结果
+	Stream  *schema.StreamReader[*schema.Message] // 流式调用结果
+}
+
+// initResiliencePolicy 加载重试策略配置，未配置的项保留默认值
+func initResiliencePolicy(cfg config.ResilienceConfig) {
+	if cfg.MaxRetries > 0 {
+		resiliencePolicy.maxRetries = cfg.MaxRetries
+	}
+	if cfg.InitialBackoffMs > 0 {
+		resiliencePolicy.initialBackoff = time.Duration(cfg.InitialBackoffMs) * time.Millisecond
+	}
+	if cfg.MaxBackoffMs > 0 {
+		resiliencePolicy.maxBackoff = time.Duration(cfg.MaxBackoffMs) * time.Millisecond
+	}
+}
+
+// ResilientChat 依次尝试 name 及其降级链上的模型，单个模型遇到瞬时错误时按指数退避重试
+// allow 用于过滤降级链上当前调用方无权使用的模型，为 nil 表示不过滤
+func ResilientChat(ctx context.Context, name string, messages []*schema.Message,
+	respType consts.ChatRespType, allow func(meta ChatModelMeta) bool) (*ChatResult, error) {
+	return resilientChat(ctx, name, messages, respType, allow, nil)
+}
+
+// ResilientToolChat 与 ResilientChat 相同，但为模型绑定 tools，降级链上不支持工具调用的模型会被跳过
+func ResilientToolChat(ctx context.Context, name string, messages []*schema.Message,
+	respType consts.ChatRespType, allow func(meta ChatModelMeta) bool,
+	tools []*schema.ToolInfo) (*ChatResult, error) {
+	toolAllow := func(meta ChatModelMeta) bool {
+		return meta.SupportToolCall && (allow == nil || allow(meta))
+	}
+	return resilientChat(ctx, name, messages, respType, toolAllow, tools)
+}
+
+func resilientChat(ctx context.Context, name string, messages []*schema.Message,
+	respType consts.ChatRespType, allow func(meta ChatModelMeta) bool,
+	tools []*schema.ToolInfo) (*ChatResult, error) {
+	// 消息中包含图片时，降级链上只保留支持图片输入的模型
+	if hasImageInput(messages) {
+		baseAllow := allow
+		allow = func(meta ChatModelMeta) bool {
+			return meta.SupportVision && (baseAllow == nil || baseAllow(meta))
+		}
+	}
+	var lastErr error
+	for i, candidate := range fallbackChain(name, allow) {
+		if i > 0 {
+			logrus.Warnf("模型 %s 调用失败，降级到模型 %s: %v", name, candidate, lastErr)
+		}
+		result, err := chatWithRetry(ctx, candidate, messages, respType, tools)
+		if err == nil {
+			if candidate != name {
+				logrus.Infof("模型降级成功，请求模型: %s，实际响应模型: %s", name, candidate)
+			}
+			return result, nil
+		}
+		lastErr = err
+		// 调用方已取消请求，不再尝试后续模型
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	if lastErr == nil {
+		return nil, fmt.Errorf("不支持的模型类型: %s", name)
+	}
+	return nil, lastErr
+}
+
+// hasImageInput 判断消息中是否包含用户输入的图片
+func hasImageInput(messages []*schema.Message) bool {
+	for _, msg := range messages {
+		for _, part := range msg.UserInputMultiContent {
+			if part.Type == schema.ChatMessagePartTypeImageURL {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fallbackChain 构造调用顺序：请求的模型在前，随后是其配置的降级模型（去重且须已注册）
+func fallbackChain(name string, allow func(meta ChatModelMeta) bool) []string {
+	chain := make([]string, 0, 4)
+	seen := make(map[string]bool)
+	add := func(candidate string) {
+		meta, ok := chatModelMetas[candidate]
+		if !ok || seen[candidate] || (allow != nil && !allow(meta)) {
+			return
+		}
+		seen[candidate] = true
+		chain = append(chain, candidate)
+	}
+	add(name)
+	if meta, ok := chatModelMetas[name]; ok {
+		for _, fallback := range meta.Fallbacks {
+			add(fallback)
+		}
+	}
+	return chain
+}
+
+// chatWithRetry 调用单个模型，瞬时错误按指数退避重试
+func chatWithRetry(ctx context.Context, name string, messages []*schema.Message,
+	respType consts.ChatRespType, tools []*schema.ToolInfo) (*ChatResult, error) {
+	var err error
+	for attempt := 0; attempt <= resiliencePolicy.maxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := backoffDuration(attempt)
+			logrus.Warnf("模型 %s 第 %d 次重试，等待 %s: %v", name, attempt, backoff, err)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+
+		var result *ChatResult
+		result, err = chatOnce(ctx, name, messages, respType, tools)
+		if err == nil {
+			return result, nil
+		}
+		if ctx.Err() != nil || !isTransientError(err) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// chatOnce 单次调用模型，应用模型配置的超时时间；tools 非空时为模型绑定工具
+func chatOnce(ctx context.Context, name string, messages []*schema.Message,
+	respType consts.ChatRespType, tools []*schema.ToolInfo) (*ChatResult, error) {
+	chat, err := GetChatModel(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(tools) > 0 {
+		if chat, err = bindTools(chat, tools); err != nil {
+			return nil, fmt.Errorf("模型 %s 绑定工具失败: %w", name, err)
+		}
+	}
+	timeout := chatModelMetas[name].Timeout
+
+	if respType != consts.ChatRespTypeStream {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		message, _, err := AutoChat(ctx, chat, messages, respType)
+		if err != nil {
+			return nil, err
+		}
+		return &ChatResult{Model: name, Message: message}, nil
+	}
+
+	// 流式调用：超时仅约束首个分片的等待时间，首个分片到达后不再限制整体耗时
+	streamCtx, cancel := context.WithCancel(ctx)
+	_, stream, err := AutoChat(streamCtx, chat, messages, respType)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	stream, err = awaitFirstChunk(stream, timeout, cancel)
+	if err != nil {
+		return nil, err
+	}
+	return &ChatResult{Model: name, Stream: stream}, nil
+}
+
+// bindTools 为模型绑定工具，模型未实现 ToolCallingChatModel 时返回错误
+func bindTools(chat model.BaseChatModel, tools []*schema.ToolInfo) (model.BaseChatModel, error) {
+	toolChat, ok := chat.(model.ToolCallingChatModel)
+	if !ok {
+		return nil, errors.New("模型不支持工具调用")
+	}
+	return toolChat.WithTools(tools)
+}
+
+// awaitFirstChunk 等待流的首个分片以便尽早发现上游错误，成功后返回包含首个分片的新流
+// cancel 在新流读取结束或首个分片失败时调用，用于释放上游连接
+func awaitFirstChunk(stream *schema.StreamReader[*schema.Message],
+	timeout time.Duration, cancel context.CancelFunc) (*schema.StreamReader[*schema.Message], error) {
+	type firstChunk struct {
+		msg *schema.Message
+		err error
+	}
+	firstCh := make(chan firstChunk, 1)
+	go func() {
+		msg, err := stream.Recv()
+		firstCh <- firstChunk{msg: msg, err: err}
+	}()
+
+	var timer <-chan time.Time
+	if timeout > 0 {
+		timer = time.After(timeout)
+	}
+
+	var first firstChunk
+	select {
+	case first = <-firstCh:
+	case <-timer:
+		cancel()
+		stream.Close()
+		return nil, errAttemptTimeout
+	}
+	if first.err != nil && first.err != io.EOF {
+		cancel()
+		stream.Close()
+		return nil, first.err
+	}
+
+	reader, writer := schema.Pipe[*schema.Message](8)
+	go func() {
+		defer cancel()
+		defer stream.Close()
+		defer writer.Close()
+		if first.err == io.EOF {
+			return
+		}
+		if closed := writer.Send(first.msg, nil); closed {
+			return
+		}
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				return
+			}
+			if closed := writer.Send(msg, err); closed || err != nil {
+				return
+			}
+		}
+	}()
+	return reader, nil
+}
+
+// backoffDuration 第 attempt 次重试前的等待时间：initial * 2^(attempt-1)，叠加至多 20% 的随机抖动
+func backoffDuration(attempt int) time.Duration {
+	backoff := resiliencePolicy.initialBackoff << (attempt - 1)
+	if backoff <= 0 || backoff > resiliencePolicy.maxBackoff {
+		backoff = resiliencePolicy.maxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(backoff)/5 + 1))
+	return backoff + jitter
+}
+
+// isTransientError 判断错误是否为可重试的瞬时错误：超时、网络错误、5xx 与 429
+func isTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, errAttemptTimeout) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, keyword := range transientErrorKeywords {
+		if strings.Contains(msg, keyword) {
+			return true
+		}
+	}
+	return false
+}
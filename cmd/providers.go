@@ -1,11 +1,19 @@
 package cmd
 
 import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 
 	"aicode/ai/chatmodel"
 	"aicode/config"
+	"aicode/constant"
+	"aicode/internal/mapper"
+	"aicode/internal/router/middleware"
+	"aicode/security"
 )
 
 // ProvideConfig 提供配置
@@ -31,3 +39,71 @@ func MustProvideChatModel(cfg *config.Config) (
 	}
 	return chatModelRegistry, nil
 }
+
+// MustProvideRateLimitStore 提供限流令牌桶存储
+func MustProvideRateLimitStore(cfg *config.Config) middleware.RateLimitStore {
+	if cfg.Server.RateLimit.Store != "redis" {
+		return middleware.NewMemoryRateLimitStore()
+	}
+	return middleware.NewRedisRateLimitStore(mustNewRedisClient(cfg), "aicode:rate_limit:")
+}
+
+// MustProvideSessionMapper 提供 session 存储
+func MustProvideSessionMapper(cfg *config.Config, db *gorm.DB) mapper.SessionMapper {
+	switch cfg.Server.Session.Store {
+	case "", "memory":
+		return mapper.NewMemorySessionMapper()
+	case "mysql":
+		return mapper.NewMySQLSessionMapper(db)
+	case "redis":
+		return mapper.NewRedisSessionMapper(mustNewRedisClient(cfg), "aicode:session:")
+	default:
+		logrus.Panicf("不支持的 session 存储: %s", cfg.Server.Session.Store)
+		return nil
+	}
+}
+
+// MustProvideLoginCounterMapper 提供登录失败计数存储
+func MustProvideLoginCounterMapper(cfg *config.Config, db *gorm.DB) mapper.LoginCounterMapper {
+	switch cfg.Security.Login.Store {
+	case "", "mysql":
+		return mapper.NewMySQLLoginCounterMapper(db)
+	case "memory":
+		return mapper.NewMemoryLoginCounterMapper()
+	case "redis":
+		return mapper.NewRedisLoginCounterMapper(mustNewRedisClient(cfg), "aicode:login_counter:")
+	default:
+		logrus.Panicf("不支持的登录失败计数存储: %s", cfg.Security.Login.Store)
+		return nil
+	}
+}
+
+// mustNewRedisClient 创建 Redis 客户端并检查连接
+func mustNewRedisClient(cfg *config.Config) *redis.Client {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Addr,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		logrus.Panicf("连接 Redis 失败: %v", err)
+	}
+	return client
+}
+
+// MustProvidePasswordEncoder 提供密码编码器
+func MustProvidePasswordEncoder(cfg *config.Config) *security.PasswordEncoder {
+	passwordCfg := cfg.Security.Password
+	encoder, err := security.NewPasswordEncoder(
+		passwordCfg.Algorithm,
+		security.NewBcryptHasher(passwordCfg.BcryptCost),
+		security.NewArgon2idHasher(passwordCfg.Argon2Memory, passwordCfg.Argon2Iterations, passwordCfg.Argon2Parallelism),
+		constant.PasswordSalt,
+	)
+	if err != nil {
+		logrus.Panicf("初始化密码编码器失败: %v", err)
+	}
+	return encoder
+}
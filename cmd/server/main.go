@@ -29,6 +29,9 @@ func main() {
 	cfg = config.GetConfig()
 	applog.Init(cfg.Server.LogLevel)
 
+	// 启动异步代码生成任务调度器
+	app.CodeGenJobService.Start()
+
 	// 启动服务器
 	addr := fmt.Sprintf(":%d", cfg.Server.Port)
 	logrus.Infof("服务器启动在端口: %d, 根路径: %s", cfg.Server.Port, cfg.Server.RootPath)
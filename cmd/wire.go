@@ -8,6 +8,7 @@ import (
 	"aicode/internal/controller"
 	"aicode/internal/mapper"
 	"aicode/internal/router"
+	"aicode/internal/service"
 	"aicode/internal/service/impl"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +22,7 @@ type App struct {
 	UserController    *controller.UserController
 	HealthController  *controller.HealthController
 	AIController      *controller.AIController
+	CodeGenJobService service.CodeGenJobService
 }
 
 // wireSet 定义所有的provider集合
@@ -28,6 +30,10 @@ var wireSet = wire.NewSet(
 	MustProvideConfig,
 	MustProvideDB,
 	MustProvideChatModel,
+	MustProvideRateLimitStore,
+	MustProvideSessionMapper,
+	MustProvideLoginCounterMapper,
+	MustProvidePasswordEncoder,
 	router.SetupRouter,
 	mapper.NewUserMapper,
 	impl.NewUserService,
@@ -37,6 +43,37 @@ var wireSet = wire.NewSet(
 	impl.NewAIChatService,
 	controller.NewAICodeController,
 	impl.NewAICodeService,
+	mapper.NewAppMapper,
+	impl.NewAppService,
+	controller.NewAppController,
+	mapper.NewChatHistoryMapper,
+	impl.NewChatHistoryService,
+	controller.NewChatHistoryController,
+	mapper.NewTokenUsageMapper,
+	impl.NewTokenUsageService,
+	mapper.NewAppVersionMapper,
+	impl.NewAppVersionService,
+	controller.NewAppVersionController,
+	mapper.NewPromptTemplateMapper,
+	impl.NewPromptTemplateService,
+	controller.NewPromptTemplateController,
+	mapper.NewPromptExperimentMapper,
+	impl.NewPromptExperimentService,
+	controller.NewPromptExperimentController,
+	mapper.NewCodeGenJobMapper,
+	impl.NewCodeGenJobService,
+	controller.NewCodeGenJobController,
+	mapper.NewAuthTokenMapper,
+	impl.NewAuthTokenService,
+	mapper.NewApiKeyMapper,
+	impl.NewApiKeyService,
+	controller.NewApiKeyController,
+	impl.NewUserSessionService,
+	controller.NewUserSessionController,
+	mapper.NewRolePermissionMapper,
+	impl.NewPermissionService,
+	mapper.NewLoginAttemptMapper,
+	impl.NewLoginAttemptService,
 )
 
 // InitializeApp 初始化应用程序（此函数会被wire生成）
@@ -11,6 +11,7 @@ import (
 	"aicode/internal/controller"
 	"aicode/internal/mapper"
 	"aicode/internal/router"
+	"aicode/internal/service"
 	"aicode/internal/service/impl"
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
@@ -28,19 +29,55 @@ func InitializeApp() (*App, error) {
 	healthController := controller.NewHealthController()
 	db := MustProvideDB(config)
 	userMapper := mapper.NewUserMapper(db)
-	userService := impl.NewUserService(userMapper)
-	userController := controller.NewUserController(userService)
-	aiChatService := impl.NewAIChatService()
+	passwordEncoder := MustProvidePasswordEncoder(config)
+	loginCounterMapper := MustProvideLoginCounterMapper(config, db)
+	loginAttemptMapper := mapper.NewLoginAttemptMapper(db)
+	loginAttemptService := impl.NewLoginAttemptService(loginCounterMapper, loginAttemptMapper, userMapper)
+	userService := impl.NewUserService(userMapper, passwordEncoder, loginAttemptService)
+	tokenUsageMapper := mapper.NewTokenUsageMapper(db)
+	tokenUsageService := impl.NewTokenUsageService(tokenUsageMapper)
+	authTokenMapper := mapper.NewAuthTokenMapper(db)
+	apiKeyMapper := mapper.NewApiKeyMapper(db)
+	authTokenService := impl.NewAuthTokenService(authTokenMapper, apiKeyMapper, userMapper)
+	userController := controller.NewUserController(userService, tokenUsageService, authTokenService, loginAttemptService)
+	chatHistoryMapper := mapper.NewChatHistoryMapper(db)
+	appMapper := mapper.NewAppMapper(db)
+	appVersionMapper := mapper.NewAppVersionMapper(db)
+	appService := impl.NewAppService(appMapper, chatHistoryMapper, appVersionMapper, userService)
+	chatHistoryService := impl.NewChatHistoryService(chatHistoryMapper, appService)
+	aiChatService := impl.NewAIChatService(chatHistoryService, tokenUsageService)
 	aiController := controller.NewAIController(aiChatService)
-	aiCodeService := impl.NewAICodeService()
+	promptExperimentMapper := mapper.NewPromptExperimentMapper(db)
+	promptTemplateMapper := mapper.NewPromptTemplateMapper(db)
+	promptTemplateService := impl.NewPromptTemplateService(promptTemplateMapper)
+	promptExperimentService := impl.NewPromptExperimentService(promptExperimentMapper, promptTemplateService)
+	appVersionService := impl.NewAppVersionService(appVersionMapper, appService, promptExperimentService)
+	aiCodeService := impl.NewAICodeService(appService, chatHistoryService, tokenUsageService, appVersionService, promptTemplateService, promptExperimentService)
 	aiCodeController := controller.NewAICodeController(aiCodeService)
-	engine := router.SetupRouter(healthController, userController, aiController, aiCodeController)
+	appController := controller.NewAppController(appService)
+	chatHistoryController := controller.NewChatHistoryController(chatHistoryService)
+	appVersionController := controller.NewAppVersionController(appVersionService)
+	promptTemplateController := controller.NewPromptTemplateController(promptTemplateService)
+	promptExperimentController := controller.NewPromptExperimentController(promptExperimentService)
+	codeGenJobMapper := mapper.NewCodeGenJobMapper(db)
+	codeGenJobService := impl.NewCodeGenJobService(codeGenJobMapper, userMapper, appService, aiCodeService)
+	codeGenJobController := controller.NewCodeGenJobController(codeGenJobService)
+	apiKeyService := impl.NewApiKeyService(apiKeyMapper)
+	apiKeyController := controller.NewApiKeyController(apiKeyService)
+	sessionMapper := MustProvideSessionMapper(config, db)
+	userSessionService := impl.NewUserSessionService(sessionMapper, authTokenMapper)
+	userSessionController := controller.NewUserSessionController(userSessionService)
+	rolePermissionMapper := mapper.NewRolePermissionMapper(db)
+	permissionService := impl.NewPermissionService(rolePermissionMapper)
+	rateLimitStore := MustProvideRateLimitStore(config)
+	engine := router.SetupRouter(healthController, userController, aiController, aiCodeController, appController, chatHistoryController, appVersionController, promptTemplateController, promptExperimentController, codeGenJobController, apiKeyController, userSessionController, authTokenService, permissionService, rateLimitStore, sessionMapper)
 	app := &App{
 		ChatModelRegistry: v,
 		Router:            engine,
 		UserController:    userController,
 		HealthController:  healthController,
 		AIController:      aiController,
+		CodeGenJobService: codeGenJobService,
 	}
 	return app, nil
 }
@@ -54,11 +91,16 @@ type App struct {
 	UserController    *controller.UserController
 	HealthController  *controller.HealthController
 	AIController      *controller.AIController
+	CodeGenJobService service.CodeGenJobService
 }
 
 // wireSet 定义所有的provider集合
 var wireSet = wire.NewSet(
 	MustProvideConfig,
 	MustProvideDB,
-	MustProvideChatModel, router.SetupRouter, mapper.NewUserMapper, impl.NewUserService, controller.NewUserController, controller.NewHealthController, controller.NewAIController, impl.NewAIChatService, controller.NewAICodeController, impl.NewAICodeService,
+	MustProvideChatModel,
+	MustProvideRateLimitStore,
+	MustProvideSessionMapper,
+	MustProvideLoginCounterMapper,
+	MustProvidePasswordEncoder, router.SetupRouter, mapper.NewUserMapper, impl.NewUserService, controller.NewUserController, controller.NewHealthController, controller.NewAIController, impl.NewAIChatService, controller.NewAICodeController, impl.NewAICodeService, mapper.NewAppMapper, impl.NewAppService, controller.NewAppController, mapper.NewChatHistoryMapper, impl.NewChatHistoryService, controller.NewChatHistoryController, mapper.NewTokenUsageMapper, impl.NewTokenUsageService, mapper.NewAppVersionMapper, impl.NewAppVersionService, controller.NewAppVersionController, mapper.NewPromptTemplateMapper, impl.NewPromptTemplateService, controller.NewPromptTemplateController, mapper.NewPromptExperimentMapper, impl.NewPromptExperimentService, controller.NewPromptExperimentController, mapper.NewCodeGenJobMapper, impl.NewCodeGenJobService, controller.NewCodeGenJobController, mapper.NewAuthTokenMapper, impl.NewAuthTokenService, mapper.NewApiKeyMapper, impl.NewApiKeyService, controller.NewApiKeyController, impl.NewUserSessionService, controller.NewUserSessionController, mapper.NewRolePermissionMapper, impl.NewPermissionService, mapper.NewLoginAttemptMapper, impl.NewLoginAttemptService,
 )
@@ -1,8 +1,13 @@
 package config
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
@@ -16,17 +21,314 @@ type Config struct {
 	Database DatabaseConfig `yaml:"database"`
 	AI       AIConfig       `yaml:"ai"`
 	File     FileConfig     `yaml:"file"`
+	Redis    RedisConfig    `yaml:"redis"`
+	Security SecurityConfig `yaml:"security"`
+}
+
+// GetDeployBaseURL 获取部署访问地址前缀，未配置时使用相对路径 {root_path}/deploy
+func (c *Config) GetDeployBaseURL() string {
+	if c.File.DeployBaseURL != "" {
+		return strings.TrimSuffix(c.File.DeployBaseURL, "/")
+	}
+	return strings.TrimSuffix(c.Server.RootPath, "/") + "/deploy"
+}
+
+// SecurityConfig 安全配置
+type SecurityConfig struct {
+	Password PasswordConfig `yaml:"password"`
+	JWT      JWTConfig      `yaml:"jwt"`
+	Login    LoginConfig    `yaml:"login"`
+}
+
+// LoginConfig 登录防暴力破解配置，为 0 的参数使用默认值
+type LoginConfig struct {
+	MaxFailures      int    `yaml:"max_failures"`       // 账号连续登录失败达到该次数后锁定，默认 5
+	LockMinutes      int    `yaml:"lock_minutes"`       // 账号锁定时长，默认 15 分钟
+	WindowMinutes    int    `yaml:"window_minutes"`     // 统计失败次数的时间窗口，默认 15 分钟
+	DelayBaseSeconds int    `yaml:"delay_base_seconds"` // 失败后再次尝试的等待时间，第 n 次失败后等待 base*2^(n-1) 秒，默认 1
+	DelayMaxSeconds  int    `yaml:"delay_max_seconds"`  // 失败后等待时间的上限，默认 30 秒
+	IPMaxFailures    int    `yaml:"ip_max_failures"`    // 同一 IP 在时间窗口内登录失败达到该次数后拒绝该 IP 的登录请求，默认 50
+	Store            string `yaml:"store"`              // 失败计数存储：mysql（默认）/memory/redis，多实例部署时使用 mysql 或 redis
+}
+
+// GetMaxFailures 获取账号锁定的失败次数阈值
+func (l *LoginConfig) GetMaxFailures() int {
+	if l.MaxFailures <= 0 {
+		return 5
+	}
+	return l.MaxFailures
+}
+
+// GetLockDuration 获取账号锁定时长
+func (l *LoginConfig) GetLockDuration() time.Duration {
+	if l.LockMinutes <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(l.LockMinutes) * time.Minute
+}
+
+// GetWindow 获取统计失败次数的时间窗口
+func (l *LoginConfig) GetWindow() time.Duration {
+	if l.WindowMinutes <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(l.WindowMinutes) * time.Minute
+}
+
+// GetDelay 获取第 failures 次失败后再次尝试需要等待的时间
+func (l *LoginConfig) GetDelay(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+	base := time.Duration(l.DelayBaseSeconds) * time.Second
+	if base <= 0 {
+		base = time.Second
+	}
+	maxDelay := time.Duration(l.DelayMaxSeconds) * time.Second
+	if maxDelay <= 0 {
+		maxDelay = 30 * time.Second
+	}
+	delay := base
+	for i := 1; i < failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// GetIPMaxFailures 获取单个 IP 的失败次数阈值
+func (l *LoginConfig) GetIPMaxFailures() int {
+	if l.IPMaxFailures <= 0 {
+		return 50
+	}
+	return l.IPMaxFailures
+}
+
+// JWTConfig 令牌认证配置
+type JWTConfig struct {
+	Secret           string `yaml:"secret"`             // HS256 签名密钥，未配置时使用 server.session_secret，两者均未配置时使用进程内随机密钥
+	Issuer           string `yaml:"issuer"`             // 签发者，默认 aicode
+	AccessTTLMinutes int    `yaml:"access_ttl_minutes"` // access 令牌有效期，默认 15 分钟
+	RefreshTTLHours  int    `yaml:"refresh_ttl_hours"`  // refresh 令牌（即令牌会话）有效期，默认 168 小时
+}
+
+// randomJWTSecret 未配置签名密钥时使用的进程内随机密钥
+var (
+	randomJWTSecretOnce sync.Once
+	randomJWTSecret     string
+)
+
+// placeholderSecrets 示例配置与文档中常见的占位密钥，公开可知，不能用于签名令牌
+var placeholderSecrets = []string{"change_me", "changeme", "change-me", "secret", "your_secret", "default_session_secret"}
+
+// CheckSecret 校验签名密钥，security.jwt.secret 或作为其后备的 server.session_secret 为公开的占位值时返回错误
+func (j *JWTConfig) CheckSecret(sessionSecret string) error {
+	for _, secret := range []string{j.Secret, sessionSecret} {
+		if secret == "" {
+			continue
+		}
+		for _, placeholder := range placeholderSecrets {
+			if strings.EqualFold(strings.TrimSpace(secret), placeholder) {
+				return fmt.Errorf("JWT 签名密钥使用了公开的占位值 %q，请为 security.jwt.secret 配置随机生成的密钥", secret)
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// GetSecret 获取签名密钥；security.jwt.secret 与 server.session_secret 均未配置时使用进程内随机生成的密钥，
+// 此时令牌在服务重启后失效，且多实例之间不通用；密钥为公开的占位值时拒绝签发与校验令牌
+func (j *JWTConfig) GetSecret(sessionSecret string) string {
+	if err := j.CheckSecret(sessionSecret); err != nil {
+		logrus.Panic(err)
+	}
+	if j.Secret != "" {
+		return j.Secret
+	}
+	if sessionSecret != "" {
+		return sessionSecret
+	}
+	randomJWTSecretOnce.Do(func() {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			logrus.Panicf("生成 JWT 签名密钥失败: %v", err)
+		}
+		randomJWTSecret = hex.EncodeToString(key)
+		logrus.Warn("未配置 security.jwt.secret 与 server.session_secret，使用随机生成的 JWT 签名密钥，" +
+			"令牌在服务重启后失效且多实例之间不通用，生产环境请配置固定密钥")
+	})
+	return randomJWTSecret
+}
+
+// GetIssuer 获取签发者
+func (j *JWTConfig) GetIssuer() string {
+	if j.Issuer == "" {
+		return "aicode"
+	}
+	return j.Issuer
+}
+
+// GetAccessTTL 获取 access 令牌有效期
+func (j *JWTConfig) GetAccessTTL() time.Duration {
+	if j.AccessTTLMinutes <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(j.AccessTTLMinutes) * time.Minute
+}
+
+// GetRefreshTTL 获取 refresh 令牌有效期
+func (j *JWTConfig) GetRefreshTTL() time.Duration {
+	if j.RefreshTTLHours <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return time.Duration(j.RefreshTTLHours) * time.Hour
+}
+
+// PasswordConfig 密码哈希配置，为 0 的参数使用默认值；调整算法或参数后，存量密码在用户下次登录成功时自动重新生成
+type PasswordConfig struct {
+	Algorithm         string `yaml:"algorithm"`          // 哈希算法：bcrypt（默认）/argon2id
+	BcryptCost        int    `yaml:"bcrypt_cost"`        // bcrypt 计算强度，默认 10
+	Argon2Memory      uint32 `yaml:"argon2_memory"`      // argon2id 内存开销（KiB），默认 65536
+	Argon2Iterations  uint32 `yaml:"argon2_iterations"`  // argon2id 迭代次数，默认 3
+	Argon2Parallelism uint8  `yaml:"argon2_parallelism"` // argon2id 并行度，默认 2
+}
+
+// RedisConfig Redis 配置
+type RedisConfig struct {
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
 }
 
 // FileConfig 文件存储配置
 type FileConfig struct {
 	StoreBasePath string `yaml:"store_base_path"`
+	DeployBaseURL string `yaml:"deploy_base_url"` // 部署访问地址前缀，如 http://localhost:8080/api/v1/deploy
 }
 
 // AIConfig 人工智能配置
 type AIConfig struct {
-	DeepSeek        DeepSeekConfig        `yaml:"deepseek"`
-	SystemPromptDir SystemPromptDirConfig `yaml:"system_prompt_dir"`
+	DeepSeek        DeepSeekConfig              `yaml:"deepseek"` // 兼容旧配置，等价于一个名为 deepseek 的供应商
+	Providers       []ChatModelProviderConfig   `yaml:"providers"`
+	Resilience      ResilienceConfig            `yaml:"resilience"`
+	SystemPromptDir SystemPromptDirConfig       `yaml:"system_prompt_dir"`
+	HistoryMaxTurns int                         `yaml:"history_max_turns"` // 服务端回填的最大历史轮数（一问一答为一轮）
+	Quota           map[string]TokenQuotaConfig `yaml:"quota"`             // 按用户角色（user/admin）配置的 token 配额
+	JobQueue        JobQueueConfig              `yaml:"job_queue"`         // 异步代码生成任务队列
+}
+
+// JobQueueConfig 异步代码生成任务队列配置，未配置的项使用默认值
+type JobQueueConfig struct {
+	Workers                 int            `yaml:"workers"`                   // 每个实例同时执行的任务总数上限，默认 4
+	DefaultModelConcurrency int            `yaml:"default_model_concurrency"` // 每个实例单个模型同时执行的任务数上限，默认 2
+	ModelConcurrency        map[string]int `yaml:"model_concurrency"`         // 按模型名单独配置的并发上限
+	QueueSize               int            `yaml:"queue_size"`                // 所有实例共享的排队中任务数上限，默认 100
+	TimeoutSeconds          int            `yaml:"timeout_seconds"`           // 单个任务的执行超时，默认 600 秒
+}
+
+// GetWorkers 获取任务总并发上限
+func (q *JobQueueConfig) GetWorkers() int {
+	if q.Workers <= 0 {
+		return 4
+	}
+	return q.Workers
+}
+
+// GetModelConcurrency 获取指定模型的并发上限
+func (q *JobQueueConfig) GetModelConcurrency(model string) int {
+	if n := q.ModelConcurrency[model]; n > 0 {
+		return n
+	}
+	if q.DefaultModelConcurrency <= 0 {
+		return 2
+	}
+	return q.DefaultModelConcurrency
+}
+
+// GetQueueSize 获取排队任务数上限
+func (q *JobQueueConfig) GetQueueSize() int {
+	if q.QueueSize <= 0 {
+		return 100
+	}
+	return q.QueueSize
+}
+
+// GetTimeout 获取单个任务的执行超时
+func (q *JobQueueConfig) GetTimeout() time.Duration {
+	if q.TimeoutSeconds <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(q.TimeoutSeconds) * time.Second
+}
+
+// TokenQuotaConfig token 配额配置，0 表示不限制
+type TokenQuotaConfig struct {
+	DailyTokens   int64 `yaml:"daily_tokens"`   // 每日 token 上限
+	MonthlyTokens int64 `yaml:"monthly_tokens"` // 每月 token 上限
+}
+
+// ChatModelProviderConfig 聊天模型供应商配置
+type ChatModelProviderConfig struct {
+	Name    string `yaml:"name"`     // 模型注册名，即请求中 model 字段的取值
+	Type    string `yaml:"type"`     // 供应商类型：deepseek/openai/ollama/qwen/ark
+	Enabled bool   `yaml:"enabled"`  // 是否启用
+	APIKey  string `yaml:"api_key"`  // API 密钥（ollama 无需配置）
+	BaseURL string `yaml:"base_url"` // 接口地址（openai 兼容接口、ollama 必填）
+	Model   string `yaml:"model"`    // 供应商侧的模型名称或 ark 接入点 id
+
+	DisplayName     string   `yaml:"display_name"`      // 展示名称，为空时使用 name
+	ContextWindow   int      `yaml:"context_window"`    // 上下文窗口大小（token），0 表示未知
+	SupportToolCall bool     `yaml:"support_tool_call"` // 是否支持工具调用
+	SupportVision   bool     `yaml:"support_vision"`    // 是否支持图片输入
+	AllowedRoles    []string `yaml:"allowed_roles"`     // 允许使用的用户角色，为空表示不限制
+	TimeoutSeconds  int      `yaml:"timeout_seconds"`   // 单次调用超时（流式为首个分片的等待超时），0 表示不限制
+	Fallbacks       []string `yaml:"fallbacks"`         // 调用失败时依次降级的模型名
+}
+
+// ResilienceConfig 模型调用容错配置
+type ResilienceConfig struct {
+	MaxRetries       int `yaml:"max_retries"`        // 单个模型遇到瞬时错误时的最大重试次数（不含首次调用）
+	InitialBackoffMs int `yaml:"initial_backoff_ms"` // 首次重试前的等待时间，之后按指数翻倍
+	MaxBackoffMs     int `yaml:"max_backoff_ms"`     // 重试等待时间上限
+}
+
+// GetProviders 获取全部聊天模型供应商配置
+// 旧版 deepseek 配置块在填写了 api_key 且未与 providers 重名时，转换为名为 deepseek 的供应商
+func (a *AIConfig) GetProviders() []ChatModelProviderConfig {
+	providers := make([]ChatModelProviderConfig, 0, len(a.Providers)+1)
+	if a.DeepSeek.APIKey != "" {
+		legacy := true
+		for _, p := range a.Providers {
+			if p.Name == "deepseek" {
+				legacy = false
+				break
+			}
+		}
+		if legacy {
+			providers = append(providers, ChatModelProviderConfig{
+				Name:    "deepseek",
+				Type:    "deepseek",
+				Enabled: true,
+				APIKey:  a.DeepSeek.APIKey,
+				BaseURL: a.DeepSeek.BaseURL,
+				Model:   a.DeepSeek.Model,
+			})
+		}
+	}
+	return append(providers, a.Providers...)
+}
+
+// GetHistoryMaxTurns 获取历史轮数上限，未配置时默认 10 轮
+func (a *AIConfig) GetHistoryMaxTurns() int {
+	if a.HistoryMaxTurns <= 0 {
+		return 10
+	}
+	return a.HistoryMaxTurns
+}
+
+// GetQuota 获取指定角色的 token 配额，未配置时不限制
+func (a *AIConfig) GetQuota(role string) TokenQuotaConfig {
+	return a.Quota[role]
 }
 
 // DeepSeekConfig 深度求索配置
@@ -40,14 +342,56 @@ type DeepSeekConfig struct {
 type SystemPromptDirConfig struct {
 	SingalGenerate string `yaml:"singal_generate"`
 	MultiGenerate  string `yaml:"multi_generate"`
+	VueProject     string `yaml:"vue_project"`
+	Agent          string `yaml:"agent"`
+	Edit           string `yaml:"edit"`
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Port          int    `yaml:"port"`
-	RootPath      string `yaml:"root_path"`
-	LogLevel      string `yaml:"log_level"`
-	SessionSecret string `yaml:"session_secret"`
+	Port           int             `yaml:"port"`
+	RootPath       string          `yaml:"root_path"`
+	LogLevel       string          `yaml:"log_level"`
+	SessionSecret  string          `yaml:"session_secret"`
+	TrustedProxies []string        `yaml:"trusted_proxies"` // 可信反向代理的 IP 或 CIDR，只采信其转发的 X-Forwarded-For；为空时按连接的对端地址取客户端 IP
+	RateLimit      RateLimitConfig `yaml:"rate_limit"`
+	Session        SessionConfig   `yaml:"session"`
+}
+
+// SessionConfig 服务端 session 配置
+type SessionConfig struct {
+	Store              string `yaml:"store"`                // session 存储：memory（默认）/mysql/redis，多实例部署时使用 mysql 或 redis
+	TTLHours           int    `yaml:"ttl_hours"`            // 自登录起的最长有效期，默认 168 小时
+	IdleTimeoutMinutes int    `yaml:"idle_timeout_minutes"` // 超过该时长没有请求即失效，默认 1440 分钟
+}
+
+// GetTTL 获取 session 最长有效期
+func (s *SessionConfig) GetTTL() time.Duration {
+	if s.TTLHours <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return time.Duration(s.TTLHours) * time.Hour
+}
+
+// GetIdleTimeout 获取 session 空闲超时
+func (s *SessionConfig) GetIdleTimeout() time.Duration {
+	if s.IdleTimeoutMinutes <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(s.IdleTimeoutMinutes) * time.Minute
+}
+
+// RateLimitConfig 限流配置
+type RateLimitConfig struct {
+	Enabled bool                     `yaml:"enabled"`
+	Store   string                   `yaml:"store"`  // 令牌桶存储：memory（默认）/redis
+	Groups  map[string]RateLimitRule `yaml:"groups"` // 按路由组配置，key 为路由组名（如 ai_code、ai_chat）
+}
+
+// RateLimitRule 令牌桶规则
+type RateLimitRule struct {
+	Rate  float64 `yaml:"rate"`  // 每秒补充的令牌数
+	Burst int     `yaml:"burst"` // 桶容量，即允许的突发请求数
 }
 
 // DatabaseConfig 数据库配置
@@ -81,6 +425,10 @@ func LoadConfig(configPath string) *Config {
 		logrus.Panicf("解析配置文件失败: %s", err.Error())
 	}
 
+	if err := cfg.Security.JWT.CheckSecret(cfg.Server.SessionSecret); err != nil {
+		logrus.Panic(err)
+	}
+
 	globalConfig = &cfg
 	logrus.Infof("加载配置文件成功: %s", configPath)
 	return &cfg
@@ -0,0 +1,40 @@
+package constant
+
+const (
+	// TokenTypeAccess access 令牌，用于访问接口
+	TokenTypeAccess = "access"
+
+	// TokenTypeRefresh refresh 令牌，只能用于换取新的令牌
+	TokenTypeRefresh = "refresh"
+)
+
+const (
+	// ApiKeyPrefix API Key 明文前缀，用于与 JWT 区分
+	ApiKeyPrefix = "ak_"
+
+	// ApiKeyMaxPerUser 每个用户可持有的未吊销 API Key 数量上限
+	ApiKeyMaxPerUser = 20
+
+	// ApiKeyScopeAll 授权全部路由组（API Key 管理接口除外）
+	ApiKeyScopeAll = "*"
+
+	// ApiKeyScopeReadSuffix 只读授权后缀，如 app:read 只允许 GET/HEAD 请求
+	ApiKeyScopeReadSuffix = ":read"
+
+	// ApiKeyRouteGroup API Key 管理接口所在的路由组，不允许通过 API Key 访问
+	ApiKeyRouteGroup = "api_key"
+)
+
+// ApiKeyScopeGroups 可授权给 API Key 的路由组，与路由注册时的分组名一致
+var ApiKeyScopeGroups = []string{
+	"user", "ai_chat", "ai_code", "code_gen_job", "app", "chat_history", "app_version",
+	"prompt_template", "prompt_experiment", "user_session",
+}
+
+const (
+	// PermissionSessionManage 查询并强制失效用户的登录 session
+	PermissionSessionManage = "session:manage"
+
+	// PermissionPromptManage 管理系统提示词模板与提示词实验
+	PermissionPromptManage = "prompt:manage"
+)
@@ -0,0 +1,15 @@
+package constant
+
+const (
+	// LoginAttemptFailed 登录记录：登录失败，计入失败次数
+	LoginAttemptFailed = 0
+
+	// LoginAttemptSuccess 登录记录：登录成功，之前的失败次数清零
+	LoginAttemptSuccess = 1
+
+	// LoginAttemptBlocked 登录记录：账号锁定或尝试过于频繁，未校验密码即拒绝
+	LoginAttemptBlocked = 2
+
+	// LoginAttemptUnlocked 登录记录：管理员解锁账号，之前的失败次数清零
+	LoginAttemptUnlocked = 3
+)
@@ -0,0 +1,20 @@
+package constant
+
+const (
+	// PromptExperimentStopped 提示词实验已停止
+	PromptExperimentStopped = 0
+
+	// PromptExperimentRunning 提示词实验进行中
+	PromptExperimentRunning = 1
+)
+
+const (
+	// ExperimentRecordPending 实验记录：生成进行中
+	ExperimentRecordPending = 0
+
+	// ExperimentRecordSuccess 实验记录：代码存储成功
+	ExperimentRecordSuccess = 1
+
+	// ExperimentRecordFailed 实验记录：代码存储失败
+	ExperimentRecordFailed = 2
+)
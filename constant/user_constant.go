@@ -13,6 +13,6 @@ const (
 	// DefaultPassword 默认密码
 	DefaultPassword = "12345678"
 
-	// PasswordSalt 密码加密盐值
+	// PasswordSalt 旧版 MD5 密码的盐值，仅用于校验升级前注册的账号
 	PasswordSalt = "yumi123"
 )
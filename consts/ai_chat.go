@@ -6,6 +6,17 @@ const (
 	ChatModelTypeDeepSeek ChatModelType = "deepseek"
 )
 
+// ChatModelProviderType 聊天模型供应商类型，对应配置 ai.providers[].type
+type ChatModelProviderType string
+
+const (
+	ChatModelProviderDeepSeek ChatModelProviderType = "deepseek"
+	ChatModelProviderOpenAI   ChatModelProviderType = "openai"
+	ChatModelProviderOllama   ChatModelProviderType = "ollama"
+	ChatModelProviderQwen     ChatModelProviderType = "qwen"
+	ChatModelProviderArk      ChatModelProviderType = "ark"
+)
+
 type ChatRole string
 
 const (
@@ -21,9 +32,67 @@ const (
 	ChatRespTypeStream   ChatRespType = "stream"
 )
 
+// UsageBizType token 用量的业务类型
+type UsageBizType string
+
+const (
+	UsageBizTypeChat UsageBizType = "chat"
+	UsageBizTypeCode UsageBizType = "code"
+)
+
 type CodeGenarateType string
 
 const (
 	CodeGenarateTypeSingle CodeGenarateType = "single"
 	CodeGenarateTypeMulti  CodeGenarateType = "multi"
+	// CodeGenarateTypeVueProject Vue 工程模式，输出任意文件树
+	CodeGenarateTypeVueProject CodeGenarateType = "vue_project"
+	// CodeGenarateTypeAgent 工具调用模式，模型通过文件工具直接读写应用目录
+	CodeGenarateTypeAgent CodeGenarateType = "agent"
+	// CodeGenarateTypeEdit 增量修改模式，基于应用当前代码输出 SEARCH/REPLACE 或 unified diff 补丁；
+	// 仅作为单次请求的生成类型，不能作为应用的代码生成类型
+	CodeGenarateTypeEdit CodeGenarateType = "edit"
+)
+
+// 代码生成请求附带图片的限制
+const (
+	CodeImageMaxCount = 4       // 单次请求最多附带的图片数
+	CodeImageMaxBytes = 5 << 20 // 单张图片解码后的最大字节数
+)
+
+// CodeStreamEvent 代码生成 SSE 事件类型，增量文本沿用默认的 message 事件
+type CodeStreamEvent string
+
+const (
+	CodeStreamEventToolCall   CodeStreamEvent = "tool_call"
+	CodeStreamEventToolResult CodeStreamEvent = "tool_result"
+	// 增量解析出的文件事件
+	CodeStreamEventFileStarted   CodeStreamEvent = "file_started"
+	CodeStreamEventFileChunk     CodeStreamEvent = "file_chunk"
+	CodeStreamEventFileCompleted CodeStreamEvent = "file_completed"
+	// CodeStreamEventCancelled 任务被取消
+	CodeStreamEventCancelled CodeStreamEvent = "cancelled"
 )
+
+// CodeJobStatus 代码生成任务状态，流式任务与异步任务共用
+type CodeJobStatus string
+
+const (
+	// CodeJobStatusQueued 排队中，仅异步任务使用
+	CodeJobStatusQueued    CodeJobStatus = "queued"
+	CodeJobStatusRunning   CodeJobStatus = "running"
+	CodeJobStatusSucceeded CodeJobStatus = "succeeded"
+	CodeJobStatusFailed    CodeJobStatus = "failed"
+	CodeJobStatusCancelled CodeJobStatus = "cancelled"
+)
+
+// IsValidCodeGenarateType 判断代码生成类型是否受支持
+func IsValidCodeGenarateType(genType CodeGenarateType) bool {
+	switch genType {
+	case CodeGenarateTypeSingle, CodeGenarateTypeMulti, CodeGenarateTypeVueProject,
+		CodeGenarateTypeAgent:
+		return true
+	default:
+		return false
+	}
+}
@@ -1,4 +1,4 @@
-// Package docs Code generated by swaggo/swag at 2026-03-06 23:26:20.9764701 +0800 CST m=+2.014554301. DO NOT EDIT
+// Package docs Code generated by swaggo/swag at 2026-10-18 10:45:26.588199224 +0000 UTC m=+5.761533072. DO NOT EDIT
 package docs
 
 import "github.com/swaggo/swag"
@@ -43,7 +43,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-string"
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_AIChatResponse"
                         }
                     }
                 }
@@ -83,9 +83,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/ai_code/gen": {
+        "/ai_chat/models": {
+            "get": {
+                "description": "列出已注册的聊天模型、支持的响应类型、上下文窗口、工具调用能力及当前用户是否可用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai_chat模块"
+                ],
+                "summary": "可用模型列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_ChatModelVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/ai_code/cancel": {
             "post": {
-                "description": "代码生成",
+                "description": "取消运行中的流式生成任务，中止上游模型调用，任务以 cancelled 事件结束",
                 "consumes": [
                     "application/json"
                 ],
@@ -95,10 +118,45 @@ const docTemplate = `{
                 "tags": [
                     "ai_code模块"
                 ],
+                "summary": "取消代码生成任务",
+                "parameters": [
+                    {
+                        "description": "取消任务请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_vo.CodeJobCancelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
+                        }
+                    }
+                }
+            }
+        },
+        "/ai_code/gen": {
+            "post": {
+                "description": "代码生成；可附带设计稿等图片（JSON 的 images 字段或 multipart 的 images 文件字段），仅支持图片输入的模型可用",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai_code模块"
+                ],
                 "summary": "代码生成",
                 "parameters": [
                     {
-                        "description": "代码生成请求",
+                        "description": "代码生成请求；multipart 表单时以同名字段提交，图片以 images 文件字段上传",
                         "name": "request",
                         "in": "body",
                         "required": true,
@@ -107,6 +165,47 @@ const docTemplate = `{
                         }
                     }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_AICodeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ai_code/gen/resume": {
+            "get": {
+                "description": "断线后重新订阅流式生成任务，从 Last-Event-ID 请求头（或 lastEventId 参数）之后的事件开始推送；任务结束后事件保留 10 分钟",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai_code模块"
+                ],
+                "summary": "续传代码生成任务",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "任务id",
+                        "name": "jobId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "已收到的最后一个事件id",
+                        "name": "lastEventId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "已收到的最后一个事件id，优先于 lastEventId 参数",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -119,9 +218,10 @@ const docTemplate = `{
         },
         "/ai_code/gen/stream": {
             "post": {
-                "description": "代码生成流式",
+                "description": "代码生成流式；生成以后台任务运行，客户端断开不影响生成，start 事件与 X-Job-Id 响应头携带任务id。每个事件带有递增的 SSE id，断线后可通过 /ai_code/gen/resume 续传。文件之外的文本通过 message 事件推送，解析出的文件通过 file_started、file_chunk、file_completed 事件推送，genType 为 agent 时推送 tool_call、tool_result 事件，任务被取消时推送 cancelled 事件",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
@@ -132,7 +232,7 @@ const docTemplate = `{
                 "summary": "代码生成流式",
                 "parameters": [
                     {
-                        "description": "代码生成请求",
+                        "description": "代码生成请求；multipart 表单时以同名字段提交，图片以 images 文件字段上传",
                         "name": "request",
                         "in": "body",
                         "required": true,
@@ -151,9 +251,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/health/": {
+        "/api_key/create": {
+            "post": {
+                "description": "创建长期有效的 API Key，通过请求头 Authorization: Bearer {key} 访问授权范围内的路由组；密钥明文只在本次返回",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Key 模块"
+                ],
+                "summary": "创建个人 API Key",
+                "parameters": [
+                    {
+                        "description": "API Key 创建请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_apikey.ApiKeyCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_ApiKeyCreatedVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/api_key/list": {
             "get": {
-                "description": "检查服务是否正常运行",
+                "description": "按创建时间倒序查询当前用户未吊销的 API Key，不包含密钥",
                 "consumes": [
                     "application/json"
                 ],
@@ -161,22 +295,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "健康检查"
+                    "API Key 模块"
                 ],
-                "summary": "健康检查",
+                "summary": "查询个人 API Key 列表",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-string"
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_ApiKeyVO"
                         }
                     }
                 }
             }
         },
-        "/user/add": {
+        "/api_key/revoke": {
             "post": {
-                "description": "管理员创建用户接口",
+                "description": "吊销后使用该 API Key 的请求立即失效",
                 "consumes": [
                     "application/json"
                 ],
@@ -184,17 +318,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "API Key 模块"
                 ],
-                "summary": "创建用户",
+                "summary": "吊销个人 API Key",
                 "parameters": [
                     {
-                        "description": "用户创建请求",
+                        "description": "API Key 吊销请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_model_dto_user.UserAddRequest"
+                            "$ref": "#/definitions/aicode_internal_model_dto_apikey.ApiKeyRevokeRequest"
                         }
                     }
                 ],
@@ -202,15 +336,15 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-int64"
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
                         }
                     }
                 }
             }
         },
-        "/user/delete": {
+        "/app/add": {
             "post": {
-                "description": "管理员删除用户接口",
+                "description": "创建应用，归属于当前登录用户",
                 "consumes": [
                     "application/json"
                 ],
@@ -218,17 +352,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "应用模块"
                 ],
-                "summary": "删除用户",
+                "summary": "创建应用",
                 "parameters": [
                     {
-                        "description": "删除请求",
+                        "description": "应用创建请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_common.DeleteRequest"
+                            "$ref": "#/definitions/aicode_internal_model_dto_app.AppAddRequest"
                         }
                     }
                 ],
@@ -236,15 +370,15 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-int64"
                         }
                     }
                 }
             }
         },
-        "/user/get": {
-            "get": {
-                "description": "管理员根据ID获取用户详细信息",
+        "/app/delete": {
+            "post": {
+                "description": "删除应用（仅本人或管理员）",
                 "consumes": [
                     "application/json"
                 ],
@@ -252,31 +386,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "应用模块"
                 ],
-                "summary": "根据ID获取用户",
+                "summary": "删除应用",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "用户ID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "删除请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.DeleteRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_entity_User"
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
                         }
                     }
                 }
             }
         },
-        "/user/get/login": {
-            "get": {
-                "description": "获取当前登录用户信息",
+        "/app/deploy": {
+            "post": {
+                "description": "将应用已生成的代码部署为静态站点（仅本人），返回访问地址；重复部署沿用原地址",
                 "consumes": [
                     "application/json"
                 ],
@@ -284,22 +420,68 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "应用模块"
+                ],
+                "summary": "部署应用",
+                "parameters": [
+                    {
+                        "description": "应用部署请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_app.AppDeployRequest"
+                        }
+                    }
                 ],
-                "summary": "获取当前登录用户",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_LoginUserVO"
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-string"
                         }
                     }
                 }
             }
         },
-        "/user/get/vo": {
+        "/app/download": {
             "get": {
-                "description": "根据ID获取脱敏后的用户信息",
+                "description": "将应用当前代码或指定版本打包为 zip 下载（仅本人），不包含隐藏的内部文件",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "应用模块"
+                ],
+                "summary": "下载应用代码",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "appId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "版本号，为空时下载当前代码",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "zip 压缩包",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/app/get/vo": {
+            "get": {
+                "description": "根据ID获取应用详情（仅本人或管理员）",
                 "consumes": [
                     "application/json"
                 ],
@@ -307,13 +489,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "应用模块"
                 ],
-                "summary": "根据ID获取用户VO",
+                "summary": "根据ID获取应用",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "用户ID",
+                        "description": "应用ID",
                         "name": "id",
                         "in": "query",
                         "required": true
@@ -323,15 +505,15 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_UserVO"
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_AppVO"
                         }
                     }
                 }
             }
         },
-        "/user/list/page/vo": {
+        "/app/list/page/vo": {
             "post": {
-                "description": "管理员分页查询用户列表",
+                "description": "分页查询应用列表，普通用户仅能查看自己的应用",
                 "consumes": [
                     "application/json"
                 ],
@@ -339,17 +521,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "应用模块"
                 ],
-                "summary": "分页获取用户列表",
+                "summary": "分页获取应用列表",
                 "parameters": [
                     {
-                        "description": "用户查询请求",
+                        "description": "应用查询请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_model_dto_user.UserQueryRequest"
+                            "$ref": "#/definitions/aicode_internal_model_dto_app.AppQueryRequest"
                         }
                     }
                 ],
@@ -363,9 +545,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/login": {
+        "/app/update": {
             "post": {
-                "description": "用户登录接口",
+                "description": "更新应用信息（仅本人或管理员）",
                 "consumes": [
                     "application/json"
                 ],
@@ -373,17 +555,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "应用模块"
                 ],
-                "summary": "用户登录",
+                "summary": "更新应用",
                 "parameters": [
                     {
-                        "description": "用户登录请求",
+                        "description": "应用更新请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_model_dto_user.UserLoginRequest"
+                            "$ref": "#/definitions/aicode_internal_model_dto_app.AppUpdateRequest"
                         }
                     }
                 ],
@@ -391,15 +573,15 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_LoginUserVO"
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
                         }
                     }
                 }
             }
         },
-        "/user/logout": {
-            "post": {
-                "description": "用户注销登录",
+        "/app_version/diff": {
+            "get": {
+                "description": "比较应用的两个版本，返回各变更文件的 unified diff（仅本人）",
                 "consumes": [
                     "application/json"
                 ],
@@ -407,22 +589,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "应用版本模块"
+                ],
+                "summary": "查询版本差异",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "appId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "起始版本号",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "目标版本号",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "用户注销",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_AppVersionDiffVO"
                         }
                     }
                 }
             }
         },
-        "/user/register": {
-            "post": {
-                "description": "用户注册接口",
+        "/app_version/list": {
+            "get": {
+                "description": "按版本号倒序列出应用的全部代码版本（仅本人）",
                 "consumes": [
                     "application/json"
                 ],
@@ -430,33 +635,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "应用版本模块"
                 ],
-                "summary": "用户注册",
+                "summary": "查询应用版本列表",
                 "parameters": [
                     {
-                        "description": "用户注册请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/aicode_internal_model_dto_user.UserRegisterRequest"
-                        }
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "appId",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-int64"
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_AppVersionVO"
                         }
                     }
                 }
             }
         },
-        "/user/update": {
+        "/app_version/rollback": {
             "post": {
-                "description": "管理员更新用户信息",
+                "description": "将应用代码恢复为指定版本，回滚结果记录为一个新版本，返回新版本号（仅本人）",
                 "consumes": [
                     "application/json"
                 ],
@@ -464,17 +667,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户模块"
+                    "应用版本模块"
                 ],
-                "summary": "更新用户",
+                "summary": "回滚应用版本",
                 "parameters": [
                     {
-                        "description": "用户更新请求",
+                        "description": "版本回滚请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_model_dto_user.UserUpdateRequest"
+                            "$ref": "#/definitions/aicode_internal_model_dto_appversion.AppVersionRollbackRequest"
                         }
                     }
                 ],
@@ -482,415 +685,2817 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-int"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "aicode_internal_common.BaseResponse-aicode_internal_common_MapResponse": {
-            "type": "object",
-            "properties": {
+        },
+        "/chat_history/list": {
+            "get": {
+                "description": "游标分页查询对话历史，按时间倒序返回；appId 与 conversationId 二选一",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "对话历史模块"
+                ],
+                "summary": "查询对话历史",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用ID",
+                        "name": "appId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "会话ID",
+                        "name": "conversationId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "游标（上一页最后一条消息的 id）",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页面大小",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_ChatHistoryCursorPageVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/code_gen_job/cancel": {
+            "post": {
+                "description": "取消排队中或执行中的任务，执行中的任务会中断模型调用，不写入应用代码；在其他服务实例执行的任务由该实例数秒内取消",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "异步代码生成模块"
+                ],
+                "summary": "取消异步代码生成任务",
+                "parameters": [
+                    {
+                        "description": "任务取消请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_codegenjob.CodeGenJobCancelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
+                        }
+                    }
+                }
+            }
+        },
+        "/code_gen_job/get": {
+            "get": {
+                "description": "查询任务状态、排队位置与结果，只能查询本人的任务（管理员除外）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "异步代码生成模块"
+                ],
+                "summary": "查询异步代码生成任务",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "任务id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_CodeGenJobVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/code_gen_job/list": {
+            "get": {
+                "description": "按提交时间倒序查询当前用户在应用下最近的任务，不包含输出内容",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "异步代码生成模块"
+                ],
+                "summary": "查询应用的异步代码生成任务列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "应用id",
+                        "name": "appId",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_CodeGenJobVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/code_gen_job/submit": {
+            "post": {
+                "description": "提交后立即返回任务id，任务按提交顺序在全局与单模型并发上限内执行，结果通过查询接口获取；暂不支持图片输入",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "异步代码生成模块"
+                ],
+                "summary": "提交异步代码生成任务",
+                "parameters": [
+                    {
+                        "description": "代码生成请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_vo.AICodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-int64"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/": {
+            "get": {
+                "description": "检查服务是否正常运行",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "健康检查"
+                ],
+                "summary": "健康检查",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-string"
+                        }
+                    }
+                }
+            }
+        },
+        "/prompt_experiment/create": {
+            "post": {
+                "description": "对同一模板的多个版本按权重分流（仅管理员），同一用户始终分到同一组；同一模板同一时刻只能有一个进行中的实验",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "提示词实验模块"
+                ],
+                "summary": "创建提示词实验",
+                "parameters": [
+                    {
+                        "description": "实验创建请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_promptexperiment.PromptExperimentCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-int64"
+                        }
+                    }
+                }
+            }
+        },
+        "/prompt_experiment/list": {
+            "get": {
+                "description": "按创建时间倒序查询全部实验（仅管理员）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "提示词实验模块"
+                ],
+                "summary": "查询提示词实验列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_PromptExperimentVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/prompt_experiment/stats": {
+            "get": {
+                "description": "按分组汇总实验的存储成功率、续写次数与回滚率（仅管理员）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "提示词实验模块"
+                ],
+                "summary": "查询提示词实验结果",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "实验id",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_PromptExperimentStatsVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/prompt_experiment/stop": {
+            "post": {
+                "description": "停止实验（仅管理员），停止后模板恢复使用生效版本，已记录的结果保留",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "提示词实验模块"
+                ],
+                "summary": "停止提示词实验",
+                "parameters": [
+                    {
+                        "description": "实验停止请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_promptexperiment.PromptExperimentStopRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
+                        }
+                    }
+                }
+            }
+        },
+        "/prompt_template/activate": {
+            "post": {
+                "description": "将模板的指定版本设为生效版本（仅管理员），可用于回滚",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "提示词模板模块"
+                ],
+                "summary": "切换模板生效版本",
+                "parameters": [
+                    {
+                        "description": "版本生效请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_prompttemplate.PromptTemplateActivateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
+                        }
+                    }
+                }
+            }
+        },
+        "/prompt_template/add": {
+            "post": {
+                "description": "创建提示词模板（仅管理员），模板使用 Go text/template 语法，可引用 {{.GenType}} {{.Model}} {{.AppName}} 等变量",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "提示词模板模块"
+                ],
+                "summary": "创建提示词模板",
+                "parameters": [
+                    {
+                        "description": "模板创建请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_prompttemplate.PromptTemplateAddRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-int"
+                        }
+                    }
+                }
+            }
+        },
+        "/prompt_template/delete": {
+            "post": {
+                "description": "删除模板的全部版本（仅管理员），删除后使用该模板的代码生成请求将失败",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "提示词模板模块"
+                ],
+                "summary": "删除提示词模板",
+                "parameters": [
+                    {
+                        "description": "模板删除请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_prompttemplate.PromptTemplateDeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
+                        }
+                    }
+                }
+            }
+        },
+        "/prompt_template/get": {
+            "get": {
+                "description": "查询模板的指定版本，未指定版本时查询生效版本（仅管理员）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "提示词模板模块"
+                ],
+                "summary": "查询提示词模板",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "模板名称",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "版本号",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_PromptTemplateVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/prompt_template/list": {
+            "get": {
+                "description": "查询全部模板的生效版本（仅管理员）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "提示词模板模块"
+                ],
+                "summary": "查询提示词模板列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_PromptTemplateVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/prompt_template/update": {
+            "post": {
+                "description": "将修改保存为模板的新版本并立即生效（仅管理员），返回新版本号",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "提示词模板模块"
+                ],
+                "summary": "修改提示词模板",
+                "parameters": [
+                    {
+                        "description": "模板修改请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_prompttemplate.PromptTemplateUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-int"
+                        }
+                    }
+                }
+            }
+        },
+        "/prompt_template/versions": {
+            "get": {
+                "description": "按版本号倒序查询模板的全部版本（仅管理员）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "提示词模板模块"
+                ],
+                "summary": "查询模板版本列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "模板名称",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_PromptTemplateVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/add": {
+            "post": {
+                "description": "管理员创建用户接口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "创建用户",
+                "parameters": [
+                    {
+                        "description": "用户创建请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_user.UserAddRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-int64"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/delete": {
+            "post": {
+                "description": "管理员删除用户接口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "删除用户",
+                "parameters": [
+                    {
+                        "description": "删除请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.DeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/get": {
+            "get": {
+                "description": "管理员根据ID获取用户详细信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "根据ID获取用户",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_entity_User"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/get/login": {
+            "get": {
+                "description": "获取当前登录用户信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "获取当前登录用户",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_LoginUserVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/get/vo": {
+            "get": {
+                "description": "根据ID获取脱敏后的用户信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "根据ID获取用户VO",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_UserVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/list/page/vo": {
+            "post": {
+                "description": "管理员分页查询用户列表",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "分页获取用户列表",
+                "parameters": [
+                    {
+                        "description": "用户查询请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_user.UserQueryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_common_MapResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login": {
+            "post": {
+                "description": "用户登录接口，同时写入 cookie session 并签发 access/refresh 令牌；连续登录失败后需等待逐次翻倍的时间（错误码 42900），达到阈值后账号被锁定（错误码 42300）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "用户登录",
+                "parameters": [
+                    {
+                        "description": "用户登录请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_user.UserLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_LoginUserVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login_attempt/list": {
+            "get": {
+                "description": "按时间倒序查询用户账号最近 100 条登录记录，包括失败、被拒绝与解锁记录（仅管理员）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "查询用户的登录记录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_LoginAttemptVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/logout": {
+            "post": {
+                "description": "用户注销登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "用户注销",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/register": {
+            "post": {
+                "description": "用户注册接口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "用户注册",
+                "parameters": [
+                    {
+                        "description": "用户注册请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_user.UserRegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-int64"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/token/refresh": {
+            "post": {
+                "description": "使用 refresh 令牌换取新的 access/refresh 令牌，旧的 refresh 令牌随即失效；无需登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "刷新令牌",
+                "parameters": [
+                    {
+                        "description": "令牌刷新请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_user.TokenRefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_AuthTokenVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/token/revoke": {
+            "post": {
+                "description": "吊销 refresh 令牌所属的令牌会话，会话内的 access/refresh 令牌全部失效；无需登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "吊销令牌",
+                "parameters": [
+                    {
+                        "description": "令牌吊销请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_user.TokenRevokeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/unlock": {
+            "post": {
+                "description": "解除因连续登录失败导致的账号锁定，并将连续失败次数清零（仅管理员）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "解锁账号",
+                "parameters": [
+                    {
+                        "description": "解锁账号请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_user.UserUnlockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/update": {
+            "post": {
+                "description": "管理员更新用户信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "更新用户",
+                "parameters": [
+                    {
+                        "description": "用户更新请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_user.UserUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-bool"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/usage": {
+            "get": {
+                "description": "获取当前登录用户今日、本月的 token 用量、配额及按模型汇总的明细",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户模块"
+                ],
+                "summary": "获取当前用户的 token 用量",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-aicode_internal_model_vo_TokenUsageSummaryVO"
+                        }
+                    }
+                }
+            }
+        },
+        "/user_session/expire": {
+            "post": {
+                "description": "失效指定 session；不指定时失效该用户的全部 session 并吊销全部令牌会话（仅管理员），返回失效的 session 数量",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户 session 模块"
+                ],
+                "summary": "强制失效用户的登录 session",
+                "parameters": [
+                    {
+                        "description": "session 失效请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_model_dto_usersession.UserSessionExpireRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-int64"
+                        }
+                    }
+                }
+            }
+        },
+        "/user_session/list": {
+            "get": {
+                "description": "按最近访问时间倒序查询用户未过期的登录 session（仅管理员）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户 session 模块"
+                ],
+                "summary": "查询用户的登录 session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户id",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_UserSessionVO"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "aicode_internal_common.BaseResponse-aicode_internal_common_MapResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_common.MapResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-aicode_internal_model_entity_User": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_model_entity.User"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_AIChatResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_model_vo.AIChatResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_AICodeResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_model_vo.AICodeResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_ApiKeyCreatedVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_model_vo.ApiKeyCreatedVO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_AppVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_model_vo.AppVO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_AppVersionDiffVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_model_vo.AppVersionDiffVO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_AuthTokenVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_model_vo.AuthTokenVO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_ChatHistoryCursorPageVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_model_vo.ChatHistoryCursorPageVO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_CodeGenJobVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_model_vo.CodeGenJobVO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_LoginUserVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_model_vo.LoginUserVO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_PromptExperimentStatsVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_model_vo.PromptExperimentStatsVO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_PromptTemplateVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_model_vo.PromptTemplateVO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_TokenUsageSummaryVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_model_vo.TokenUsageSummaryVO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_UserVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/aicode_internal_model_vo.UserVO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_ApiKeyVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aicode_internal_model_vo.ApiKeyVO"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_AppVersionVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aicode_internal_model_vo.AppVersionVO"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_ChatModelVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aicode_internal_model_vo.ChatModelVO"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_CodeGenJobVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aicode_internal_model_vo.CodeGenJobVO"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_LoginAttemptVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aicode_internal_model_vo.LoginAttemptVO"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_PromptExperimentVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aicode_internal_model_vo.PromptExperimentVO"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_PromptTemplateVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aicode_internal_model_vo.PromptTemplateVO"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-array_aicode_internal_model_vo_UserSessionVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aicode_internal_model_vo.UserSessionVO"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-bool": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-int": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-int64": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.BaseResponse-string": {
+            "type": "object",
+            "properties": {
                 "code": {
                     "type": "integer"
                 },
-                "data": {
-                    "$ref": "#/definitions/aicode_internal_common.MapResponse"
+                "data": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_common.DeleteRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "aicode_internal_common.MapResponse": {
+            "type": "object"
+        },
+        "aicode_internal_model_dto_apikey.ApiKeyCreateRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expireDays": {
+                    "description": "有效天数，0 表示永不过期",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "description": "名称",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "scopes": {
+                    "description": "授权范围：* 或路由组名，路由组名加 :read 后缀表示只读",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "aicode_internal_model_dto_apikey.ApiKeyRevokeRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "API Key id",
+                    "type": "integer"
+                }
+            }
+        },
+        "aicode_internal_model_dto_app.AppAddRequest": {
+            "type": "object",
+            "required": [
+                "initPrompt"
+            ],
+            "properties": {
+                "appName": {
+                    "description": "应用名称（为空时取初始化 prompt 前缀）",
+                    "type": "string"
+                },
+                "codeGenType": {
+                    "description": "代码生成类型：single/multi/vue_project/agent",
+                    "type": "string"
+                },
+                "cover": {
+                    "description": "应用封面",
+                    "type": "string"
+                },
+                "initPrompt": {
+                    "description": "应用初始化的 prompt",
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_model_dto_app.AppDeployRequest": {
+            "type": "object",
+            "required": [
+                "appId"
+            ],
+            "properties": {
+                "appId": {
+                    "description": "应用id",
+                    "type": "integer"
+                }
+            }
+        },
+        "aicode_internal_model_dto_app.AppQueryRequest": {
+            "type": "object",
+            "properties": {
+                "appName": {
+                    "description": "应用名称",
+                    "type": "string"
+                },
+                "codeGenType": {
+                    "description": "代码生成类型",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "id",
+                    "type": "integer"
+                },
+                "pageNum": {
+                    "description": "当前页号",
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "description": "页面大小",
+                    "type": "integer"
+                },
+                "sortField": {
+                    "description": "排序字段",
+                    "type": "string"
+                },
+                "sortOrder": {
+                    "description": "排序顺序（默认降序）",
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "创建用户id（仅管理员可按用户筛选）",
+                    "type": "integer"
+                }
+            }
+        },
+        "aicode_internal_model_dto_app.AppUpdateRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "appName": {
+                    "description": "应用名称",
+                    "type": "string"
+                },
+                "cover": {
+                    "description": "应用封面",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "id",
+                    "type": "integer"
+                },
+                "priority": {
+                    "description": "优先级（仅管理员可修改）",
+                    "type": "integer"
+                }
+            }
+        },
+        "aicode_internal_model_dto_appversion.AppVersionRollbackRequest": {
+            "type": "object",
+            "required": [
+                "appId",
+                "version"
+            ],
+            "properties": {
+                "appId": {
+                    "description": "应用id",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "回滚到的版本号",
+                    "type": "integer"
+                }
+            }
+        },
+        "aicode_internal_model_dto_codegenjob.CodeGenJobCancelRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "任务id",
+                    "type": "integer"
+                }
+            }
+        },
+        "aicode_internal_model_dto_promptexperiment.PromptExperimentCreateRequest": {
+            "type": "object",
+            "required": [
+                "templateName",
+                "variants"
+            ],
+            "properties": {
+                "description": {
+                    "description": "实验说明",
+                    "type": "string"
+                },
+                "templateName": {
+                    "description": "参与实验的模板名称",
+                    "type": "string"
+                },
+                "variants": {
+                    "description": "实验分组，至少两组",
+                    "type": "array",
+                    "maxItems": 10,
+                    "minItems": 2,
+                    "items": {
+                        "$ref": "#/definitions/aicode_internal_model_dto_promptexperiment.PromptExperimentVariantRequest"
+                    }
+                }
+            }
+        },
+        "aicode_internal_model_dto_promptexperiment.PromptExperimentStopRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "实验id",
+                    "type": "integer"
+                }
+            }
+        },
+        "aicode_internal_model_dto_promptexperiment.PromptExperimentVariantRequest": {
+            "type": "object",
+            "required": [
+                "version",
+                "weight"
+            ],
+            "properties": {
+                "version": {
+                    "description": "模板版本号",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "weight": {
+                    "description": "分流权重",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "aicode_internal_model_dto_prompttemplate.PromptTemplateActivateRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "version"
+            ],
+            "properties": {
+                "name": {
+                    "description": "模板名称",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "版本号",
+                    "type": "integer"
+                }
+            }
+        },
+        "aicode_internal_model_dto_prompttemplate.PromptTemplateAddRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "name"
+            ],
+            "properties": {
+                "content": {
+                    "description": "模板内容（Go text/template 语法）",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "版本说明",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "模板名称，代码生成模板与生成类型同名",
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_model_dto_prompttemplate.PromptTemplateDeleteRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "模板名称",
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_model_dto_prompttemplate.PromptTemplateUpdateRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "name"
+            ],
+            "properties": {
+                "content": {
+                    "description": "模板内容",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "版本说明",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "模板名称",
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_model_dto_user.TokenRefreshRequest": {
+            "type": "object",
+            "required": [
+                "refreshToken"
+            ],
+            "properties": {
+                "refreshToken": {
+                    "description": "refresh 令牌",
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_model_dto_user.TokenRevokeRequest": {
+            "type": "object",
+            "required": [
+                "refreshToken"
+            ],
+            "properties": {
+                "refreshToken": {
+                    "description": "要吊销的令牌会话的 refresh 令牌",
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_model_dto_user.UserAddRequest": {
+            "type": "object",
+            "required": [
+                "userAccount"
+            ],
+            "properties": {
+                "userAccount": {
+                    "description": "账号",
+                    "type": "string"
+                },
+                "userAvatar": {
+                    "description": "用户头像",
+                    "type": "string"
+                },
+                "userName": {
+                    "description": "用户昵称",
+                    "type": "string"
+                },
+                "userProfile": {
+                    "description": "用户简介",
+                    "type": "string"
+                },
+                "userRole": {
+                    "description": "用户角色: user, admin",
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_model_dto_user.UserLoginRequest": {
+            "type": "object",
+            "required": [
+                "userAccount",
+                "userPassword"
+            ],
+            "properties": {
+                "userAccount": {
+                    "description": "账号",
+                    "type": "string"
+                },
+                "userPassword": {
+                    "description": "密码",
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_model_dto_user.UserQueryRequest": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "id",
+                    "type": "integer"
+                },
+                "pageNum": {
+                    "description": "当前页号",
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "description": "页面大小",
+                    "type": "integer"
+                },
+                "sortField": {
+                    "description": "排序字段",
+                    "type": "string"
+                },
+                "sortOrder": {
+                    "description": "排序顺序（默认降序）",
+                    "type": "string"
+                },
+                "userAccount": {
+                    "description": "账号",
+                    "type": "string"
+                },
+                "userName": {
+                    "description": "用户昵称",
+                    "type": "string"
+                },
+                "userProfile": {
+                    "description": "简介",
+                    "type": "string"
+                },
+                "userRole": {
+                    "description": "用户角色：user/admin/ban",
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_model_dto_user.UserRegisterRequest": {
+            "type": "object",
+            "required": [
+                "checkPassword",
+                "userAccount",
+                "userPassword"
+            ],
+            "properties": {
+                "checkPassword": {
+                    "description": "确认密码",
+                    "type": "string"
+                },
+                "userAccount": {
+                    "description": "账号",
+                    "type": "string"
+                },
+                "userPassword": {
+                    "description": "密码",
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_model_dto_user.UserUnlockRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "用户id",
+                    "type": "integer"
+                }
+            }
+        },
+        "aicode_internal_model_dto_user.UserUpdateRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "id",
+                    "type": "integer"
+                },
+                "userAvatar": {
+                    "description": "用户头像",
+                    "type": "string"
+                },
+                "userName": {
+                    "description": "用户昵称",
+                    "type": "string"
+                },
+                "userProfile": {
+                    "description": "简介",
+                    "type": "string"
+                },
+                "userRole": {
+                    "description": "用户角色：user/admin",
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_model_dto_usersession.UserSessionExpireRequest": {
+            "type": "object",
+            "required": [
+                "userId"
+            ],
+            "properties": {
+                "sessionId": {
+                    "description": "要失效的 session id，为空时失效该用户的全部 session 与令牌会话",
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "用户id",
+                    "type": "integer"
+                }
+            }
+        },
+        "aicode_internal_model_entity.User": {
+            "type": "object",
+            "properties": {
+                "createTime": {
+                    "type": "string"
+                },
+                "editTime": {
+                    "type": "string"
                 },
-                "message": {
+                "id": {
+                    "type": "integer"
+                },
+                "isDelete": {
+                    "type": "integer"
+                },
+                "updateTime": {
+                    "type": "string"
+                },
+                "userAccount": {
+                    "type": "string"
+                },
+                "userAvatar": {
+                    "type": "string"
+                },
+                "userName": {
+                    "type": "string"
+                },
+                "userPassword": {
+                    "type": "string"
+                },
+                "userProfile": {
+                    "type": "string"
+                },
+                "userRole": {
                     "type": "string"
                 }
             }
         },
-        "aicode_internal_common.BaseResponse-aicode_internal_model_entity_User": {
+        "aicode_internal_model_vo.AIChatRequest": {
             "type": "object",
+            "required": [
+                "conversationId",
+                "model",
+                "question"
+            ],
             "properties": {
-                "code": {
-                    "type": "integer"
+                "conversationId": {
+                    "type": "string",
+                    "maxLength": 64
                 },
-                "data": {
-                    "$ref": "#/definitions/aicode_internal_model_entity.User"
+                "model": {
+                    "$ref": "#/definitions/consts.ChatModelType"
                 },
-                "message": {
+                "question": {
                     "type": "string"
                 }
             }
         },
-        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_LoginUserVO": {
+        "aicode_internal_model_vo.AIChatResponse": {
             "type": "object",
             "properties": {
-                "code": {
+                "answer": {
+                    "type": "string"
+                },
+                "model": {
+                    "description": "实际响应的模型，发生降级时与请求的模型不同",
+                    "type": "string"
+                },
+                "question": {
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_model_vo.AICodeRequest": {
+            "type": "object",
+            "required": [
+                "appId",
+                "genType",
+                "model",
+                "question"
+            ],
+            "properties": {
+                "appId": {
                     "type": "integer"
                 },
-                "data": {
-                    "$ref": "#/definitions/aicode_internal_model_vo.LoginUserVO"
+                "genType": {
+                    "$ref": "#/definitions/consts.CodeGenarateType"
                 },
-                "message": {
+                "images": {
+                    "description": "设计稿等参考图片，仅支持图片输入的模型可用",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "model": {
+                    "$ref": "#/definitions/consts.ChatModelType"
+                },
+                "question": {
                     "type": "string"
                 }
             }
         },
-        "aicode_internal_common.BaseResponse-aicode_internal_model_vo_UserVO": {
+        "aicode_internal_model_vo.AICodeResponse": {
             "type": "object",
             "properties": {
-                "code": {
+                "content": {
+                    "description": "模型输出的原始内容",
+                    "type": "string"
+                },
+                "model": {
+                    "description": "实际响应的模型，发生降级时与请求的模型不同",
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_model_vo.ApiKeyCreatedVO": {
+            "type": "object",
+            "properties": {
+                "createTime": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "expireTime": {
+                    "description": "过期时间，为空表示永不过期",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "id",
                     "type": "integer"
                 },
-                "data": {
-                    "$ref": "#/definitions/aicode_internal_model_vo.UserVO"
+                "key": {
+                    "description": "密钥明文",
+                    "type": "string"
                 },
-                "message": {
+                "keyPrefix": {
+                    "description": "密钥前缀，用于辨认",
                     "type": "string"
+                },
+                "lastUsedTime": {
+                    "description": "最近一次使用时间",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "名称",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "授权范围",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "aicode_internal_common.BaseResponse-bool": {
+        "aicode_internal_model_vo.ApiKeyVO": {
             "type": "object",
             "properties": {
-                "code": {
+                "createTime": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "expireTime": {
+                    "description": "过期时间，为空表示永不过期",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "id",
                     "type": "integer"
                 },
-                "data": {
-                    "type": "boolean"
+                "keyPrefix": {
+                    "description": "密钥前缀，用于辨认",
+                    "type": "string"
                 },
-                "message": {
+                "lastUsedTime": {
+                    "description": "最近一次使用时间",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "名称",
                     "type": "string"
+                },
+                "scopes": {
+                    "description": "授权范围",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "aicode_internal_common.BaseResponse-int64": {
+        "aicode_internal_model_vo.AppVO": {
             "type": "object",
             "properties": {
-                "code": {
+                "appName": {
+                    "description": "应用名称",
+                    "type": "string"
+                },
+                "codeGenType": {
+                    "description": "代码生成类型",
+                    "type": "string"
+                },
+                "cover": {
+                    "description": "应用封面",
+                    "type": "string"
+                },
+                "createTime": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "deployKey": {
+                    "description": "部署标识，未部署时为空",
+                    "type": "string"
+                },
+                "deployUrl": {
+                    "description": "部署访问地址，未部署时为空",
+                    "type": "string"
+                },
+                "deployedTime": {
+                    "description": "最近一次部署时间",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "id",
                     "type": "integer"
                 },
-                "data": {
+                "initPrompt": {
+                    "description": "应用初始化的 prompt",
+                    "type": "string"
+                },
+                "priority": {
+                    "description": "优先级",
                     "type": "integer"
                 },
-                "message": {
+                "updateTime": {
+                    "description": "更新时间",
                     "type": "string"
+                },
+                "user": {
+                    "description": "创建用户信息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/aicode_internal_model_vo.UserVO"
+                        }
+                    ]
+                },
+                "userId": {
+                    "description": "创建用户id",
+                    "type": "integer"
                 }
             }
         },
-        "aicode_internal_common.BaseResponse-string": {
+        "aicode_internal_model_vo.AppVersionDiffVO": {
             "type": "object",
             "properties": {
-                "code": {
+                "files": {
+                    "description": "变更文件列表，未变更的文件不返回",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aicode_internal_model_vo.AppVersionFileDiffVO"
+                    }
+                },
+                "from": {
+                    "description": "起始版本号",
                     "type": "integer"
                 },
-                "data": {
+                "to": {
+                    "description": "目标版本号",
+                    "type": "integer"
+                }
+            }
+        },
+        "aicode_internal_model_vo.AppVersionFileDiffVO": {
+            "type": "object",
+            "properties": {
+                "binary": {
+                    "description": "是否为二进制或过大的文件，此时不返回 diff",
+                    "type": "boolean"
+                },
+                "diff": {
+                    "description": "unified diff 文本",
                     "type": "string"
                 },
-                "message": {
+                "path": {
+                    "description": "相对于应用目录的路径",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "变更类型：added/removed/modified",
                     "type": "string"
                 }
             }
         },
-        "aicode_internal_common.DeleteRequest": {
+        "aicode_internal_model_vo.AppVersionVO": {
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
-                "id": {
+                "createTime": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "genType": {
+                    "description": "代码生成类型",
+                    "type": "string"
+                },
+                "model": {
+                    "description": "实际响应的模型名",
+                    "type": "string"
+                },
+                "prompt": {
+                    "description": "生成该版本的用户 prompt",
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "创建用户id",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "版本号",
                     "type": "integer"
                 }
             }
         },
-        "aicode_internal_common.MapResponse": {
-            "type": "object"
-        },
-        "aicode_internal_model_dto_user.UserAddRequest": {
+        "aicode_internal_model_vo.AuthTokenVO": {
             "type": "object",
-            "required": [
-                "userAccount"
-            ],
             "properties": {
-                "userAccount": {
-                    "description": "账号",
+                "accessToken": {
+                    "description": "access 令牌，通过请求头 Authorization: Bearer {accessToken} 访问接口",
                     "type": "string"
                 },
-                "userAvatar": {
-                    "description": "用户头像",
+                "expiresIn": {
+                    "description": "access 令牌有效期（秒）",
+                    "type": "integer"
+                },
+                "refreshExpiresAt": {
+                    "description": "refresh 令牌过期时间",
                     "type": "string"
                 },
-                "userName": {
-                    "description": "用户昵称",
+                "refreshToken": {
+                    "description": "refresh 令牌，只能用于换取新的令牌，每次刷新后旧令牌失效",
+                    "type": "string"
+                },
+                "tokenType": {
+                    "description": "令牌类型，固定为 Bearer",
+                    "type": "string"
+                }
+            }
+        },
+        "aicode_internal_model_vo.ChatHistoryCursorPageVO": {
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "description": "是否还有更早的消息",
+                    "type": "boolean"
+                },
+                "nextCursor": {
+                    "description": "下一页游标，hasMore 为 false 时无意义",
+                    "type": "integer"
+                },
+                "records": {
+                    "description": "消息列表",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aicode_internal_model_vo.ChatHistoryVO"
+                    }
+                }
+            }
+        },
+        "aicode_internal_model_vo.ChatHistoryVO": {
+            "type": "object",
+            "properties": {
+                "appId": {
+                    "description": "应用id",
+                    "type": "integer"
+                },
+                "conversationId": {
+                    "description": "会话id",
                     "type": "string"
                 },
-                "userProfile": {
-                    "description": "用户简介",
+                "createTime": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "id",
+                    "type": "integer"
+                },
+                "message": {
+                    "description": "消息内容",
                     "type": "string"
                 },
-                "userRole": {
-                    "description": "用户角色: user, admin",
+                "messageType": {
+                    "description": "消息类型：user/assistant",
                     "type": "string"
                 }
             }
         },
-        "aicode_internal_model_dto_user.UserLoginRequest": {
+        "aicode_internal_model_vo.ChatModelVO": {
             "type": "object",
-            "required": [
-                "userAccount",
-                "userPassword"
-            ],
             "properties": {
-                "userAccount": {
-                    "description": "账号",
+                "allowed": {
+                    "description": "当前用户角色是否允许使用",
+                    "type": "boolean"
+                },
+                "contextWindow": {
+                    "description": "上下文窗口大小（token），0 表示未知",
+                    "type": "integer"
+                },
+                "displayName": {
+                    "description": "展示名称",
                     "type": "string"
                 },
-                "userPassword": {
-                    "description": "密码",
+                "name": {
+                    "description": "模型名，即请求中 model 字段的取值",
+                    "type": "string"
+                },
+                "provider": {
+                    "description": "供应商类型",
                     "type": "string"
+                },
+                "respTypes": {
+                    "description": "支持的响应类型：generate/stream",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/consts.ChatRespType"
+                    }
+                },
+                "supportToolCall": {
+                    "description": "是否支持工具调用",
+                    "type": "boolean"
+                },
+                "supportVision": {
+                    "description": "是否支持图片输入",
+                    "type": "boolean"
                 }
             }
         },
-        "aicode_internal_model_dto_user.UserQueryRequest": {
+        "aicode_internal_model_vo.CodeGenJobVO": {
             "type": "object",
             "properties": {
-                "id": {
-                    "description": "id",
+                "appId": {
+                    "description": "应用id",
                     "type": "integer"
                 },
-                "pageNum": {
-                    "description": "当前页号",
-                    "type": "integer"
+                "content": {
+                    "description": "模型输出的原始内容，仅查询单个任务时返回",
+                    "type": "string"
                 },
-                "pageSize": {
-                    "description": "页面大小",
-                    "type": "integer"
+                "createTime": {
+                    "description": "提交时间",
+                    "type": "string"
                 },
-                "sortField": {
-                    "description": "排序字段",
+                "errorMsg": {
+                    "description": "失败原因",
                     "type": "string"
                 },
-                "sortOrder": {
-                    "description": "排序顺序（默认降序）",
+                "finishTime": {
+                    "description": "结束时间",
                     "type": "string"
                 },
-                "userAccount": {
-                    "description": "账号",
+                "genType": {
+                    "description": "代码生成类型",
                     "type": "string"
                 },
-                "userName": {
-                    "description": "用户昵称",
+                "id": {
+                    "description": "任务id",
+                    "type": "integer"
+                },
+                "model": {
+                    "description": "请求的模型名",
                     "type": "string"
                 },
-                "userProfile": {
-                    "description": "简介",
+                "question": {
+                    "description": "用户提问",
                     "type": "string"
                 },
-                "userRole": {
-                    "description": "用户角色：user/admin/ban",
+                "queuePosition": {
+                    "description": "排队位置，从 1 开始，不在排队时为 0",
+                    "type": "integer"
+                },
+                "resultModel": {
+                    "description": "实际响应的模型，发生降级时与请求的模型不同",
+                    "type": "string"
+                },
+                "startTime": {
+                    "description": "开始执行时间",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "状态：queued/running/succeeded/failed/cancelled",
                     "type": "string"
                 }
             }
         },
-        "aicode_internal_model_dto_user.UserRegisterRequest": {
+        "aicode_internal_model_vo.CodeJobCancelRequest": {
             "type": "object",
             "required": [
-                "checkPassword",
-                "userAccount",
-                "userPassword"
+                "jobId"
             ],
             "properties": {
-                "checkPassword": {
-                    "description": "确认密码",
-                    "type": "string"
-                },
-                "userAccount": {
-                    "description": "账号",
-                    "type": "string"
-                },
-                "userPassword": {
-                    "description": "密码",
+                "jobId": {
+                    "description": "任务id",
                     "type": "string"
                 }
             }
         },
-        "aicode_internal_model_dto_user.UserUpdateRequest": {
+        "aicode_internal_model_vo.LoginAttemptVO": {
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
+                "clientIp": {
+                    "description": "客户端 IP",
+                    "type": "string"
+                },
+                "createTime": {
+                    "description": "时间",
+                    "type": "string"
+                },
                 "id": {
                     "description": "id",
                     "type": "integer"
                 },
-                "userAvatar": {
-                    "description": "用户头像",
-                    "type": "string"
+                "operatorId": {
+                    "description": "解锁操作的管理员id",
+                    "type": "integer"
                 },
-                "userName": {
-                    "description": "用户昵称",
+                "reason": {
+                    "description": "失败或拒绝原因",
                     "type": "string"
                 },
-                "userProfile": {
-                    "description": "简介",
+                "result": {
+                    "description": "结果：0-失败，1-成功，2-被拒绝，3-管理员解锁",
+                    "type": "integer"
+                },
+                "userAccount": {
+                    "description": "登录时提交的账号",
                     "type": "string"
                 },
-                "userRole": {
-                    "description": "用户角色：user/admin",
+                "userAgent": {
+                    "description": "客户端 User-Agent",
                     "type": "string"
                 }
             }
         },
-        "aicode_internal_model_entity.User": {
+        "aicode_internal_model_vo.LoginUserVO": {
             "type": "object",
             "properties": {
                 "createTime": {
-                    "type": "string"
-                },
-                "editTime": {
+                    "description": "创建时间",
                     "type": "string"
                 },
                 "id": {
+                    "description": "用户 id",
                     "type": "integer"
                 },
-                "isDelete": {
-                    "type": "integer"
+                "token": {
+                    "description": "登录时签发的令牌，供不使用 cookie 的客户端调用",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/aicode_internal_model_vo.AuthTokenVO"
+                        }
+                    ]
                 },
                 "updateTime": {
+                    "description": "更新时间",
                     "type": "string"
                 },
                 "userAccount": {
+                    "description": "账号",
                     "type": "string"
                 },
                 "userAvatar": {
+                    "description": "用户头像",
                     "type": "string"
                 },
                 "userName": {
-                    "type": "string"
-                },
-                "userPassword": {
+                    "description": "用户昵称",
                     "type": "string"
                 },
                 "userProfile": {
+                    "description": "用户简介",
                     "type": "string"
                 },
                 "userRole": {
+                    "description": "用户角色：user/admin",
                     "type": "string"
                 }
             }
         },
-        "aicode_internal_model_vo.AIChatMessage": {
+        "aicode_internal_model_vo.ModelUsageVO": {
             "type": "object",
             "properties": {
-                "content": {
+                "completionTokens": {
+                    "description": "输出 token 数",
+                    "type": "integer"
+                },
+                "model": {
+                    "description": "模型名",
                     "type": "string"
                 },
-                "role": {
-                    "description": "\"user\" 或 \"assistant\"",
+                "promptTokens": {
+                    "description": "输入 token 数",
+                    "type": "integer"
+                },
+                "totalTokens": {
+                    "description": "总 token 数",
+                    "type": "integer"
+                }
+            }
+        },
+        "aicode_internal_model_vo.PromptExperimentStatsVO": {
+            "type": "object",
+            "properties": {
+                "experiment": {
+                    "description": "实验信息",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/consts.ChatRole"
+                            "$ref": "#/definitions/aicode_internal_model_vo.PromptExperimentVO"
                         }
                     ]
+                },
+                "variants": {
+                    "description": "各分组结果，按实验分组顺序排列",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aicode_internal_model_vo.PromptVariantStatsVO"
+                    }
                 }
             }
         },
-        "aicode_internal_model_vo.AIChatRequest": {
+        "aicode_internal_model_vo.PromptExperimentVO": {
             "type": "object",
-            "required": [
-                "model",
-                "question"
-            ],
             "properties": {
-                "history": {
+                "createTime": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "实验说明",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "实验id",
+                    "type": "integer"
+                },
+                "running": {
+                    "description": "是否进行中",
+                    "type": "boolean"
+                },
+                "templateName": {
+                    "description": "参与实验的模板名称",
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "创建用户id",
+                    "type": "integer"
+                },
+                "variants": {
+                    "description": "实验分组",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/aicode_internal_model_vo.AIChatMessage"
+                        "$ref": "#/definitions/aicode_internal_model_vo.PromptExperimentVariantVO"
                     }
+                }
+            }
+        },
+        "aicode_internal_model_vo.PromptExperimentVariantVO": {
+            "type": "object",
+            "properties": {
+                "version": {
+                    "description": "模板版本号",
+                    "type": "integer"
                 },
-                "model": {
-                    "$ref": "#/definitions/consts.ChatModelType"
-                },
-                "question": {
-                    "type": "string"
+                "weight": {
+                    "description": "分流权重",
+                    "type": "integer"
                 }
             }
         },
-        "aicode_internal_model_vo.AICodeMessage": {
+        "aicode_internal_model_vo.PromptTemplateVO": {
             "type": "object",
             "properties": {
                 "content": {
+                    "description": "模板内容",
                     "type": "string"
                 },
-                "role": {
-                    "description": "\"user\" 或 \"assistant\"",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/consts.ChatRole"
-                        }
-                    ]
+                "createTime": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "版本说明",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "id",
+                    "type": "integer"
+                },
+                "isActive": {
+                    "description": "是否为生效版本",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "模板名称",
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "创建用户id",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "版本号",
+                    "type": "integer"
                 }
             }
         },
-        "aicode_internal_model_vo.AICodeRequest": {
+        "aicode_internal_model_vo.PromptVariantStatsVO": {
             "type": "object",
-            "required": [
-                "appId",
-                "genType",
-                "model",
-                "question"
-            ],
             "properties": {
-                "appId": {
-                    "type": "string"
+                "avgRetries": {
+                    "description": "平均每次生成的续写次数",
+                    "type": "number"
                 },
-                "genType": {
-                    "$ref": "#/definitions/consts.CodeGenarateType"
+                "failed": {
+                    "description": "代码存储失败次数（含解析失败、生成中断）",
+                    "type": "integer"
                 },
-                "history": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/aicode_internal_model_vo.AICodeMessage"
-                    }
+                "retries": {
+                    "description": "输出被截断后请求续写的总次数",
+                    "type": "integer"
                 },
-                "model": {
-                    "$ref": "#/definitions/consts.ChatModelType"
+                "rollbackRate": {
+                    "description": "回滚率，按存储成功的生成计算",
+                    "type": "number"
                 },
-                "question": {
-                    "type": "string"
+                "rolledBack": {
+                    "description": "生成的版本被用户回滚的次数",
+                    "type": "integer"
+                },
+                "success": {
+                    "description": "代码存储成功次数",
+                    "type": "integer"
+                },
+                "successRate": {
+                    "description": "存储成功率，按已结束的生成计算",
+                    "type": "number"
+                },
+                "total": {
+                    "description": "参与的生成次数",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "模板版本号",
+                    "type": "integer"
+                },
+                "weight": {
+                    "description": "分流权重",
+                    "type": "integer"
                 }
             }
         },
-        "aicode_internal_model_vo.LoginUserVO": {
+        "aicode_internal_model_vo.TokenUsageSummaryVO": {
             "type": "object",
             "properties": {
-                "createTime": {
-                    "description": "创建时间",
-                    "type": "string"
+                "dailyQuota": {
+                    "description": "每日配额，0 表示不限制",
+                    "type": "integer"
                 },
-                "id": {
-                    "description": "用户 id",
+                "dailyUsed": {
+                    "description": "今日已用 token",
                     "type": "integer"
                 },
-                "updateTime": {
-                    "description": "更新时间",
+                "models": {
+                    "description": "本月按模型汇总的用量",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/aicode_internal_model_vo.ModelUsageVO"
+                    }
+                },
+                "monthlyQuota": {
+                    "description": "每月配额，0 表示不限制",
+                    "type": "integer"
+                },
+                "monthlyUsed": {
+                    "description": "本月已用 token",
+                    "type": "integer"
+                }
+            }
+        },
+        "aicode_internal_model_vo.UserSessionVO": {
+            "type": "object",
+            "properties": {
+                "clientIp": {
+                    "description": "最近一次保存 session 时的客户端 IP",
                     "type": "string"
                 },
-                "userAccount": {
-                    "description": "账号",
+                "createTime": {
+                    "description": "创建时间",
                     "type": "string"
                 },
-                "userAvatar": {
-                    "description": "用户头像",
+                "expireTime": {
+                    "description": "过期时间",
                     "type": "string"
                 },
-                "userName": {
-                    "description": "用户昵称",
+                "lastAccessTime": {
+                    "description": "最近访问时间，精度为 1 分钟",
                     "type": "string"
                 },
-                "userProfile": {
-                    "description": "用户简介",
+                "sessionId": {
+                    "description": "session id",
                     "type": "string"
                 },
-                "userRole": {
-                    "description": "用户角色：user/admin",
+                "userAgent": {
+                    "description": "最近一次保存 session 时的客户端 User-Agent",
                     "type": "string"
                 }
             }
@@ -937,28 +3542,32 @@ const docTemplate = `{
                 "ChatModelTypeDeepSeek"
             ]
         },
-        "consts.ChatRole": {
+        "consts.ChatRespType": {
             "type": "string",
             "enum": [
-                "user",
-                "assistant",
-                "system"
+                "generate",
+                "stream"
             ],
             "x-enum-varnames": [
-                "ChatRoleUser",
-                "ChatRoleAssistant",
-                "ChatRoleSystem"
+                "ChatRespTypeGenerate",
+                "ChatRespTypeStream"
             ]
         },
         "consts.CodeGenarateType": {
             "type": "string",
             "enum": [
                 "single",
-                "multi"
+                "multi",
+                "vue_project",
+                "agent",
+                "edit"
             ],
             "x-enum-varnames": [
                 "CodeGenarateTypeSingle",
-                "CodeGenarateTypeMulti"
+                "CodeGenarateTypeMulti",
+                "CodeGenarateTypeVueProject",
+                "CodeGenarateTypeAgent",
+                "CodeGenarateTypeEdit"
             ]
         }
     }
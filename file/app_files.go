@@ -0,0 +1,139 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// appFileMaxReadBytes 单次读取应用文件的最大字节数
+const appFileMaxReadBytes = 256 * 1024
+
+// AppFileEntry 应用目录下的文件/目录条目
+type AppFileEntry struct {
+	Path  string `json:"path"`  // 相对于应用目录的路径，以 / 分隔
+	IsDir bool   `json:"isDir"` // 是否为目录
+	Size  int64  `json:"size"`  // 文件大小（字节），目录为 0
+}
+
+// resolveAppPath 校验相对路径并拼接为应用目录下的绝对路径
+func resolveAppPath(appId, relPath string) (string, string, error) {
+	cleaned, err := sanitizeProjectPath(relPath)
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(buildAppDir(appId), filepath.FromSlash(cleaned)), cleaned, nil
+}
+
+// WriteAppFile 写入应用目录下的单个文件，目录不存在时自动创建
+func WriteAppFile(appId, relPath, content string) error {
+	fullPath, _, err := resolveAppPath(appId, relPath)
+	if err != nil {
+		return err
+	}
+	return writeFile(fullPath, content)
+}
+
+// ReadAppFile 读取应用目录下的单个文件，超过大小上限时返回错误
+func ReadAppFile(appId, relPath string) (string, error) {
+	fullPath, cleaned, err := resolveAppPath(appId, relPath)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("文件不存在: %s", cleaned)
+		}
+		return "", fmt.Errorf("读取文件失败 [%s]: %w", cleaned, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s 是目录，不能按文件读取", cleaned)
+	}
+	if info.Size() > appFileMaxReadBytes {
+		return "", fmt.Errorf("文件 %s 大小 %d 字节超过读取上限 %d", cleaned, info.Size(), appFileMaxReadBytes)
+	}
+	data, err := os.ReadFile(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("读取文件失败 [%s]: %w", cleaned, err)
+	}
+	return string(data), nil
+}
+
+// ListAppDir 递归列出应用目录（relPath 为空时为根目录）下的全部文件与目录，跳过隐藏文件
+func ListAppDir(appId, relPath string) ([]AppFileEntry, error) {
+	root := buildAppDir(appId)
+	dir := root
+	if rel := strings.TrimSpace(relPath); rel != "" && rel != "." && rel != "/" {
+		fullPath, _, err := resolveAppPath(appId, rel)
+		if err != nil {
+			return nil, err
+		}
+		dir = fullPath
+	}
+	entries := make([]AppFileEntry, 0)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return entries, nil
+	}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		if strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		entry := AppFileEntry{Path: filepath.ToSlash(rel), IsDir: info.IsDir()}
+		if !info.IsDir() {
+			entry.Size = info.Size()
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取目录失败: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries, nil
+}
+
+// DeleteAppFile 删除应用目录下的单个文件
+func DeleteAppFile(appId, relPath string) error {
+	fullPath, cleaned, err := resolveAppPath(appId, relPath)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("文件不存在: %s", cleaned)
+		}
+		return fmt.Errorf("删除文件失败 [%s]: %w", cleaned, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s 是目录，只能删除文件", cleaned)
+	}
+	if err := os.Remove(fullPath); err != nil {
+		return fmt.Errorf("删除文件失败 [%s]: %w", cleaned, err)
+	}
+	return nil
+}
+
+// RemoveAppDir 删除应用目录，包括生成的代码与上传的图片
+func RemoveAppDir(appId string) error {
+	if appId == "" || strings.ContainsAny(appId, `/\.`) {
+		return nil
+	}
+	return os.RemoveAll(buildAppDir(appId))
+}
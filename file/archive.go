@@ -0,0 +1,68 @@
+package file
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteAppZip 将应用目录（version 为 0 时）或指定版本快照打包为 zip 写入 w
+// 以 . 开头的隐藏文件与目录（上传文件、内部元数据等）不打包
+func WriteAppZip(w io.Writer, appId string, version int) error {
+	root := buildAppDir(appId)
+	if version > 0 {
+		root = buildVersionDir(appId, version)
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return fmt.Errorf("应用代码不存在: %s", root)
+	}
+
+	zw := zip.NewWriter(w)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		if strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() || !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		header.Method = zip.Deflate
+		entry, err := zw.CreateHeader(header)
+		if err != nil {
+			return fmt.Errorf("写入压缩包失败 [%s]: %w", header.Name, err)
+		}
+		in, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("读取文件失败 [%s]: %w", header.Name, err)
+		}
+		defer in.Close()
+		if _, err := io.Copy(entry, in); err != nil {
+			return fmt.Errorf("写入压缩包失败 [%s]: %w", header.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
@@ -0,0 +1,113 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"aicode/config"
+)
+
+// DeployRootDir 部署根目录：基础路径 + "deploy"，由静态资源路由对外提供访问
+func DeployRootDir() string {
+	cfg := config.GetConfig()
+	return filepath.Join(cfg.File.StoreBasePath, "deploy")
+}
+
+// AppCodeExists 判断应用是否已生成代码，只有隐藏文件（如上传的图片）时视为未生成
+func AppCodeExists(appId string) bool {
+	entries, err := os.ReadDir(buildAppDir(appId))
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// DeployApp 将应用目录完整复制到 {basePath}/deploy/{deployKey}
+// 先复制到临时目录再整体替换，避免访问者看到部署到一半的文件
+func DeployApp(appId, deployKey string) error {
+	if deployKey == "" || strings.ContainsAny(deployKey, `/\.`) {
+		return fmt.Errorf("非法的部署标识: %s", deployKey)
+	}
+	srcDir := buildAppDir(appId)
+	if !AppCodeExists(appId) {
+		return fmt.Errorf("应用代码不存在: %s", srcDir)
+	}
+	deployDir := filepath.Join(DeployRootDir(), deployKey)
+	tmpDir := deployDir + ".tmp"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return fmt.Errorf("清理临时部署目录失败: %w", err)
+	}
+	if err := copyDir(srcDir, tmpDir); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return err
+	}
+	if err := os.RemoveAll(deployDir); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return fmt.Errorf("清理旧部署目录失败: %w", err)
+	}
+	if err := os.Rename(tmpDir, deployDir); err != nil {
+		return fmt.Errorf("替换部署目录失败: %w", err)
+	}
+	return nil
+}
+
+// RemoveDeploy 删除应用的部署目录
+func RemoveDeploy(deployKey string) error {
+	if deployKey == "" || strings.ContainsAny(deployKey, `/\.`) {
+		return nil
+	}
+	return os.RemoveAll(filepath.Join(DeployRootDir(), deployKey))
+}
+
+// copyDir 递归复制目录，跳过以 . 开头的隐藏文件与目录
+func copyDir(srcDir, dstDir string) error {
+	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		if rel != "." && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		target := filepath.Join(dstDir, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return copyFile(path, target)
+	})
+}
+
+// copyFile 复制单个文件
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("读取文件失败 [%s]: %w", src, err)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("创建文件失败 [%s]: %w", dst, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("复制文件失败 [%s]: %w", dst, err)
+	}
+	return out.Close()
+}
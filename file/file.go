@@ -85,13 +85,23 @@ func StoreByGenType(ctx context.Context,
 	if content == "" {
 		return fmt.Errorf("content 不能为空")
 	}
-	// 剥离 AI 返回内容中可能存在的 markdown 代码块包装
-	content = stripMarkdownCodeBlock(content)
+	// 增量修改模式输出的是补丁而非完整代码
+	if genType == consts.CodeGenarateTypeEdit {
+		_, err := ApplyEditOutput(appId, content)
+		return err
+	}
+	// 提取并修复模型输出，规范化为生成类型对应的 JSON 结构；输出被截断时返回 *TruncatedOutputError
+	content, err := RepairOutput(genType, content)
+	if err != nil {
+		return err
+	}
 	switch genType {
 	case consts.CodeGenarateTypeSingle:
 		return StoreToSingalFile(ctx, appId, content)
 	case consts.CodeGenarateTypeMulti:
 		return StoreToMultiFile(ctx, appId, content)
+	case consts.CodeGenarateTypeVueProject:
+		return StoreToProjectFiles(ctx, appId, content)
 	default:
 		return fmt.Errorf("不支持的代码生成类型: %s", genType)
 	}
@@ -0,0 +1,505 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EditPatch 模型输出的针对单个文件的一处修改，支持两种格式：
+//   - SEARCH/REPLACE：将文件中唯一匹配 Search 的片段替换为 Replace；Search 为空表示新建文件
+//   - unified diff：按 Hunks 依次修改文件，Create/Delete 对应 --- /dev/null 与 +++ /dev/null
+type EditPatch struct {
+	Path    string
+	Search  string
+	Replace string
+	Hunks   []DiffHunk
+	Create  bool
+	Delete  bool
+}
+
+// DiffHunk unified diff 中的一个修改块
+type DiffHunk struct {
+	OldStart int      // @@ -l,s 中的起始行号，用于多处匹配时选择最近的位置
+	Lines    []string // 带前缀的行：' ' 上下文、'-' 删除、'+' 新增
+}
+
+var (
+	// hunkHeaderRe 匹配 @@ -l,s +l,s @@ 修改块头
+	hunkHeaderRe = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+\d+(?:,\d+)? @@`)
+	// pathLabelRe 匹配路径行前的说明性前缀，如 "文件：" "File:" "### "
+	pathLabelRe = regexp.MustCompile(`^(?i:#+\s*|文件[:：]\s*|file[:：]\s*|path[:：]\s*)`)
+)
+
+// ApplyEditOutput 从模型输出中解析修改并原子地应用到应用目录，返回被修改的文件路径
+// 输出被截断时返回 *TruncatedOutputError 且不修改任何文件
+func ApplyEditOutput(appId, content string) ([]string, error) {
+	patches, err := ParseEditPatches(content)
+	if err != nil {
+		return nil, err
+	}
+	return ApplyEditPatches(appId, patches)
+}
+
+// ParseEditPatches 从模型输出中解析 SEARCH/REPLACE 块与 unified diff，两种格式可混合出现
+// 代码块标记与说明文字被忽略；SEARCH 块未结束或代码块未闭合时返回 *TruncatedOutputError
+func ParseEditPatches(content string) ([]EditPatch, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	patches := make([]EditPatch, 0)
+	lastPath := ""
+	fenceCount := 0
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(line), "```"):
+			fenceCount++
+		case isSearchMarker(line):
+			path := searchBlockPath(lines, i)
+			if path == "" {
+				path = lastPath
+			}
+			if path == "" {
+				return nil, fmt.Errorf("第 %d 行的 SEARCH 块缺少文件路径", i+1)
+			}
+			patch, next, err := parseSearchReplace(lines, i+1)
+			if err != nil {
+				return nil, err
+			}
+			patch.Path = path
+			patches = append(patches, patch)
+			lastPath = path
+			i = next
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			patch, next := parseUnifiedDiff(lines, i)
+			patches = append(patches, patch)
+			lastPath = patch.Path
+			i = next
+		}
+	}
+	if fenceCount%2 == 1 {
+		return nil, &TruncatedOutputError{Reason: "代码块未结束"}
+	}
+	if len(patches) == 0 {
+		return nil, fmt.Errorf("模型输出中没有可应用的修改")
+	}
+	for i := range patches {
+		cleaned, err := sanitizeProjectPath(patches[i].Path)
+		if err != nil {
+			return nil, err
+		}
+		patches[i].Path = cleaned
+	}
+	return patches, nil
+}
+
+// isSearchMarker 判断是否为 <<<<<<< SEARCH 标记行
+func isSearchMarker(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "<<<<<<<") && strings.Contains(trimmed, "SEARCH")
+}
+
+// searchBlockPath 取 SEARCH 标记之前最近的非空行作为文件路径，中间可隔一个代码块起始标记；
+// 紧邻上一个块的结束标记时返回空，由调用方沿用上一个文件
+func searchBlockPath(lines []string, marker int) string {
+	for i := marker - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "```") {
+			if path := fencePath(strings.TrimPrefix(line, "```")); path != "" {
+				return path
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ">>>>>>>") {
+			return ""
+		}
+		line = pathLabelRe.ReplaceAllString(line, "")
+		line = strings.Trim(line, "*`'\" :：")
+		if line == "" || strings.ContainsAny(line, " \t") {
+			return ""
+		}
+		return line
+	}
+	return ""
+}
+
+// parseSearchReplace 解析 SEARCH 标记之后的 查找内容 ======= 替换内容 >>>>>>> REPLACE，返回结束标记所在行
+func parseSearchReplace(lines []string, start int) (EditPatch, int, error) {
+	var search, replace []string
+	inReplace := false
+	for i := start; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case !inReplace && strings.HasPrefix(trimmed, "=======") && strings.Trim(trimmed, "=") == "":
+			inReplace = true
+		case inReplace && strings.HasPrefix(trimmed, ">>>>>>>"):
+			return EditPatch{
+				Search:  strings.Join(search, "\n"),
+				Replace: strings.Join(replace, "\n"),
+			}, i, nil
+		case inReplace:
+			replace = append(replace, lines[i])
+		default:
+			search = append(search, lines[i])
+		}
+	}
+	return EditPatch{}, len(lines), &TruncatedOutputError{Reason: "SEARCH/REPLACE 块未结束"}
+}
+
+// parseUnifiedDiff 解析从 --- 行开始的单个文件的 unified diff，返回最后一个被消费的行
+// 不依赖 @@ 中的行数（模型经常写错），修改块延续到下一个块头、文件头、代码块标记或非 diff 行为止
+func parseUnifiedDiff(lines []string, start int) (EditPatch, int) {
+	oldPath := diffHeaderPath(lines[start][4:], "a/")
+	newPath := diffHeaderPath(lines[start+1][4:], "b/")
+	patch := EditPatch{Path: newPath}
+	switch {
+	case newPath == "/dev/null":
+		patch.Path = oldPath
+		patch.Delete = true
+	case oldPath == "/dev/null":
+		patch.Create = true
+	}
+
+	i := start + 2
+	for i < len(lines) {
+		m := hunkHeaderRe.FindStringSubmatch(lines[i])
+		if m == nil {
+			break
+		}
+		oldStart, _ := strconv.Atoi(m[1])
+		hunk := DiffHunk{OldStart: oldStart}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if line == "" {
+				line = " "
+			}
+			if strings.HasPrefix(line, "@@") || strings.HasPrefix(line, "```") ||
+				(strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ")) {
+				break
+			}
+			if line[0] == '\\' {
+				continue
+			}
+			if line[0] != ' ' && line[0] != '-' && line[0] != '+' {
+				break
+			}
+			hunk.Lines = append(hunk.Lines, line)
+		}
+		// 末尾的空行可能是 diff 之后的空白而非上下文
+		for len(hunk.Lines) > 0 && strings.TrimSpace(hunk.Lines[len(hunk.Lines)-1]) == "" {
+			hunk.Lines = hunk.Lines[:len(hunk.Lines)-1]
+		}
+		patch.Hunks = append(patch.Hunks, hunk)
+	}
+	return patch, i - 1
+}
+
+// diffHeaderPath 解析 ---/+++ 行中的路径，去掉 a/ b/ 前缀与时间戳
+func diffHeaderPath(header, prefix string) string {
+	path := strings.TrimSpace(header)
+	if tab := strings.IndexByte(path, '\t'); tab >= 0 {
+		path = path[:tab]
+	}
+	if path == "/dev/null" {
+		return path
+	}
+	return strings.TrimPrefix(path, prefix)
+}
+
+// editedFile 应用修改过程中单个文件的状态
+type editedFile struct {
+	path     string
+	fullPath string
+	existed  bool
+	original string
+	content  string
+	deleted  bool
+}
+
+// ApplyEditPatches 在内存中依次应用全部修改，任一修改无法匹配时返回错误且不修改任何文件；
+// 全部成功后写入磁盘，写入中途失败时恢复已写入的文件。返回被修改的文件路径
+func ApplyEditPatches(appId string, patches []EditPatch) ([]string, error) {
+	files := make(map[string]*editedFile)
+	order := make([]string, 0)
+	for i, patch := range patches {
+		f, ok := files[patch.Path]
+		if !ok {
+			var err error
+			if f, err = loadEditedFile(appId, patch.Path); err != nil {
+				return nil, err
+			}
+			files[patch.Path] = f
+			order = append(order, patch.Path)
+		}
+		if err := applyPatch(f, patch); err != nil {
+			return nil, fmt.Errorf("第 %d 处修改（%s）应用失败: %w", i+1, patch.Path, err)
+		}
+	}
+
+	changed := make([]*editedFile, 0, len(order))
+	paths := make([]string, 0, len(order))
+	for _, p := range order {
+		f := files[p]
+		if (f.deleted && f.existed) || (!f.deleted && (!f.existed || f.content != f.original)) {
+			changed = append(changed, f)
+			paths = append(paths, f.path)
+		}
+	}
+	if err := commitEditedFiles(changed); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// loadEditedFile 读取待修改文件的当前内容，文件不存在时视为空文件
+func loadEditedFile(appId, relPath string) (*editedFile, error) {
+	fullPath, cleaned, err := resolveAppPath(appId, relPath)
+	if err != nil {
+		return nil, err
+	}
+	f := &editedFile{path: cleaned, fullPath: fullPath}
+	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		return f, nil
+	}
+	content, err := ReadAppFile(appId, cleaned)
+	if err != nil {
+		return nil, err
+	}
+	f.existed = true
+	f.original = content
+	f.content = content
+	return f, nil
+}
+
+// applyPatch 将单处修改应用到文件的内存内容
+func applyPatch(f *editedFile, patch EditPatch) error {
+	if f.deleted {
+		return fmt.Errorf("文件已被删除")
+	}
+	switch {
+	case patch.Delete:
+		if !f.existed {
+			return fmt.Errorf("文件不存在，无法删除")
+		}
+		f.deleted = true
+		return nil
+	case patch.Hunks != nil || patch.Create:
+		return applyHunks(f, patch)
+	default:
+		return applySearchReplace(f, patch.Search, patch.Replace)
+	}
+}
+
+// applySearchReplace 将文件中唯一匹配 search 的片段替换为 replace
+// 精确匹配失败时按行忽略首尾空白再匹配一次
+func applySearchReplace(f *editedFile, search, replace string) error {
+	if strings.TrimSpace(search) == "" {
+		if strings.TrimSpace(f.content) != "" {
+			return fmt.Errorf("SEARCH 为空只能用于新建文件，但文件已存在")
+		}
+		f.content = ensureTrailingNewline(replace)
+		return nil
+	}
+	switch strings.Count(f.content, search) {
+	case 1:
+		f.content = strings.Replace(f.content, search, replace, 1)
+		return nil
+	case 0:
+	default:
+		return fmt.Errorf("SEARCH 内容在文件中匹配到多处，请包含更多上下文")
+	}
+
+	lines, trailingNewline := splitLines(f.content)
+	searchLines, _ := splitLines(search)
+	matches := findLineMatches(lines, searchLines, true)
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("SEARCH 内容与文件当前内容不匹配")
+	case 1:
+	default:
+		return fmt.Errorf("SEARCH 内容在文件中匹配到多处，请包含更多上下文")
+	}
+	replaceLines, _ := splitLines(replace)
+	if replace == "" {
+		replaceLines = nil
+	}
+	lines = spliceLines(lines, matches[0], len(searchLines), replaceLines)
+	f.content = joinLines(lines, trailingNewline)
+	return nil
+}
+
+// applyHunks 依次应用 unified diff 的修改块，每块在 OldStart 附近（按前序修改的行数偏移校正）选择最近的匹配位置
+func applyHunks(f *editedFile, patch EditPatch) error {
+	if patch.Create && strings.TrimSpace(f.content) != "" {
+		return fmt.Errorf("文件已存在，不能按新建文件处理")
+	}
+	lines, trailingNewline := splitLines(f.content)
+	if !f.existed || f.content == "" {
+		lines, trailingNewline = nil, true
+	}
+	offset := 0
+	for n, hunk := range patch.Hunks {
+		var oldLines, newLines []string
+		for _, line := range hunk.Lines {
+			switch line[0] {
+			case ' ':
+				oldLines = append(oldLines, line[1:])
+				newLines = append(newLines, line[1:])
+			case '-':
+				oldLines = append(oldLines, line[1:])
+			case '+':
+				newLines = append(newLines, line[1:])
+			}
+		}
+		expected := hunk.OldStart - 1 + offset
+		var pos int
+		if len(oldLines) == 0 {
+			pos = min(max(expected+1, 0), len(lines))
+			if hunk.OldStart == 0 {
+				pos = 0
+			}
+		} else {
+			matches := findLineMatches(lines, oldLines, false)
+			if len(matches) == 0 {
+				matches = findLineMatches(lines, oldLines, true)
+			}
+			if len(matches) == 0 {
+				return fmt.Errorf("第 %d 个修改块与文件当前内容不匹配", n+1)
+			}
+			pos = nearest(matches, expected)
+		}
+		lines = spliceLines(lines, pos, len(oldLines), newLines)
+		offset += len(newLines) - len(oldLines)
+	}
+	f.content = joinLines(lines, trailingNewline)
+	return nil
+}
+
+// commitEditedFiles 先将全部新内容写入临时文件，再逐个替换；替换中途失败时恢复已替换的文件
+func commitEditedFiles(files []*editedFile) error {
+	tmpSuffix := ".edit.tmp"
+	cleanup := func() {
+		for _, f := range files {
+			_ = os.Remove(f.fullPath + tmpSuffix)
+		}
+	}
+	for _, f := range files {
+		if f.deleted {
+			continue
+		}
+		if err := writeFile(f.fullPath+tmpSuffix, f.content); err != nil {
+			cleanup()
+			return err
+		}
+	}
+
+	done := make([]*editedFile, 0, len(files))
+	for _, f := range files {
+		var err error
+		if f.deleted {
+			err = os.Remove(f.fullPath)
+		} else {
+			err = os.Rename(f.fullPath+tmpSuffix, f.fullPath)
+		}
+		if err != nil {
+			cleanup()
+			restoreEditedFiles(done)
+			return fmt.Errorf("写入修改失败 [%s]: %w", f.path, err)
+		}
+		done = append(done, f)
+	}
+	return nil
+}
+
+// restoreEditedFiles 恢复已写入的文件：原本存在的写回原内容，新建的删除
+func restoreEditedFiles(files []*editedFile) {
+	for _, f := range files {
+		if f.existed {
+			_ = writeFile(f.fullPath, f.original)
+		} else {
+			_ = os.Remove(f.fullPath)
+		}
+	}
+}
+
+// findLineMatches 返回 target 在 lines 中全部匹配的起始行；loose 为 true 时忽略每行首尾空白
+func findLineMatches(lines, target []string, loose bool) []int {
+	matches := make([]int, 0)
+	if len(target) == 0 || len(target) > len(lines) {
+		return matches
+	}
+	for i := 0; i+len(target) <= len(lines); i++ {
+		ok := true
+		for j, t := range target {
+			a, b := lines[i+j], t
+			if loose {
+				a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+			}
+			if a != b {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// nearest 返回与 expected 距离最近的匹配位置
+func nearest(matches []int, expected int) int {
+	best := matches[0]
+	for _, m := range matches[1:] {
+		if abs(m-expected) < abs(best-expected) {
+			best = m
+		}
+	}
+	return best
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// spliceLines 将 lines[pos:pos+count] 替换为 replacement
+func spliceLines(lines []string, pos, count int, replacement []string) []string {
+	result := make([]string, 0, len(lines)-count+len(replacement))
+	result = append(result, lines[:pos]...)
+	result = append(result, replacement...)
+	return append(result, lines[pos+count:]...)
+}
+
+// splitLines 按行拆分文本，返回是否以换行结尾
+func splitLines(content string) ([]string, bool) {
+	trailingNewline := strings.HasSuffix(content, "\n")
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return []string{}, trailingNewline
+	}
+	return strings.Split(content, "\n"), trailingNewline
+}
+
+// joinLines splitLines 的逆操作
+func joinLines(lines []string, trailingNewline bool) string {
+	content := strings.Join(lines, "\n")
+	if trailingNewline && content != "" {
+		content += "\n"
+	}
+	return content
+}
+
+// ensureTrailingNewline 非空内容补齐末尾换行
+func ensureTrailingNewline(content string) string {
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		return content + "\n"
+	}
+	return content
+}
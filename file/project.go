@@ -0,0 +1,191 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"aicode/internal/model/vo"
+)
+
+const (
+	// projectMaxFiles 工程模式单次生成允许的最大文件数
+	projectMaxFiles = 200
+	// projectMaxPathDepth 工程模式文件路径允许的最大层级
+	projectMaxPathDepth = 10
+)
+
+// StoreToProjectFiles 将工程模式生成结果存储到本地
+// content 必须是符合 ProjectFileResult 结构的 JSON 字符串
+// 写入文件：{basePath}/app{appId}/{files[].path}
+// 所有路径校验通过后才会落盘；新文件先写入应用目录旁的临时目录，全部写入成功后才整体替换上次生成的文件
+// （以 . 开头的隐藏文件保留），避免写入中途失败时既没有旧代码也没有新代码
+func StoreToProjectFiles(ctx context.Context, appId, content string) error {
+	var result vo.ProjectFileResult
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return fmt.Errorf("解析工程生成结果失败: %w", err)
+	}
+	if len(result.Files) == 0 {
+		return fmt.Errorf("工程生成结果中没有文件")
+	}
+	if len(result.Files) > projectMaxFiles {
+		return fmt.Errorf("工程文件数 %d 超过上限 %d", len(result.Files), projectMaxFiles)
+	}
+
+	// 校验路径并去重
+	fileMap := make(map[string]string, len(result.Files))
+	for _, f := range result.Files {
+		relPath, err := sanitizeProjectPath(f.Path)
+		if err != nil {
+			return err
+		}
+		if _, ok := fileMap[relPath]; ok {
+			return fmt.Errorf("工程文件路径重复: %s", f.Path)
+		}
+		fileMap[relPath] = f.Content
+	}
+
+	dir := buildAppDir(appId)
+	tmpDir := dir + ".tmp"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return fmt.Errorf("清理临时应用目录失败: %w", err)
+	}
+	for relPath, fileContent := range fileMap {
+		if err := writeFile(filepath.Join(tmpDir, filepath.FromSlash(relPath)), fileContent); err != nil {
+			_ = os.RemoveAll(tmpDir)
+			return err
+		}
+	}
+	return swapAppDir(dir, tmpDir)
+}
+
+// swapAppDir 用临时目录整体替换应用目录：先把应用目录中的隐藏文件/目录移入临时目录，再交换两个目录
+// 任一步失败时恢复应用目录并删除临时目录
+func swapAppDir(dir, tmpDir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		_ = os.RemoveAll(tmpDir)
+		return fmt.Errorf("读取应用目录失败: %w", err)
+	}
+	exists := err == nil
+	moved := make([]string, 0)
+	restore := func() {
+		for _, name := range moved {
+			_ = os.Rename(filepath.Join(tmpDir, name), filepath.Join(dir, name))
+		}
+		_ = os.RemoveAll(tmpDir)
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if err := os.Rename(filepath.Join(dir, entry.Name()), filepath.Join(tmpDir, entry.Name())); err != nil {
+			restore()
+			return fmt.Errorf("移动隐藏文件失败 [%s]: %w", entry.Name(), err)
+		}
+		moved = append(moved, entry.Name())
+	}
+
+	oldDir := dir + ".old"
+	if err := os.RemoveAll(oldDir); err != nil {
+		restore()
+		return fmt.Errorf("清理旧应用目录失败: %w", err)
+	}
+	if exists {
+		if err := os.Rename(dir, oldDir); err != nil {
+			restore()
+			return fmt.Errorf("替换应用目录失败: %w", err)
+		}
+	}
+	if err := os.Rename(tmpDir, dir); err != nil {
+		if exists {
+			_ = os.Rename(oldDir, dir)
+		}
+		restore()
+		return fmt.Errorf("替换应用目录失败: %w", err)
+	}
+	_ = os.RemoveAll(oldDir)
+	return nil
+}
+
+// sanitizeProjectPath 校验并规范化模型给出的相对路径，返回以 / 分隔的干净路径
+// 拒绝绝对路径、盘符、.. 越级、隐藏文件/目录以及过深的层级，防止写出应用目录
+func sanitizeProjectPath(p string) (string, error) {
+	raw := p
+	p = strings.TrimSpace(strings.ReplaceAll(p, "\\", "/"))
+	p = strings.TrimPrefix(p, "./")
+	if p == "" {
+		return "", fmt.Errorf("工程文件路径不能为空")
+	}
+	if strings.HasPrefix(p, "/") || strings.Contains(p, ":") || strings.ContainsRune(p, 0) {
+		return "", fmt.Errorf("非法的工程文件路径: %s", raw)
+	}
+	cleaned := path.Clean(p)
+	segments := strings.Split(cleaned, "/")
+	if len(segments) > projectMaxPathDepth {
+		return "", fmt.Errorf("工程文件路径层级过深: %s", raw)
+	}
+	for _, seg := range segments {
+		if seg == "" || seg == "." || seg == ".." || strings.HasPrefix(seg, ".") {
+			return "", fmt.Errorf("非法的工程文件路径: %s", raw)
+		}
+	}
+	return cleaned, nil
+}
+
+// cleanGeneratedFiles 删除应用目录下上次生成的文件，保留以 . 开头的隐藏文件/目录
+func cleanGeneratedFiles(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("读取应用目录失败: %w", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("清理应用目录失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// RemoveStaleFiles 删除应用目录下不在 keep 列表中的文件及由此产生的空目录，隐藏文件/目录保留
+// keep 中的路径须为 sanitizeProjectPath 可接受的相对路径
+func RemoveStaleFiles(appId string, keep []string) error {
+	keepSet := make(map[string]bool, len(keep))
+	for _, p := range keep {
+		if cleaned, err := sanitizeProjectPath(p); err == nil {
+			keepSet[cleaned] = true
+		}
+	}
+	dir := buildAppDir(appId)
+	entries, err := ListAppDir(appId, "")
+	if err != nil {
+		return err
+	}
+	// 逆序遍历，保证子项先于父目录处理
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		fullPath := filepath.Join(dir, filepath.FromSlash(entry.Path))
+		if entry.IsDir {
+			if children, err := os.ReadDir(fullPath); err == nil && len(children) == 0 {
+				_ = os.Remove(fullPath)
+			}
+			continue
+		}
+		if !keepSet[entry.Path] {
+			if err := os.Remove(fullPath); err != nil {
+				return fmt.Errorf("清理旧文件失败 [%s]: %w", entry.Path, err)
+			}
+		}
+	}
+	return nil
+}
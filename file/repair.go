@@ -0,0 +1,279 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"aicode/consts"
+	"aicode/internal/model/vo"
+)
+
+// TruncatedOutputError 模型输出在代码中途被截断（如 JSON 未闭合、代码块未结束）
+// 调用方可据此请求模型从中断处续写，拼接后重新解析
+type TruncatedOutputError struct {
+	Reason string
+}
+
+func (e *TruncatedOutputError) Error() string {
+	return "模型输出被截断: " + e.Reason
+}
+
+// jsonFenceRe 匹配 ```json 代码块起始标记
+var jsonFenceRe = regexp.MustCompile("```(?i:json)?[ \\t]*\\n\\s*\\{")
+
+// RepairOutput 将模型输出规范化为 genType 对应结构的合法 JSON，依次尝试：
+//  1. 提取 JSON 对象（忽略前后说明文字与代码块标记），解析失败时修复未转义的控制字符、非法转义、多余逗号等
+//  2. 从 ```html / ```css / ```js 代码块中提取文件
+//  3. 单文件模式下直接截取 <html>...</html>
+//
+// 输出被截断且无法通过以上方式得到完整结果时返回 *TruncatedOutputError
+func RepairOutput(genType consts.CodeGenarateType, content string) (string, error) {
+	content = stripMarkdownCodeBlock(content)
+
+	var truncated *TruncatedOutputError
+	if candidate, ok := extractJSONCandidate(content); ok {
+		repaired, complete := repairJSON(candidate)
+		if complete {
+			if err := validateResult(genType, repaired); err == nil {
+				return repaired, nil
+			}
+		} else {
+			truncated = &TruncatedOutputError{Reason: "JSON 未闭合"}
+		}
+	}
+
+	files, order, fenceTruncated := parseFencedFiles(content)
+	if len(files) > 0 && !fenceTruncated {
+		if result, err := buildResultFromFiles(genType, files, order); err == nil {
+			return result, nil
+		}
+	}
+	if fenceTruncated && truncated == nil {
+		truncated = &TruncatedOutputError{Reason: "代码块未结束"}
+	}
+
+	if genType == consts.CodeGenarateTypeSingle {
+		html, complete, found := extractRawHTML(content)
+		if found && complete {
+			return buildResultFromFiles(genType, map[string]string{"index.html": html}, nil)
+		}
+		if found && truncated == nil {
+			truncated = &TruncatedOutputError{Reason: "HTML 未结束"}
+		}
+	}
+
+	if truncated != nil {
+		return "", truncated
+	}
+	return "", fmt.Errorf("无法从模型输出中解析出 %s 模式的代码", genType)
+}
+
+// extractJSONCandidate 定位输出中的 JSON 对象起点：优先取 ```json 代码块，否则取第一个 {
+func extractJSONCandidate(content string) (string, bool) {
+	if loc := jsonFenceRe.FindStringIndex(content); loc != nil {
+		return content[loc[1]-1:], true
+	}
+	start := strings.IndexByte(content, '{')
+	if start < 0 {
+		return "", false
+	}
+	return content[start:], true
+}
+
+// repairJSON 从 JSON 对象起点扫描到根节点闭合为止，修复常见的模型输出问题：
+//   - 字符串中未转义的换行、制表符等控制字符
+//   - 非法的转义序列（如 \s）
+//   - 字符串中未转义的双引号（其后不是 , : } ] 时视为字面量）
+//   - 对象/数组末尾多余的逗号
+//
+// 根节点闭合之后的内容被丢弃；扫描到末尾仍未闭合时 complete 为 false
+func repairJSON(s string) (repaired string, complete bool) {
+	var b strings.Builder
+	b.Grow(len(s) + 16)
+	depth := 0
+	inString := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case c == '\\':
+				if i+1 < len(s) && strings.IndexByte(`"\/bfnrtu`, s[i+1]) >= 0 {
+					b.WriteByte(c)
+					b.WriteByte(s[i+1])
+					i++
+				} else {
+					b.WriteString(`\\`)
+				}
+			case c == '"':
+				if closesString(s, i+1) {
+					inString = false
+					b.WriteByte(c)
+				} else {
+					b.WriteString(`\"`)
+				}
+			case c == '\n':
+				b.WriteString(`\n`)
+			case c == '\r':
+				b.WriteString(`\r`)
+			case c == '\t':
+				b.WriteString(`\t`)
+			case c < 0x20:
+				fmt.Fprintf(&b, `\u%04x`, c)
+			default:
+				b.WriteByte(c)
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+			b.WriteByte(c)
+		case '{', '[':
+			depth++
+			b.WriteByte(c)
+		case '}', ']':
+			trimTrailingComma(&b)
+			b.WriteByte(c)
+			depth--
+			if depth == 0 {
+				return b.String(), true
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), false
+}
+
+// closesString 判断位置 i 处的双引号之后（跳过空白）是否为合法的结构字符，即该引号是否为字符串结束符
+func closesString(s string, next int) bool {
+	for ; next < len(s); next++ {
+		switch s[next] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case ',', ':', '}', ']':
+			return true
+		default:
+			return false
+		}
+	}
+	// 输出在引号处结束，按字符串结束处理
+	return true
+}
+
+// trimTrailingComma 删除已输出内容末尾（忽略空白）的逗号
+func trimTrailingComma(b *strings.Builder) {
+	out := b.String()
+	trimmed := strings.TrimRight(out, " \t\r\n")
+	if strings.HasSuffix(trimmed, ",") {
+		b.Reset()
+		b.WriteString(trimmed[:len(trimmed)-1])
+	}
+}
+
+// validateResult 校验 JSON 符合 genType 对应的结构且必要字段非空
+func validateResult(genType consts.CodeGenarateType, content string) error {
+	switch genType {
+	case consts.CodeGenarateTypeSingle:
+		var result vo.SingleHTMLResult
+		if err := json.Unmarshal([]byte(content), &result); err != nil {
+			return err
+		}
+		if strings.TrimSpace(result.HTML) == "" {
+			return fmt.Errorf("html 字段为空")
+		}
+	case consts.CodeGenarateTypeMulti:
+		var result vo.MultiHTMLResult
+		if err := json.Unmarshal([]byte(content), &result); err != nil {
+			return err
+		}
+		if strings.TrimSpace(result.HTML) == "" {
+			return fmt.Errorf("html 字段为空")
+		}
+	case consts.CodeGenarateTypeVueProject:
+		var result vo.ProjectFileResult
+		if err := json.Unmarshal([]byte(content), &result); err != nil {
+			return err
+		}
+		if len(result.Files) == 0 {
+			return fmt.Errorf("files 字段为空")
+		}
+	default:
+		return fmt.Errorf("不支持的代码生成类型: %s", genType)
+	}
+	return nil
+}
+
+// buildResultFromFiles 将提取出的文件组装为 genType 对应结构的 JSON
+func buildResultFromFiles(genType consts.CodeGenarateType,
+	files map[string]string, order []string) (string, error) {
+	var result any
+	switch genType {
+	case consts.CodeGenarateTypeSingle:
+		if files["index.html"] == "" {
+			return "", fmt.Errorf("缺少 html 代码")
+		}
+		result = vo.SingleHTMLResult{HTML: files["index.html"]}
+	case consts.CodeGenarateTypeMulti:
+		if files["index.html"] == "" {
+			return "", fmt.Errorf("缺少 html 代码")
+		}
+		result = vo.MultiHTMLResult{
+			HTML:       files["index.html"],
+			CSS:        files["style.css"],
+			JavaScript: files["script.js"],
+		}
+	case consts.CodeGenarateTypeVueProject:
+		project := vo.ProjectFileResult{Files: make([]vo.ProjectFile, 0, len(order))}
+		for _, p := range order {
+			project.Files = append(project.Files, vo.ProjectFile{Path: p, Content: files[p]})
+		}
+		result = project
+	default:
+		return "", fmt.Errorf("不支持的代码生成类型: %s", genType)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// parseFencedFiles 从 markdown 代码块中提取文件，同名文件以最后一次为准
+func parseFencedFiles(content string) (files map[string]string, order []string, truncated bool) {
+	parser := &StreamFileParser{mode: modeFence}
+	parser.fence = &fenceScanner{parser: parser}
+	parser.fence.feed(content)
+	parser.fence.close()
+
+	files = make(map[string]string)
+	for _, event := range parser.events {
+		if event.Type != StreamEventFileCompleted {
+			continue
+		}
+		if _, ok := files[event.Path]; !ok {
+			order = append(order, event.Path)
+		}
+		files[event.Path] = event.Content
+	}
+	return files, order, parser.fence.inBlock
+}
+
+// htmlStartRe 匹配 HTML 文档起始
+var htmlStartRe = regexp.MustCompile(`(?i)<!doctype html|<html[\s>]`)
+
+// extractRawHTML 截取输出中的 <!DOCTYPE html> / <html> 到 </html> 之间的内容
+func extractRawHTML(content string) (html string, complete bool, found bool) {
+	loc := htmlStartRe.FindStringIndex(content)
+	if loc == nil {
+		return "", false, false
+	}
+	rest := content[loc[0]:]
+	end := strings.LastIndex(strings.ToLower(rest), "</html>")
+	if end < 0 {
+		return rest, false, true
+	}
+	return rest[:end+len("</html>")], true, true
+}
@@ -0,0 +1,591 @@
+package file
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// StreamEventType 增量解析事件类型
+type StreamEventType string
+
+const (
+	// StreamEventText 文件之外的说明文字
+	StreamEventText StreamEventType = "text"
+	// StreamEventFileStarted 开始输出一个文件
+	StreamEventFileStarted StreamEventType = "file_started"
+	// StreamEventFileChunk 文件内容增量
+	StreamEventFileChunk StreamEventType = "file_chunk"
+	// StreamEventFileCompleted 文件输出完毕，Content 为文件完整内容
+	StreamEventFileCompleted StreamEventType = "file_completed"
+)
+
+// StreamEvent 增量解析事件
+type StreamEvent struct {
+	Type    StreamEventType
+	Path    string // 文件相对路径，Text 事件为空
+	Content string // Chunk/Text 为增量内容，Completed 为文件完整内容
+}
+
+// jsonFieldFiles JSON 输出中字段名与文件名的对应关系（single/multi 模式）
+var jsonFieldFiles = map[string]string{
+	"html":       "index.html",
+	"css":        "style.css",
+	"javascript": "script.js",
+	"js":         "script.js",
+}
+
+// fenceLangFiles 代码块语言与默认文件名的对应关系，代码块未标注路径时使用
+var fenceLangFiles = map[string]string{
+	"html":       "index.html",
+	"css":        "style.css",
+	"javascript": "script.js",
+	"js":         "script.js",
+}
+
+// streamParseMode 解析模式，由输出的开头内容判定
+type streamParseMode int
+
+const (
+	modeDetect streamParseMode = iota
+	modeJSON
+	modeFence
+)
+
+// StreamFileParser 模型输出的增量解析器
+// 支持两种输出格式，按输出开头自动识别：
+//   - JSON：{"html": "..."}、{"html","css","javascript"} 或 {"files": [{"path","content"}]}，字段值边到达边输出
+//   - 代码块：```html / ```css / ```js，或在语言后标注路径（```js src/main.js），按行输出
+//
+// Feed 每次返回本次输入产生的事件，Close 在输出结束时调用；非并发安全
+type StreamFileParser struct {
+	mode    streamParseMode
+	pending strings.Builder // modeDetect 阶段缓存的内容
+
+	json  *jsonScanner
+	fence *fenceScanner
+
+	events    []StreamEvent
+	completed []string // 已完成的文件路径，按完成顺序
+}
+
+// NewStreamFileParser 创建增量解析器
+func NewStreamFileParser() *StreamFileParser {
+	return &StreamFileParser{}
+}
+
+// Feed 输入一段增量输出，返回新产生的事件
+func (p *StreamFileParser) Feed(chunk string) []StreamEvent {
+	p.events = p.events[:0]
+	switch p.mode {
+	case modeDetect:
+		p.pending.WriteString(chunk)
+		p.detect(false)
+	case modeJSON:
+		p.json.feed(chunk)
+	case modeFence:
+		p.fence.feed(chunk)
+	}
+	return p.flushEvents()
+}
+
+// Close 结束解析，输出残留内容；未闭合的文件不会产生 Completed 事件
+func (p *StreamFileParser) Close() []StreamEvent {
+	p.events = p.events[:0]
+	if p.mode == modeDetect {
+		p.detect(true)
+	}
+	if p.mode == modeFence {
+		p.fence.close()
+	}
+	return p.flushEvents()
+}
+
+// CompletedFiles 已完整输出的文件路径
+func (p *StreamFileParser) CompletedFiles() []string {
+	return p.completed
+}
+
+// Truncated 输出是否在文件或 JSON 结构中途结束，可在 Close 之前调用以决定是否续写
+func (p *StreamFileParser) Truncated() bool {
+	switch p.mode {
+	case modeJSON:
+		return !p.json.done
+	case modeFence:
+		// 末行为没有换行符的闭合标记时，代码块实际已结束
+		return p.fence.inBlock && strings.TrimSpace(p.fence.lineBuf.String()) != "```"
+	default:
+		return false
+	}
+}
+
+// TruncatedError 输出被截断时返回描述截断原因的 *TruncatedOutputError，未截断时返回 nil
+func (p *StreamFileParser) TruncatedError() error {
+	if !p.Truncated() {
+		return nil
+	}
+	if p.mode == modeJSON {
+		return &TruncatedOutputError{Reason: "JSON 未闭合"}
+	}
+	return &TruncatedOutputError{Reason: "代码块未结束"}
+}
+
+// detect 根据开头内容判定解析模式：以 { 开头或首行为 ```json 时按 JSON 解析，否则按代码块解析
+// final 为 true 时不再等待更多内容
+func (p *StreamFileParser) detect(final bool) {
+	buffered := p.pending.String()
+	trimmed := strings.TrimLeft(buffered, " \t\r\n")
+	if trimmed == "" && !final {
+		return
+	}
+	switch {
+	case strings.HasPrefix(trimmed, "{"):
+		p.startJSON(trimmed)
+	case strings.HasPrefix(trimmed, "`"):
+		newline := strings.IndexByte(trimmed, '\n')
+		if newline < 0 && !final {
+			return
+		}
+		firstLine, rest := trimmed, ""
+		if newline >= 0 {
+			firstLine, rest = trimmed[:newline], trimmed[newline+1:]
+		}
+		if strings.TrimSpace(strings.TrimLeft(firstLine, "`")) == "json" {
+			p.startJSON(rest)
+		} else {
+			p.startFence(buffered)
+		}
+	default:
+		p.startFence(buffered)
+	}
+	p.pending.Reset()
+}
+
+func (p *StreamFileParser) startJSON(content string) {
+	p.mode = modeJSON
+	p.json = &jsonScanner{parser: p, projectFiles: make(map[int]*projectFileState)}
+	p.json.feed(content)
+}
+
+func (p *StreamFileParser) startFence(content string) {
+	p.mode = modeFence
+	p.fence = &fenceScanner{parser: p}
+	p.fence.feed(content)
+}
+
+func (p *StreamFileParser) emit(event StreamEvent) {
+	if event.Type == StreamEventFileCompleted {
+		p.completed = append(p.completed, event.Path)
+	}
+	// 相邻的同一文件 Chunk / Text 事件合并，减少推送次数
+	if n := len(p.events); n > 0 && (event.Type == StreamEventFileChunk || event.Type == StreamEventText) {
+		last := &p.events[n-1]
+		if last.Type == event.Type && last.Path == event.Path {
+			last.Content += event.Content
+			return
+		}
+	}
+	p.events = append(p.events, event)
+}
+
+func (p *StreamFileParser) flushEvents() []StreamEvent {
+	if len(p.events) == 0 {
+		return nil
+	}
+	events := make([]StreamEvent, len(p.events))
+	copy(events, p.events)
+	return events
+}
+
+// jsonFrame JSON 容器栈帧
+type jsonFrame struct {
+	isObject  bool
+	expectKey bool   // 对象中下一个字符串是否为 key
+	key       string // 对象中当前 key
+	count     int    // 数组中已开始的元素个数
+}
+
+// projectFileState files 数组中单个文件对象的解析状态，兼容 content 先于 path 出现
+type projectFileState struct {
+	path      string
+	started   bool
+	completed bool
+	content   strings.Builder
+	begun     bool // content 字符串已开始
+	ended     bool // content 字符串已结束
+}
+
+// jsonScanner 逐字符扫描 JSON，对字符串值按路径输出增量
+type jsonScanner struct {
+	parser *StreamFileParser
+	stack  []jsonFrame
+	done   bool // 根节点已闭合，其后内容忽略
+
+	inString   bool
+	stringKey  bool // 当前字符串是对象 key
+	escape     bool
+	unicodeBuf []byte // \u 之后的十六进制字符
+	highSurr   rune   // 待配对的 UTF-16 高位代理
+	strBuf     strings.Builder
+
+	target       *jsonTarget // 当前字符串值对应的输出目标，为 nil 表示忽略
+	projectFiles map[int]*projectFileState
+}
+
+// jsonTarget 字符串值的输出目标
+type jsonTarget struct {
+	path    string            // 直接输出的文件路径（single/multi）
+	content *strings.Builder  // 直接输出文件的已输出内容
+	project *projectFileState // files[i].content
+	pathOf  *projectFileState // files[i].path
+}
+
+func (s *jsonScanner) feed(chunk string) {
+	for i := 0; i < len(chunk) && !s.done; i++ {
+		c := chunk[i]
+		if s.inString {
+			s.scanString(c)
+			continue
+		}
+		switch c {
+		case '{', '[':
+			s.beginValue()
+			s.stack = append(s.stack, jsonFrame{isObject: c == '{', expectKey: c == '{'})
+		case '}', ']':
+			if len(s.stack) > 0 {
+				s.stack = s.stack[:len(s.stack)-1]
+			}
+			if len(s.stack) == 0 {
+				s.done = true
+			}
+		case ':':
+			if top := s.top(); top != nil && top.isObject {
+				top.expectKey = false
+			}
+		case ',':
+			if top := s.top(); top != nil && top.isObject {
+				top.expectKey = true
+			}
+		case '"':
+			s.beginString()
+		default:
+			// 数字、布尔值等非字符串值忽略
+		}
+	}
+	s.flushString()
+}
+
+func (s *jsonScanner) top() *jsonFrame {
+	if len(s.stack) == 0 {
+		return nil
+	}
+	return &s.stack[len(s.stack)-1]
+}
+
+// beginValue 在数组中开始一个新元素时推进下标
+func (s *jsonScanner) beginValue() {
+	if top := s.top(); top != nil && !top.isObject {
+		top.count++
+	}
+}
+
+// path 当前位置的路径，如 [files 0 content]
+func (s *jsonScanner) path() []string {
+	path := make([]string, 0, len(s.stack))
+	for _, frame := range s.stack {
+		if frame.isObject {
+			path = append(path, frame.key)
+		} else {
+			path = append(path, strconv.Itoa(frame.count-1))
+		}
+	}
+	return path
+}
+
+func (s *jsonScanner) beginString() {
+	s.inString = true
+	s.strBuf.Reset()
+	top := s.top()
+	s.stringKey = top != nil && top.isObject && top.expectKey
+	if s.stringKey {
+		return
+	}
+	s.beginValue()
+	s.target = s.resolveTarget(s.path())
+	if s.target == nil {
+		return
+	}
+	switch {
+	case s.target.path != "":
+		s.target.content = &strings.Builder{}
+		s.parser.emit(StreamEvent{Type: StreamEventFileStarted, Path: s.target.path})
+	case s.target.project != nil:
+		s.target.project.begun = true
+		s.startProjectFile(s.target.project)
+	}
+}
+
+// resolveTarget 根据字符串值所在路径确定输出目标
+func (s *jsonScanner) resolveTarget(path []string) *jsonTarget {
+	if len(path) == 1 {
+		if name, ok := jsonFieldFiles[path[0]]; ok {
+			return &jsonTarget{path: name}
+		}
+		return nil
+	}
+	if len(path) == 3 && path[0] == "files" {
+		index, err := strconv.Atoi(path[1])
+		if err != nil {
+			return nil
+		}
+		state, ok := s.projectFiles[index]
+		if !ok {
+			state = &projectFileState{}
+			s.projectFiles[index] = state
+		}
+		switch path[2] {
+		case "content":
+			return &jsonTarget{project: state}
+		case "path":
+			return &jsonTarget{pathOf: state}
+		}
+	}
+	return nil
+}
+
+func (s *jsonScanner) scanString(c byte) {
+	if s.unicodeBuf != nil {
+		s.unicodeBuf = append(s.unicodeBuf, c)
+		if len(s.unicodeBuf) == 4 {
+			s.writeUnicode()
+		}
+		return
+	}
+	if s.escape {
+		s.escape = false
+		switch c {
+		case 'n':
+			s.writeRune('\n')
+		case 't':
+			s.writeRune('\t')
+		case 'r':
+			s.writeRune('\r')
+		case 'b':
+			s.writeRune('\b')
+		case 'f':
+			s.writeRune('\f')
+		case 'u':
+			s.unicodeBuf = make([]byte, 0, 4)
+		default:
+			// \" \\ \/ 及非法转义均按字面量处理
+			s.writeByte(c)
+		}
+		return
+	}
+	switch c {
+	case '\\':
+		s.escape = true
+	case '"':
+		s.endString()
+	default:
+		s.writeByte(c)
+	}
+}
+
+// writeUnicode 解码 \uXXXX，处理 UTF-16 代理对
+func (s *jsonScanner) writeUnicode() {
+	code, err := strconv.ParseUint(string(s.unicodeBuf), 16, 32)
+	s.unicodeBuf = nil
+	if err != nil {
+		return
+	}
+	r := rune(code)
+	if utf16.IsSurrogate(r) {
+		if s.highSurr == 0 {
+			s.highSurr = r
+			return
+		}
+		r = utf16.DecodeRune(s.highSurr, r)
+		s.highSurr = 0
+	}
+	s.writeRune(r)
+}
+
+func (s *jsonScanner) writeRune(r rune) {
+	// 未配对的高位代理直接丢弃
+	s.highSurr = 0
+	s.strBuf.WriteRune(r)
+}
+
+func (s *jsonScanner) writeByte(c byte) {
+	s.strBuf.WriteByte(c)
+}
+
+// flushString 将当前字符串值已解码的内容作为增量输出
+func (s *jsonScanner) flushString() {
+	if !s.inString || s.stringKey || s.target == nil || s.strBuf.Len() == 0 {
+		return
+	}
+	delta := s.strBuf.String()
+	switch {
+	case s.target.path != "":
+		s.strBuf.Reset()
+		s.target.content.WriteString(delta)
+		s.parser.emit(StreamEvent{Type: StreamEventFileChunk, Path: s.target.path, Content: delta})
+	case s.target.project != nil:
+		s.strBuf.Reset()
+		s.appendProjectContent(s.target.project, delta)
+	}
+	// files[i].path 需完整收集，保留在 strBuf 中
+}
+
+func (s *jsonScanner) endString() {
+	if s.stringKey {
+		s.inString = false
+		if top := s.top(); top != nil {
+			top.key = s.strBuf.String()
+		}
+		s.strBuf.Reset()
+		return
+	}
+	s.flushString()
+	s.inString = false
+	target := s.target
+	s.target = nil
+	if target == nil {
+		return
+	}
+	switch {
+	case target.path != "":
+		s.parser.emit(StreamEvent{Type: StreamEventFileCompleted, Path: target.path, Content: target.content.String()})
+	case target.project != nil:
+		target.project.ended = true
+		s.completeProjectFile(target.project)
+	case target.pathOf != nil:
+		state := target.pathOf
+		state.path = strings.TrimSpace(s.strBuf.String())
+		s.strBuf.Reset()
+		// content 先于 path 出现时，补发此前缓存的内容
+		if state.path != "" && state.begun && !state.started {
+			s.startProjectFile(state)
+			if state.content.Len() > 0 {
+				s.parser.emit(StreamEvent{Type: StreamEventFileChunk, Path: state.path, Content: state.content.String()})
+			}
+			s.completeProjectFile(state)
+		}
+	}
+}
+
+func (s *jsonScanner) startProjectFile(state *projectFileState) {
+	if state.started || state.path == "" {
+		return
+	}
+	state.started = true
+	s.parser.emit(StreamEvent{Type: StreamEventFileStarted, Path: state.path})
+}
+
+func (s *jsonScanner) appendProjectContent(state *projectFileState, delta string) {
+	state.content.WriteString(delta)
+	if state.started {
+		s.parser.emit(StreamEvent{Type: StreamEventFileChunk, Path: state.path, Content: delta})
+	}
+}
+
+func (s *jsonScanner) completeProjectFile(state *projectFileState) {
+	if !state.started || !state.ended || state.completed {
+		return
+	}
+	state.completed = true
+	s.parser.emit(StreamEvent{Type: StreamEventFileCompleted, Path: state.path, Content: state.content.String()})
+}
+
+// fenceScanner 按行扫描 markdown 代码块
+type fenceScanner struct {
+	parser  *StreamFileParser
+	lineBuf strings.Builder
+	inBlock bool
+	path    string
+	content strings.Builder
+}
+
+func (s *fenceScanner) feed(chunk string) {
+	for {
+		newline := strings.IndexByte(chunk, '\n')
+		if newline < 0 {
+			s.lineBuf.WriteString(chunk)
+			return
+		}
+		s.lineBuf.WriteString(chunk[:newline+1])
+		chunk = chunk[newline+1:]
+		line := s.lineBuf.String()
+		s.lineBuf.Reset()
+		s.scanLine(line)
+	}
+}
+
+func (s *fenceScanner) close() {
+	if s.lineBuf.Len() == 0 {
+		return
+	}
+	line := s.lineBuf.String()
+	s.lineBuf.Reset()
+	// 末行没有换行符时，闭合标记同样有效
+	if s.inBlock && strings.TrimSpace(line) == "```" {
+		s.completeBlock()
+		return
+	}
+	s.scanLine(line)
+}
+
+func (s *fenceScanner) scanLine(line string) {
+	trimmed := strings.TrimSpace(line)
+	if s.inBlock {
+		if trimmed == "```" {
+			s.completeBlock()
+			return
+		}
+		s.content.WriteString(line)
+		s.parser.emit(StreamEvent{Type: StreamEventFileChunk, Path: s.path, Content: line})
+		return
+	}
+	if strings.HasPrefix(trimmed, "```") {
+		if path := fencePath(strings.TrimPrefix(trimmed, "```")); path != "" {
+			s.inBlock = true
+			s.path = path
+			s.content.Reset()
+			s.parser.emit(StreamEvent{Type: StreamEventFileStarted, Path: path})
+			return
+		}
+	}
+	s.parser.emit(StreamEvent{Type: StreamEventText, Content: line})
+}
+
+func (s *fenceScanner) completeBlock() {
+	s.inBlock = false
+	s.parser.emit(StreamEvent{Type: StreamEventFileCompleted, Path: s.path, Content: s.content.String()})
+	s.path = ""
+	s.content.Reset()
+}
+
+// fencePath 从代码块标记的信息串中解析文件路径
+// 支持 "js src/main.js"、"js:src/main.js"、"src/main.js"、"html title=index.html" 等写法；
+// 未标注路径时按语言使用默认文件名，无法识别时返回空串
+func fencePath(info string) string {
+	info = strings.TrimSpace(info)
+	if info == "" {
+		return ""
+	}
+	fields := strings.FieldsFunc(info, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ':'
+	})
+	for _, field := range fields {
+		for _, prefix := range []string{"title=", "file=", "filename=", "path="} {
+			field = strings.TrimPrefix(field, prefix)
+		}
+		field = strings.Trim(field, `"'`)
+		if strings.ContainsAny(field, "./") {
+			return field
+		}
+	}
+	return fenceLangFiles[strings.ToLower(fields[0])]
+}
@@ -0,0 +1,31 @@
+package file
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// appUploadDir 应用目录下保存用户上传文件的隐藏目录，不参与快照、部署与打包
+const appUploadDir = ".uploads"
+
+// SaveAppUpload 将用户上传的文件保存到应用目录的 .uploads 下，返回相对于应用目录的路径
+// 文件名由时间戳与随机串组成，ext 为包含 . 的扩展名
+func SaveAppUpload(appId, ext string, data []byte) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("生成文件名失败: %w", err)
+	}
+	name := time.Now().Format("20060102150405") + "-" + hex.EncodeToString(suffix) + ext
+	dir := filepath.Join(buildAppDir(appId), appUploadDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建上传目录失败: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+		return "", fmt.Errorf("保存上传文件失败: %w", err)
+	}
+	return appUploadDir + "/" + name, nil
+}
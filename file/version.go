@@ -0,0 +1,221 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"aicode/config"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// 版本差异中的文件变更类型
+const (
+	VersionFileAdded    = "added"
+	VersionFileRemoved  = "removed"
+	VersionFileModified = "modified"
+)
+
+// diffContextLines unified diff 的上下文行数
+const diffContextLines = 3
+
+// VersionFileDiff 两个版本之间单个文件的差异
+type VersionFileDiff struct {
+	Path   string // 相对于应用目录的路径，以 / 分隔
+	Status string // 变更类型：added/removed/modified
+	Binary bool   // 是否为二进制或超过大小上限的文件，此时 Diff 为空
+	Diff   string // unified diff 文本
+}
+
+// buildVersionDir 构造版本快照目录路径：基础路径 + "app_version" + appId + "v{version}"
+func buildVersionDir(appId string, version int) string {
+	cfg := config.GetConfig()
+	return filepath.Join(cfg.File.StoreBasePath, "app_version", appId, "v"+strconv.Itoa(version))
+}
+
+// AppVersionExists 判断应用的版本快照是否存在
+func AppVersionExists(appId string, version int) bool {
+	info, err := os.Stat(buildVersionDir(appId, version))
+	return err == nil && info.IsDir()
+}
+
+// SnapshotApp 将应用目录当前内容（不含隐藏文件）复制为版本快照 {basePath}/app_version/{appId}/v{version}
+// 先复制到临时目录再整体重命名，快照目录要么完整存在要么不存在
+func SnapshotApp(appId string, version int) error {
+	if version <= 0 {
+		return fmt.Errorf("非法的版本号: %d", version)
+	}
+	if !AppCodeExists(appId) {
+		return fmt.Errorf("应用代码不存在: %s", buildAppDir(appId))
+	}
+	versionDir := buildVersionDir(appId, version)
+	tmpDir := versionDir + ".tmp"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return fmt.Errorf("清理临时快照目录失败: %w", err)
+	}
+	if err := copyDir(buildAppDir(appId), tmpDir); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return err
+	}
+	if err := os.RemoveAll(versionDir); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return fmt.Errorf("清理旧快照目录失败: %w", err)
+	}
+	if err := os.Rename(tmpDir, versionDir); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return fmt.Errorf("保存版本快照失败: %w", err)
+	}
+	return nil
+}
+
+// RemoveAppVersion 删除应用的单个版本快照
+func RemoveAppVersion(appId string, version int) error {
+	return os.RemoveAll(buildVersionDir(appId, version))
+}
+
+// RemoveAppVersions 删除应用的全部版本快照
+func RemoveAppVersions(appId string) error {
+	cfg := config.GetConfig()
+	return os.RemoveAll(filepath.Join(cfg.File.StoreBasePath, "app_version", appId))
+}
+
+// RestoreAppVersion 用版本快照替换应用目录中的生成文件，隐藏文件/目录保留
+// 快照先复制到应用目录旁的临时目录，复制成功后才清理应用目录，避免复制失败时丢失当前代码
+func RestoreAppVersion(appId string, version int) error {
+	if !AppVersionExists(appId, version) {
+		return fmt.Errorf("版本快照不存在: v%d", version)
+	}
+	appDir := buildAppDir(appId)
+	tmpDir := appDir + ".restore"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return fmt.Errorf("清理临时回滚目录失败: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := copyDir(buildVersionDir(appId, version), tmpDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(appDir, 0755); err != nil {
+		return fmt.Errorf("创建应用目录失败: %w", err)
+	}
+	if err := cleanGeneratedFiles(appDir); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		return fmt.Errorf("读取临时回滚目录失败: %w", err)
+	}
+	for _, entry := range entries {
+		if err := os.Rename(filepath.Join(tmpDir, entry.Name()), filepath.Join(appDir, entry.Name())); err != nil {
+			return fmt.Errorf("回滚文件失败 [%s]: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// DiffAppVersions 比较应用的两个版本快照，返回按路径排序的变更文件列表，未变更的文件不返回
+func DiffAppVersions(appId string, from, to int) ([]VersionFileDiff, error) {
+	fromFiles, err := readVersionFiles(appId, from)
+	if err != nil {
+		return nil, err
+	}
+	toFiles, err := readVersionFiles(appId, to)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(fromFiles)+len(toFiles))
+	for p := range fromFiles {
+		paths = append(paths, p)
+	}
+	for p := range toFiles {
+		if _, ok := fromFiles[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	diffs := make([]VersionFileDiff, 0)
+	for _, p := range paths {
+		a, inFrom := fromFiles[p]
+		b, inTo := toFiles[p]
+		diff := VersionFileDiff{Path: p, Status: VersionFileModified}
+		fromName, toName := "a/"+p, "b/"+p
+		switch {
+		case !inFrom:
+			diff.Status = VersionFileAdded
+			fromName = "/dev/null"
+		case !inTo:
+			diff.Status = VersionFileRemoved
+			toName = "/dev/null"
+		case a != nil && b != nil && bytes.Equal(a, b):
+			continue
+		}
+		if (inFrom && !isTextContent(a)) || (inTo && !isTextContent(b)) {
+			diff.Binary = true
+			diffs = append(diffs, diff)
+			continue
+		}
+		text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(a)),
+			B:        difflib.SplitLines(string(b)),
+			FromFile: fromName,
+			FromDate: "v" + strconv.Itoa(from),
+			ToFile:   toName,
+			ToDate:   "v" + strconv.Itoa(to),
+			Context:  diffContextLines,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("生成文件差异失败 [%s]: %w", p, err)
+		}
+		diff.Diff = text
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// readVersionFiles 读取版本快照中的全部文件，超过读取上限的文件内容置为 nil（按二进制处理）
+func readVersionFiles(appId string, version int) (map[string][]byte, error) {
+	if !AppVersionExists(appId, version) {
+		return nil, fmt.Errorf("版本快照不存在: v%d", version)
+	}
+	root := buildVersionDir(appId, version)
+	files := make(map[string][]byte)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if info.Size() > appFileMaxReadBytes {
+			files[rel] = nil
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("读取快照文件失败 [%s]: %w", rel, err)
+		}
+		files[rel] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// isTextContent 判断内容是否可按文本比较：非 nil、合法 UTF-8 且不含 NUL 字节
+func isTextContent(data []byte) bool {
+	return data != nil && utf8.Valid(data) && !strings.ContainsRune(string(data), 0)
+}
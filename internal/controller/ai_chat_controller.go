@@ -29,9 +29,32 @@ func (ctrl *AIController) RegisterRoutes(r *gin.RouterGroup) {
 		// 聊天
 		r.POST("/chat/generate", ctrl.AIChatGenerate)
 		r.POST("/chat/stream", ctrl.AIChatStream)
+		// 模型列表
+		r.GET("/models", ctrl.ListChatModels)
 	}
 }
 
+// ListChatModels 模型列表
+// @Summary 可用模型列表
+// @Description 列出已注册的聊天模型、支持的响应类型、上下文窗口、工具调用能力及当前用户是否可用
+// @Tags ai_chat模块
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.BaseResponse[[]vo.ChatModelVO]
+// @Router /ai_chat/models [get]
+func (ctrl *AIController) ListChatModels(c *gin.Context) {
+	models, err := ctrl.aiChatService.ListChatModels(c.Request.Context())
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusOK, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusOK, common.Error(exception.SystemError))
+		return
+	}
+	c.JSON(http.StatusOK, common.Success(models))
+}
+
 // AIChatGenerate聊天
 // @Summary 聊天接口(非流式)
 // @Description 聊天接口(非流式)
@@ -39,7 +62,7 @@ func (ctrl *AIController) RegisterRoutes(r *gin.RouterGroup) {
 // @Accept json
 // @Produce json
 // @Param request body vo.AIChatRequest true "聊天请求(非流式)"
-// @Success 200 {object} common.BaseResponse[string]
+// @Success 200 {object} common.BaseResponse[vo.AIChatResponse]
 // @Router /ai_chat/chat/generate [post]
 func (ctrl *AIController) AIChatGenerate(c *gin.Context) {
 	req := &vo.AIChatRequest{}
@@ -51,6 +74,10 @@ func (ctrl *AIController) AIChatGenerate(c *gin.Context) {
 	ctx := c.Request.Context()
 	message, err := ctrl.aiChatService.ChatGenerate(ctx, req)
 	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError,
 			common.ErrorWithMessage(exception.OperationError, err.Error()))
 		return
@@ -90,16 +117,18 @@ func (ctrl *AIController) AIChatStream(c *gin.Context) {
 	c.Writer.WriteHeader(http.StatusOK)
 
 	ctx := c.Request.Context()
-	streamReader, err := ctrl.aiChatService.ChatStream(ctx, req)
+	streamReader, model, err := ctrl.aiChatService.ChatStream(ctx, req)
 	if err != nil {
 		c.SSEvent("error", gin.H{"error": err.Error()})
 		flusher.Flush()
 		return
 	}
-	// 发送开始事件
+	defer streamReader.Close()
+	// 发送开始事件，携带实际响应的模型
 	c.SSEvent("message", gin.H{
 		"type":    "start",
 		"content": "",
+		"model":   model,
 	})
 	flusher.Flush()
 
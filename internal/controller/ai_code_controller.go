@@ -1,14 +1,21 @@
 package controller
 
 import (
+	"encoding/base64"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
+	"aicode/consts"
 	"aicode/internal/common"
 	"aicode/internal/exception"
 	"aicode/internal/model/vo"
 	"aicode/internal/service"
 
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 // AIController ai控制层
@@ -29,103 +36,219 @@ func (ctrl *AICodeController) RegisterRoutes(r *gin.RouterGroup) {
 		// 代码生成
 		r.POST("/gen", ctrl.CodeGenerate)
 		r.POST("/gen/stream", ctrl.CodeGenerateStream)
+		// 流式生成任务的续传与取消
+		r.GET("/gen/resume", ctrl.ResumeCodeJob)
+		r.POST("/cancel", ctrl.CancelCodeJob)
 	}
 }
 
 // CodeGenerateStream 代码生成流式
 // @Summary 代码生成流式
-// @Description 代码生成流式
+// @Description 代码生成流式；生成以后台任务运行，客户端断开不影响生成，start 事件与 X-Job-Id 响应头携带任务id。每个事件带有递增的 SSE id，断线后可通过 /ai_code/gen/resume 续传。文件之外的文本通过 message 事件推送，解析出的文件通过 file_started、file_chunk、file_completed 事件推送，genType 为 agent 时推送 tool_call、tool_result 事件，任务被取消时推送 cancelled 事件
 // @Tags ai_code模块
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
-// @Param request body vo.AICodeRequest true "代码生成请求"
+// @Param request body vo.AICodeRequest true "代码生成请求；multipart 表单时以同名字段提交，图片以 images 文件字段上传"
 // @Success 200 {object} common.BaseResponse[string]
 // @Router /ai_code/gen/stream [post]
 func (ctrl *AICodeController) CodeGenerateStream(c *gin.Context) {
 	// 绑定请求参数
 	req := &vo.AICodeRequest{}
-	if err := c.ShouldBindJSON(req); err != nil {
+	if err := bindCodeRequest(c, req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorWithMessage(exception.ParamsError, err.Error()))
+		return
+	}
+	ctx := c.Request.Context()
+	job, err := ctrl.aiCodeService.CodeGenerateStream(ctx, req)
+	if err != nil {
+		if !startSSE(c) {
+			return
+		}
+		c.SSEvent("error", gin.H{"error": err.Error()})
+		c.Writer.Flush()
+		return
+	}
+	events, err := ctrl.aiCodeService.SubscribeCodeJob(ctx, job.JobID, 0)
+	if err != nil {
+		if !startSSE(c) {
+			return
+		}
+		c.SSEvent("error", gin.H{"error": err.Error()})
+		c.Writer.Flush()
+		return
+	}
+	c.Header("X-Job-Id", job.JobID)
+	if !startSSE(c) {
+		return
+	}
+	writeCodeJobEvents(c, events)
+}
+
+// ResumeCodeJob 续传流式代码生成任务
+// @Summary 续传代码生成任务
+// @Description 断线后重新订阅流式生成任务，从 Last-Event-ID 请求头（或 lastEventId 参数）之后的事件开始推送；任务结束后事件保留 10 分钟
+// @Tags ai_code模块
+// @Produce json
+// @Param jobId query string true "任务id"
+// @Param lastEventId query int false "已收到的最后一个事件id"
+// @Param Last-Event-ID header string false "已收到的最后一个事件id，优先于 lastEventId 参数"
+// @Success 200 {object} common.BaseResponse[string]
+// @Router /ai_code/gen/resume [get]
+func (ctrl *AICodeController) ResumeCodeJob(c *gin.Context) {
+	var req vo.CodeJobResumeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
 		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
 		return
 	}
-	// 检查是否支持流式输出
-	flusher, ok := c.Writer.(http.Flusher)
-	if !ok {
+	if header := c.GetHeader("Last-Event-ID"); header != "" {
+		lastEventId, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || lastEventId < 0 {
+			c.JSON(http.StatusBadRequest, common.ErrorWithMessage(exception.ParamsError, "Last-Event-ID 非法"))
+			return
+		}
+		req.LastEventID = lastEventId
+	}
+
+	events, err := ctrl.aiCodeService.SubscribeCodeJob(c.Request.Context(), req.JobID, req.LastEventID)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+	if !startSSE(c) {
+		return
+	}
+	writeCodeJobEvents(c, events)
+}
+
+// CancelCodeJob 取消流式代码生成任务
+// @Summary 取消代码生成任务
+// @Description 取消运行中的流式生成任务，中止上游模型调用，任务以 cancelled 事件结束
+// @Tags ai_code模块
+// @Accept json
+// @Produce json
+// @Param request body vo.CodeJobCancelRequest true "取消任务请求"
+// @Success 200 {object} common.BaseResponse[bool]
+// @Router /ai_code/cancel [post]
+func (ctrl *AICodeController) CancelCodeJob(c *gin.Context) {
+	var req vo.CodeJobCancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	result, err := ctrl.aiCodeService.CancelCodeJob(c.Request.Context(), req.JobID)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(result))
+}
+
+// startSSE 设置 SSE 响应头并写出状态码，不支持流式输出时返回 false
+func startSSE(c *gin.Context) bool {
+	if _, ok := c.Writer.(http.Flusher); !ok {
 		c.JSON(http.StatusInternalServerError,
 			common.ErrorWithMessage(exception.SystemError, "Streaming not supported"))
-		return
+		return false
 	}
 	// 设置 SSE 响应头（必须在写入任何内容之前设置）
 	c.Header("Content-Type", "text/event-stream")
 	c.Header("Cache-Control", "no-cache")
 	c.Header("Connection", "keep-alive")
 	c.Header("Access-Control-Allow-Origin", "*")
-	c.Header("Access-Control-Allow-Headers", "Content-Type")
-
+	c.Header("Access-Control-Allow-Headers", "Content-Type, Last-Event-ID")
+	c.Header("Access-Control-Expose-Headers", "X-Job-Id")
 	c.Writer.WriteHeader(http.StatusOK)
+	return true
+}
 
-	ctx := c.Request.Context()
-
-	// 初始化 channel，service 层异步将流数据写入该 channel
-	ch := make(chan vo.CodeStreamResult, 32)
-	if err := ctrl.aiCodeService.CodeGenerateStream(ctx, req, ch); err != nil {
-		c.SSEvent("error", gin.H{"error": err.Error()})
-		flusher.Flush()
-		return
-	}
-
-	// 发送开始事件
-	c.SSEvent("message", gin.H{
-		"type":    "start",
-		"content": "",
-	})
-	flusher.Flush()
-
-	// 监听 channel，将增量内容实时推送给客户端
-	for result := range ch {
-		if result.Err != nil {
-			c.SSEvent("error", gin.H{"error": result.Err.Error()})
-			flusher.Flush()
-			return
-		}
-		c.SSEvent("message", gin.H{
-			"type":    "data",
-			"content": result.Content,
+// writeCodeJobEvents 将任务事件带 id 实时推送给客户端，channel 关闭即代表任务事件已全部推送
+func writeCodeJobEvents(c *gin.Context, events <-chan vo.CodeJobEvent) {
+	for event := range events {
+		c.Render(-1, sse.Event{
+			Id:    strconv.FormatInt(event.ID, 10),
+			Event: event.Event,
+			Data:  event.Data,
 		})
-		flusher.Flush()
+		c.Writer.Flush()
 	}
-
-	// channel 关闭即代表 stream 已全部消费完毕
-	c.SSEvent("message", gin.H{
-		"type":    "end",
-		"content": "",
-	})
-	flusher.Flush()
 }
 
 // CodeGenerate 代码生成
 // @Summary 代码生成
-// @Description 代码生成
+// @Description 代码生成；可附带设计稿等图片（JSON 的 images 字段或 multipart 的 images 文件字段），仅支持图片输入的模型可用
 // @Tags ai_code模块
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
-// @Param request body vo.AICodeRequest true "代码生成请求"
-// @Success 200 {object} common.BaseResponse[string]
+// @Param request body vo.AICodeRequest true "代码生成请求；multipart 表单时以同名字段提交，图片以 images 文件字段上传"
+// @Success 200 {object} common.BaseResponse[vo.AICodeResponse]
 // @Router /ai_code/gen [post]
 func (ctrl *AICodeController) CodeGenerate(c *gin.Context) {
 	// 绑定请求参数
 	req := &vo.AICodeRequest{}
-	if err := c.ShouldBindJSON(req); err != nil {
-		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+	if err := bindCodeRequest(c, req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorWithMessage(exception.ParamsError, err.Error()))
 		return
 	}
 	ctx := c.Request.Context()
 	// 调用服务
 	message, err := ctrl.aiCodeService.CodeGenerate(ctx, req)
 	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError,
 			common.ErrorWithMessage(exception.OperationError, err.Error()))
 		return
 	}
 	c.JSON(http.StatusOK, common.Success(message))
 }
+
+// bindCodeRequest 绑定代码生成请求：JSON 请求直接绑定；multipart 表单绑定表单字段，
+// 并将 images 文件字段中上传的图片转换为 data URL 追加到 Images
+func bindCodeRequest(c *gin.Context, req *vo.AICodeRequest) error {
+	if c.ContentType() != binding.MIMEMultipartPOSTForm {
+		if err := c.ShouldBindJSON(req); err != nil {
+			return fmt.Errorf("请求参数错误")
+		}
+		return nil
+	}
+	if err := c.ShouldBind(req); err != nil {
+		return fmt.Errorf("请求参数错误")
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return fmt.Errorf("解析表单失败")
+	}
+	files := form.File["images"]
+	if len(req.Images)+len(files) > consts.CodeImageMaxCount {
+		return fmt.Errorf("最多上传 %d 张图片", consts.CodeImageMaxCount)
+	}
+	for _, fh := range files {
+		if fh.Size > consts.CodeImageMaxBytes {
+			return fmt.Errorf("图片 %s 超过 %dMB", fh.Filename, consts.CodeImageMaxBytes>>20)
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return fmt.Errorf("读取图片 %s 失败", fh.Filename)
+		}
+		data, err := io.ReadAll(io.LimitReader(f, consts.CodeImageMaxBytes+1))
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("读取图片 %s 失败", fh.Filename)
+		}
+		req.Images = append(req.Images,
+			"data:"+http.DetectContentType(data)+";base64,"+base64.StdEncoding.EncodeToString(data))
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"net/http"
+
+	"aicode/internal/common"
+	"aicode/internal/exception"
+	"aicode/internal/model/dto/apikey"
+	_ "aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApiKeyController 个人 API Key 控制层，不允许通过 API Key 访问
+type ApiKeyController struct {
+	apiKeyService service.ApiKeyService
+}
+
+// NewApiKeyController 创建个人 API Key 控制器
+func NewApiKeyController(apiKeyService service.ApiKeyService) *ApiKeyController {
+	return &ApiKeyController{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (ctrl *ApiKeyController) RegisterRoutes(r *gin.RouterGroup) {
+	r.POST("/create", ctrl.CreateApiKey)
+	r.GET("/list", ctrl.ListApiKeys)
+	r.POST("/revoke", ctrl.RevokeApiKey)
+}
+
+// CreateApiKey 创建个人 API Key
+// @Summary 创建个人 API Key
+// @Description 创建长期有效的 API Key，通过请求头 Authorization: Bearer {key} 访问授权范围内的路由组；密钥明文只在本次返回
+// @Tags API Key 模块
+// @Accept json
+// @Produce json
+// @Param request body apikey.ApiKeyCreateRequest true "API Key 创建请求"
+// @Success 200 {object} common.BaseResponse[vo.ApiKeyCreatedVO]
+// @Router /api_key/create [post]
+func (ctrl *ApiKeyController) CreateApiKey(c *gin.Context) {
+	var req apikey.ApiKeyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	result, err := ctrl.apiKeyService.CreateApiKey(c.Request.Context(), &req)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(result))
+}
+
+// ListApiKeys 查询个人 API Key 列表
+// @Summary 查询个人 API Key 列表
+// @Description 按创建时间倒序查询当前用户未吊销的 API Key，不包含密钥
+// @Tags API Key 模块
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.BaseResponse[[]vo.ApiKeyVO]
+// @Router /api_key/list [get]
+func (ctrl *ApiKeyController) ListApiKeys(c *gin.Context) {
+	apiKeys, err := ctrl.apiKeyService.ListApiKeys(c.Request.Context())
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(apiKeys))
+}
+
+// RevokeApiKey 吊销个人 API Key
+// @Summary 吊销个人 API Key
+// @Description 吊销后使用该 API Key 的请求立即失效
+// @Tags API Key 模块
+// @Accept json
+// @Produce json
+// @Param request body apikey.ApiKeyRevokeRequest true "API Key 吊销请求"
+// @Success 200 {object} common.BaseResponse[bool]
+// @Router /api_key/revoke [post]
+func (ctrl *ApiKeyController) RevokeApiKey(c *gin.Context) {
+	var req apikey.ApiKeyRevokeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	result, err := ctrl.apiKeyService.RevokeApiKey(c.Request.Context(), req.ID)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(result))
+}
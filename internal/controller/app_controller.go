@@ -0,0 +1,259 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"aicode/internal/common"
+	"aicode/internal/exception"
+	"aicode/internal/model/dto/app"
+	_ "aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// AppController 应用控制层
+type AppController struct {
+	appService service.AppService
+}
+
+// NewAppController 创建应用控制器
+func NewAppController(appService service.AppService) *AppController {
+	return &AppController{
+		appService: appService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (ctrl *AppController) RegisterRoutes(r *gin.RouterGroup) {
+	{
+		r.POST("/add", ctrl.AddApp)
+		r.POST("/update", ctrl.UpdateApp)
+		r.POST("/delete", ctrl.DeleteApp)
+		r.GET("/get/vo", ctrl.GetAppVOById)
+		r.POST("/list/page/vo", ctrl.ListAppVOByPage)
+		r.POST("/deploy", ctrl.DeployApp)
+		r.GET("/download", ctrl.DownloadApp)
+	}
+}
+
+// AddApp 创建应用
+// @Summary 创建应用
+// @Description 创建应用，归属于当前登录用户
+// @Tags 应用模块
+// @Accept json
+// @Produce json
+// @Param request body app.AppAddRequest true "应用创建请求"
+// @Success 200 {object} common.BaseResponse[int64]
+// @Router /app/add [post]
+func (ctrl *AppController) AddApp(c *gin.Context) {
+	var req app.AppAddRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	result, err := ctrl.appService.AddApp(c.Request.Context(), &req)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(result))
+}
+
+// UpdateApp 更新应用
+// @Summary 更新应用
+// @Description 更新应用信息（仅本人或管理员）
+// @Tags 应用模块
+// @Accept json
+// @Produce json
+// @Param request body app.AppUpdateRequest true "应用更新请求"
+// @Success 200 {object} common.BaseResponse[bool]
+// @Router /app/update [post]
+func (ctrl *AppController) UpdateApp(c *gin.Context) {
+	var req app.AppUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.ID <= 0 {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	result, err := ctrl.appService.UpdateApp(c.Request.Context(), &req)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(result))
+}
+
+// DeleteApp 删除应用
+// @Summary 删除应用
+// @Description 删除应用（仅本人或管理员）
+// @Tags 应用模块
+// @Accept json
+// @Produce json
+// @Param request body common.DeleteRequest true "删除请求"
+// @Success 200 {object} common.BaseResponse[bool]
+// @Router /app/delete [post]
+func (ctrl *AppController) DeleteApp(c *gin.Context) {
+	var req common.DeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.ID <= 0 {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	result, err := ctrl.appService.DeleteApp(c.Request.Context(), req.ID)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(result))
+}
+
+// GetAppVOById 根据 id 获取应用详情
+// @Summary 根据ID获取应用
+// @Description 根据ID获取应用详情（仅本人或管理员）
+// @Tags 应用模块
+// @Accept json
+// @Produce json
+// @Param id query int64 true "应用ID"
+// @Success 200 {object} common.BaseResponse[vo.AppVO]
+// @Router /app/get/vo [get]
+func (ctrl *AppController) GetAppVOById(c *gin.Context) {
+	idStr := c.Query("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	appVO, err := ctrl.appService.GetAppVO(c.Request.Context(), id)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(appVO))
+}
+
+// ListAppVOByPage 分页获取应用列表
+// @Summary 分页获取应用列表
+// @Description 分页查询应用列表，普通用户仅能查看自己的应用
+// @Tags 应用模块
+// @Accept json
+// @Produce json
+// @Param request body app.AppQueryRequest true "应用查询请求"
+// @Success 200 {object} common.BaseResponse[common.MapResponse]
+// @Router /app/list/page/vo [post]
+func (ctrl *AppController) ListAppVOByPage(c *gin.Context) {
+	var req app.AppQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	appVOList, total, err := ctrl.appService.ListAppVOByPage(c.Request.Context(), &req)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	// 构造分页响应
+	pageResponse := map[string]interface{}{
+		"records":  appVOList,
+		"total":    total,
+		"pageNum":  req.PageNum,
+		"pageSize": req.PageSize,
+	}
+
+	c.JSON(http.StatusOK, common.Success(pageResponse))
+}
+
+// DeployApp 部署应用
+// @Summary 部署应用
+// @Description 将应用已生成的代码部署为静态站点（仅本人），返回访问地址；重复部署沿用原地址
+// @Tags 应用模块
+// @Accept json
+// @Produce json
+// @Param request body app.AppDeployRequest true "应用部署请求"
+// @Success 200 {object} common.BaseResponse[string]
+// @Router /app/deploy [post]
+func (ctrl *AppController) DeployApp(c *gin.Context) {
+	var req app.AppDeployRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.AppID <= 0 {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	result, err := ctrl.appService.DeployApp(c.Request.Context(), req.AppID)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(result))
+}
+
+// DownloadApp 下载应用代码
+// @Summary 下载应用代码
+// @Description 将应用当前代码或指定版本打包为 zip 下载（仅本人），不包含隐藏的内部文件
+// @Tags 应用模块
+// @Produce application/zip
+// @Param appId query int64 true "应用ID"
+// @Param version query int false "版本号，为空时下载当前代码"
+// @Success 200 {file} file "zip 压缩包"
+// @Router /app/download [get]
+func (ctrl *AppController) DownloadApp(c *gin.Context) {
+	var req app.AppDownloadRequest
+	if err := c.ShouldBindQuery(&req); err != nil || req.AppID <= 0 {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	fileName, writeZip, err := ctrl.appService.DownloadApp(c.Request.Context(), req.AppID, req.Version)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	// 响应头写出后无法再返回 JSON 错误，打包失败时只记录日志，客户端收到的压缩包不完整
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Status(http.StatusOK)
+	if err := writeZip(c.Writer); err != nil {
+		logrus.Errorf("打包下载应用代码失败, appId=%d: %v", req.AppID, err)
+	}
+}
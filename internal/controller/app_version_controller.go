@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"net/http"
+
+	"aicode/internal/common"
+	"aicode/internal/exception"
+	"aicode/internal/model/dto/appversion"
+	_ "aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AppVersionController 应用代码版本控制层
+type AppVersionController struct {
+	appVersionService service.AppVersionService
+}
+
+// NewAppVersionController 创建应用代码版本控制器
+func NewAppVersionController(appVersionService service.AppVersionService) *AppVersionController {
+	return &AppVersionController{
+		appVersionService: appVersionService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (ctrl *AppVersionController) RegisterRoutes(r *gin.RouterGroup) {
+	{
+		r.GET("/list", ctrl.ListVersions)
+		r.GET("/diff", ctrl.DiffVersions)
+		r.POST("/rollback", ctrl.Rollback)
+	}
+}
+
+// ListVersions 查询应用版本列表
+// @Summary 查询应用版本列表
+// @Description 按版本号倒序列出应用的全部代码版本（仅本人）
+// @Tags 应用版本模块
+// @Accept json
+// @Produce json
+// @Param appId query int64 true "应用ID"
+// @Success 200 {object} common.BaseResponse[[]vo.AppVersionVO]
+// @Router /app_version/list [get]
+func (ctrl *AppVersionController) ListVersions(c *gin.Context) {
+	var req appversion.AppVersionListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	versions, err := ctrl.appVersionService.ListVersions(c.Request.Context(), req.AppID)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(versions))
+}
+
+// DiffVersions 查询两个版本之间的差异
+// @Summary 查询版本差异
+// @Description 比较应用的两个版本，返回各变更文件的 unified diff（仅本人）
+// @Tags 应用版本模块
+// @Accept json
+// @Produce json
+// @Param appId query int64 true "应用ID"
+// @Param from query int true "起始版本号"
+// @Param to query int true "目标版本号"
+// @Success 200 {object} common.BaseResponse[vo.AppVersionDiffVO]
+// @Router /app_version/diff [get]
+func (ctrl *AppVersionController) DiffVersions(c *gin.Context) {
+	var req appversion.AppVersionDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	diff, err := ctrl.appVersionService.DiffVersions(c.Request.Context(), req.AppID, req.From, req.To)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(diff))
+}
+
+// Rollback 回滚应用到指定版本
+// @Summary 回滚应用版本
+// @Description 将应用代码恢复为指定版本，回滚结果记录为一个新版本，返回新版本号（仅本人）
+// @Tags 应用版本模块
+// @Accept json
+// @Produce json
+// @Param request body appversion.AppVersionRollbackRequest true "版本回滚请求"
+// @Success 200 {object} common.BaseResponse[int]
+// @Router /app_version/rollback [post]
+func (ctrl *AppVersionController) Rollback(c *gin.Context) {
+	var req appversion.AppVersionRollbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	version, err := ctrl.appVersionService.Rollback(c.Request.Context(), req.AppID, req.Version)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(version))
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"net/http"
+
+	"aicode/internal/common"
+	"aicode/internal/exception"
+	"aicode/internal/model/dto/chathistory"
+	_ "aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChatHistoryController 对话历史控制层
+type ChatHistoryController struct {
+	chatHistoryService service.ChatHistoryService
+}
+
+// NewChatHistoryController 创建对话历史控制器
+func NewChatHistoryController(chatHistoryService service.ChatHistoryService) *ChatHistoryController {
+	return &ChatHistoryController{
+		chatHistoryService: chatHistoryService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (ctrl *ChatHistoryController) RegisterRoutes(r *gin.RouterGroup) {
+	{
+		r.GET("/list", ctrl.ListChatHistory)
+	}
+}
+
+// ListChatHistory 游标分页查询对话历史
+// @Summary 查询对话历史
+// @Description 游标分页查询对话历史，按时间倒序返回；appId 与 conversationId 二选一
+// @Tags 对话历史模块
+// @Accept json
+// @Produce json
+// @Param appId query int64 false "应用ID"
+// @Param conversationId query string false "会话ID"
+// @Param cursor query int64 false "游标（上一页最后一条消息的 id）"
+// @Param pageSize query int false "页面大小"
+// @Success 200 {object} common.BaseResponse[vo.ChatHistoryCursorPageVO]
+// @Router /chat_history/list [get]
+func (ctrl *ChatHistoryController) ListChatHistory(c *gin.Context) {
+	var req chathistory.ChatHistoryQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	page, err := ctrl.chatHistoryService.ListByCursor(c.Request.Context(), &req)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(page))
+}
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"net/http"
+
+	"aicode/internal/common"
+	"aicode/internal/exception"
+	"aicode/internal/model/dto/codegenjob"
+	"aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CodeGenJobController 异步代码生成任务控制层
+type CodeGenJobController struct {
+	codeGenJobService service.CodeGenJobService
+}
+
+// NewCodeGenJobController 创建异步代码生成任务控制器
+func NewCodeGenJobController(codeGenJobService service.CodeGenJobService) *CodeGenJobController {
+	return &CodeGenJobController{
+		codeGenJobService: codeGenJobService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (ctrl *CodeGenJobController) RegisterRoutes(r *gin.RouterGroup) {
+	r.POST("/submit", ctrl.SubmitJob)
+	r.GET("/get", ctrl.GetJob)
+	r.GET("/list", ctrl.ListJobs)
+	r.POST("/cancel", ctrl.CancelJob)
+}
+
+// SubmitJob 提交异步代码生成任务
+// @Summary 提交异步代码生成任务
+// @Description 提交后立即返回任务id，任务按提交顺序在全局与单模型并发上限内执行，结果通过查询接口获取；暂不支持图片输入
+// @Tags 异步代码生成模块
+// @Accept json
+// @Produce json
+// @Param request body vo.AICodeRequest true "代码生成请求"
+// @Success 200 {object} common.BaseResponse[int64]
+// @Router /code_gen_job/submit [post]
+func (ctrl *CodeGenJobController) SubmitJob(c *gin.Context) {
+	var req vo.AICodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	id, err := ctrl.codeGenJobService.SubmitJob(c.Request.Context(), &req)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(id))
+}
+
+// GetJob 查询异步代码生成任务
+// @Summary 查询异步代码生成任务
+// @Description 查询任务状态、排队位置与结果，只能查询本人的任务（管理员除外）
+// @Tags 异步代码生成模块
+// @Accept json
+// @Produce json
+// @Param id query int true "任务id"
+// @Success 200 {object} common.BaseResponse[vo.CodeGenJobVO]
+// @Router /code_gen_job/get [get]
+func (ctrl *CodeGenJobController) GetJob(c *gin.Context) {
+	var req codegenjob.CodeGenJobGetRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	job, err := ctrl.codeGenJobService.GetJob(c.Request.Context(), req.ID)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(job))
+}
+
+// ListJobs 查询应用的异步代码生成任务列表
+// @Summary 查询应用的异步代码生成任务列表
+// @Description 按提交时间倒序查询当前用户在应用下最近的任务，不包含输出内容
+// @Tags 异步代码生成模块
+// @Accept json
+// @Produce json
+// @Param appId query int true "应用id"
+// @Success 200 {object} common.BaseResponse[[]vo.CodeGenJobVO]
+// @Router /code_gen_job/list [get]
+func (ctrl *CodeGenJobController) ListJobs(c *gin.Context) {
+	var req codegenjob.CodeGenJobListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	jobs, err := ctrl.codeGenJobService.ListJobs(c.Request.Context(), req.AppID)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(jobs))
+}
+
+// CancelJob 取消异步代码生成任务
+// @Summary 取消异步代码生成任务
+// @Description 取消排队中或执行中的任务，执行中的任务会中断模型调用，不写入应用代码；在其他服务实例执行的任务由该实例数秒内取消
+// @Tags 异步代码生成模块
+// @Accept json
+// @Produce json
+// @Param request body codegenjob.CodeGenJobCancelRequest true "任务取消请求"
+// @Success 200 {object} common.BaseResponse[bool]
+// @Router /code_gen_job/cancel [post]
+func (ctrl *CodeGenJobController) CancelJob(c *gin.Context) {
+	var req codegenjob.CodeGenJobCancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	result, err := ctrl.codeGenJobService.CancelJob(c.Request.Context(), req.ID)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(result))
+}
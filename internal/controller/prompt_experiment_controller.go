@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"net/http"
+
+	"aicode/constant"
+	"aicode/internal/common"
+	"aicode/internal/exception"
+	"aicode/internal/model/dto/promptexperiment"
+	_ "aicode/internal/model/vo"
+	"aicode/internal/router/middleware"
+	"aicode/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromptExperimentController 提示词实验控制层，全部接口仅限管理员
+type PromptExperimentController struct {
+	promptExperimentService service.PromptExperimentService
+}
+
+// NewPromptExperimentController 创建提示词实验控制器
+func NewPromptExperimentController(promptExperimentService service.PromptExperimentService) *PromptExperimentController {
+	return &PromptExperimentController{
+		promptExperimentService: promptExperimentService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (ctrl *PromptExperimentController) RegisterRoutes(r *gin.RouterGroup) {
+	admin := middleware.Permission(r, constant.PermissionPromptManage)
+	{
+		admin.POST("/create", ctrl.CreateExperiment)
+		admin.POST("/stop", ctrl.StopExperiment)
+		admin.GET("/list", ctrl.ListExperiments)
+		admin.GET("/stats", ctrl.GetStats)
+	}
+}
+
+// CreateExperiment 创建提示词实验
+// @Summary 创建提示词实验
+// @Description 对同一模板的多个版本按权重分流（仅管理员），同一用户始终分到同一组；同一模板同一时刻只能有一个进行中的实验
+// @Tags 提示词实验模块
+// @Accept json
+// @Produce json
+// @Param request body promptexperiment.PromptExperimentCreateRequest true "实验创建请求"
+// @Success 200 {object} common.BaseResponse[int64]
+// @Router /prompt_experiment/create [post]
+func (ctrl *PromptExperimentController) CreateExperiment(c *gin.Context) {
+	var req promptexperiment.PromptExperimentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	id, err := ctrl.promptExperimentService.CreateExperiment(c.Request.Context(), &req)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(id))
+}
+
+// StopExperiment 停止提示词实验
+// @Summary 停止提示词实验
+// @Description 停止实验（仅管理员），停止后模板恢复使用生效版本，已记录的结果保留
+// @Tags 提示词实验模块
+// @Accept json
+// @Produce json
+// @Param request body promptexperiment.PromptExperimentStopRequest true "实验停止请求"
+// @Success 200 {object} common.BaseResponse[bool]
+// @Router /prompt_experiment/stop [post]
+func (ctrl *PromptExperimentController) StopExperiment(c *gin.Context) {
+	var req promptexperiment.PromptExperimentStopRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	result, err := ctrl.promptExperimentService.StopExperiment(c.Request.Context(), req.ID)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(result))
+}
+
+// ListExperiments 查询提示词实验列表
+// @Summary 查询提示词实验列表
+// @Description 按创建时间倒序查询全部实验（仅管理员）
+// @Tags 提示词实验模块
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.BaseResponse[[]vo.PromptExperimentVO]
+// @Router /prompt_experiment/list [get]
+func (ctrl *PromptExperimentController) ListExperiments(c *gin.Context) {
+	experiments, err := ctrl.promptExperimentService.ListExperiments(c.Request.Context())
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(experiments))
+}
+
+// GetStats 查询提示词实验结果
+// @Summary 查询提示词实验结果
+// @Description 按分组汇总实验的存储成功率、续写次数与回滚率（仅管理员）
+// @Tags 提示词实验模块
+// @Accept json
+// @Produce json
+// @Param id query int true "实验id"
+// @Success 200 {object} common.BaseResponse[vo.PromptExperimentStatsVO]
+// @Router /prompt_experiment/stats [get]
+func (ctrl *PromptExperimentController) GetStats(c *gin.Context) {
+	var req promptexperiment.PromptExperimentStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	stats, err := ctrl.promptExperimentService.GetStats(c.Request.Context(), req.ID)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(stats))
+}
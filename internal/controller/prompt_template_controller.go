@@ -0,0 +1,238 @@
+package controller
+
+import (
+	"net/http"
+
+	"aicode/constant"
+	"aicode/internal/common"
+	"aicode/internal/exception"
+	"aicode/internal/model/dto/prompttemplate"
+	_ "aicode/internal/model/vo"
+	"aicode/internal/router/middleware"
+	"aicode/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromptTemplateController 提示词模板控制层，全部接口仅限管理员
+type PromptTemplateController struct {
+	promptTemplateService service.PromptTemplateService
+}
+
+// NewPromptTemplateController 创建提示词模板控制器
+func NewPromptTemplateController(promptTemplateService service.PromptTemplateService) *PromptTemplateController {
+	return &PromptTemplateController{
+		promptTemplateService: promptTemplateService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (ctrl *PromptTemplateController) RegisterRoutes(r *gin.RouterGroup) {
+	admin := middleware.Permission(r, constant.PermissionPromptManage)
+	{
+		admin.POST("/add", ctrl.AddTemplate)
+		admin.POST("/update", ctrl.UpdateTemplate)
+		admin.POST("/activate", ctrl.ActivateVersion)
+		admin.POST("/delete", ctrl.DeleteTemplate)
+		admin.GET("/get", ctrl.GetTemplate)
+		admin.GET("/list", ctrl.ListTemplates)
+		admin.GET("/versions", ctrl.ListVersions)
+	}
+}
+
+// AddTemplate 创建提示词模板
+// @Summary 创建提示词模板
+// @Description 创建提示词模板（仅管理员），模板使用 Go text/template 语法，可引用 {{.GenType}} {{.Model}} {{.AppName}} 等变量
+// @Tags 提示词模板模块
+// @Accept json
+// @Produce json
+// @Param request body prompttemplate.PromptTemplateAddRequest true "模板创建请求"
+// @Success 200 {object} common.BaseResponse[int]
+// @Router /prompt_template/add [post]
+func (ctrl *PromptTemplateController) AddTemplate(c *gin.Context) {
+	var req prompttemplate.PromptTemplateAddRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	version, err := ctrl.promptTemplateService.AddTemplate(c.Request.Context(), &req)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(version))
+}
+
+// UpdateTemplate 修改提示词模板
+// @Summary 修改提示词模板
+// @Description 将修改保存为模板的新版本并立即生效（仅管理员），返回新版本号
+// @Tags 提示词模板模块
+// @Accept json
+// @Produce json
+// @Param request body prompttemplate.PromptTemplateUpdateRequest true "模板修改请求"
+// @Success 200 {object} common.BaseResponse[int]
+// @Router /prompt_template/update [post]
+func (ctrl *PromptTemplateController) UpdateTemplate(c *gin.Context) {
+	var req prompttemplate.PromptTemplateUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	version, err := ctrl.promptTemplateService.UpdateTemplate(c.Request.Context(), &req)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(version))
+}
+
+// ActivateVersion 切换提示词模板生效版本
+// @Summary 切换模板生效版本
+// @Description 将模板的指定版本设为生效版本（仅管理员），可用于回滚
+// @Tags 提示词模板模块
+// @Accept json
+// @Produce json
+// @Param request body prompttemplate.PromptTemplateActivateRequest true "版本生效请求"
+// @Success 200 {object} common.BaseResponse[bool]
+// @Router /prompt_template/activate [post]
+func (ctrl *PromptTemplateController) ActivateVersion(c *gin.Context) {
+	var req prompttemplate.PromptTemplateActivateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	result, err := ctrl.promptTemplateService.ActivateVersion(c.Request.Context(), req.Name, req.Version)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(result))
+}
+
+// DeleteTemplate 删除提示词模板
+// @Summary 删除提示词模板
+// @Description 删除模板的全部版本（仅管理员），删除后使用该模板的代码生成请求将失败
+// @Tags 提示词模板模块
+// @Accept json
+// @Produce json
+// @Param request body prompttemplate.PromptTemplateDeleteRequest true "模板删除请求"
+// @Success 200 {object} common.BaseResponse[bool]
+// @Router /prompt_template/delete [post]
+func (ctrl *PromptTemplateController) DeleteTemplate(c *gin.Context) {
+	var req prompttemplate.PromptTemplateDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	result, err := ctrl.promptTemplateService.DeleteTemplate(c.Request.Context(), req.Name)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(result))
+}
+
+// GetTemplate 查询提示词模板
+// @Summary 查询提示词模板
+// @Description 查询模板的指定版本，未指定版本时查询生效版本（仅管理员）
+// @Tags 提示词模板模块
+// @Accept json
+// @Produce json
+// @Param name query string true "模板名称"
+// @Param version query int false "版本号"
+// @Success 200 {object} common.BaseResponse[vo.PromptTemplateVO]
+// @Router /prompt_template/get [get]
+func (ctrl *PromptTemplateController) GetTemplate(c *gin.Context) {
+	var req prompttemplate.PromptTemplateGetRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	template, err := ctrl.promptTemplateService.GetTemplate(c.Request.Context(), req.Name, req.Version)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(template))
+}
+
+// ListTemplates 查询提示词模板列表
+// @Summary 查询提示词模板列表
+// @Description 查询全部模板的生效版本（仅管理员）
+// @Tags 提示词模板模块
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.BaseResponse[[]vo.PromptTemplateVO]
+// @Router /prompt_template/list [get]
+func (ctrl *PromptTemplateController) ListTemplates(c *gin.Context) {
+	templates, err := ctrl.promptTemplateService.ListTemplates(c.Request.Context())
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(templates))
+}
+
+// ListVersions 查询提示词模板版本列表
+// @Summary 查询模板版本列表
+// @Description 按版本号倒序查询模板的全部版本（仅管理员）
+// @Tags 提示词模板模块
+// @Accept json
+// @Produce json
+// @Param name query string true "模板名称"
+// @Success 200 {object} common.BaseResponse[[]vo.PromptTemplateVO]
+// @Router /prompt_template/versions [get]
+func (ctrl *PromptTemplateController) ListVersions(c *gin.Context) {
+	name := c.Query("name")
+	if name == "" {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	templates, err := ctrl.promptTemplateService.ListVersions(c.Request.Context(), name)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(templates))
+}
@@ -4,12 +4,12 @@ import (
 	"net/http"
 	"strconv"
 
-	"aicode/constant"
 	"aicode/internal/common"
 	"aicode/internal/exception"
 	"aicode/internal/model/dto/user"
 	_ "aicode/internal/model/entity"
 	_ "aicode/internal/model/vo"
+	"aicode/internal/router/middleware"
 	"aicode/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -17,32 +17,48 @@ import (
 
 // UserController 用户控制层
 type UserController struct {
-	userService service.UserService
+	userService         service.UserService
+	tokenUsageService   service.TokenUsageService
+	authTokenService    service.AuthTokenService
+	loginAttemptService service.LoginAttemptService
 }
 
 // NewUserController 创建用户控制器
-func NewUserController(userService service.UserService) *UserController {
+func NewUserController(userService service.UserService,
+	tokenUsageService service.TokenUsageService, authTokenService service.AuthTokenService,
+	loginAttemptService service.LoginAttemptService) *UserController {
 	return &UserController{
-		userService: userService,
+		userService:         userService,
+		tokenUsageService:   tokenUsageService,
+		authTokenService:    authTokenService,
+		loginAttemptService: loginAttemptService,
 	}
 }
 
 // RegisterRoutes 注册路由
 func (ctrl *UserController) RegisterRoutes(r *gin.RouterGroup) {
+	public := middleware.Public(r)
+	{
+		public.POST("/register", ctrl.UserRegister)
+		public.POST("/login", ctrl.UserLogin)
+		public.POST("/token/refresh", ctrl.RefreshToken)
+		public.POST("/token/revoke", ctrl.RevokeToken)
+	}
 	{
-		r.POST("/register", ctrl.UserRegister)
-		r.POST("/login", ctrl.UserLogin)
 		r.GET("/get/login", ctrl.GetLoginUser)
 		r.POST("/logout", ctrl.UserLogout)
+		r.GET("/usage", ctrl.GetUsageSummary)
 	}
+	admin := middleware.Admin(r)
 	{
-		// 管理员接口（后续需要添加权限验证中间件）
-		r.POST("/add", ctrl.AddUser)
-		r.GET("/get", ctrl.GetUserById)
-		r.GET("/get/vo", ctrl.GetUserVOById)
-		r.POST("/delete", ctrl.DeleteUser)
-		r.POST("/update", ctrl.UpdateUser)
-		r.POST("/list/page/vo", ctrl.ListUserVOByPage)
+		admin.POST("/add", ctrl.AddUser)
+		admin.GET("/get", ctrl.GetUserById)
+		admin.GET("/get/vo", ctrl.GetUserVOById)
+		admin.POST("/delete", ctrl.DeleteUser)
+		admin.POST("/update", ctrl.UpdateUser)
+		admin.POST("/list/page/vo", ctrl.ListUserVOByPage)
+		admin.POST("/unlock", ctrl.UnlockUser)
+		admin.GET("/login_attempt/list", ctrl.ListLoginAttempts)
 	}
 }
 
@@ -77,7 +93,7 @@ func (ctrl *UserController) UserRegister(c *gin.Context) {
 
 // UserLogin 用户登录
 // @Summary 用户登录
-// @Description 用户登录接口
+// @Description 用户登录接口，同时写入 cookie session 并签发 access/refresh 令牌；连续登录失败后需等待逐次翻倍的时间（错误码 42900），达到阈值后账号被锁定（错误码 42300）
 // @Tags 用户模块
 // @Accept json
 // @Produce json
@@ -101,9 +117,78 @@ func (ctrl *UserController) UserLogin(c *gin.Context) {
 		return
 	}
 
+	token, err := ctrl.authTokenService.IssueTokens(c.Request.Context(), loginUserVO.ID)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+	loginUserVO.Token = token
+
 	c.JSON(http.StatusOK, common.Success(loginUserVO))
 }
 
+// RefreshToken 刷新令牌
+// @Summary 刷新令牌
+// @Description 使用 refresh 令牌换取新的 access/refresh 令牌，旧的 refresh 令牌随即失效；无需登录
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param request body user.TokenRefreshRequest true "令牌刷新请求"
+// @Success 200 {object} common.BaseResponse[vo.AuthTokenVO]
+// @Router /user/token/refresh [post]
+func (ctrl *UserController) RefreshToken(c *gin.Context) {
+	var req user.TokenRefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	token, err := ctrl.authTokenService.RefreshTokens(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(token))
+}
+
+// RevokeToken 吊销令牌
+// @Summary 吊销令牌
+// @Description 吊销 refresh 令牌所属的令牌会话，会话内的 access/refresh 令牌全部失效；无需登录
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param request body user.TokenRevokeRequest true "令牌吊销请求"
+// @Success 200 {object} common.BaseResponse[bool]
+// @Router /user/token/revoke [post]
+func (ctrl *UserController) RevokeToken(c *gin.Context) {
+	var req user.TokenRevokeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	result, err := ctrl.authTokenService.RevokeToken(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(result))
+}
+
 // GetLoginUser 获取当前登录用户
 // @Summary 获取当前登录用户
 // @Description 获取当前登录用户信息
@@ -126,6 +211,28 @@ func (ctrl *UserController) GetLoginUser(c *gin.Context) {
 	c.JSON(http.StatusOK, common.Success(ctrl.userService.GetLoginUserVO(loginUser)))
 }
 
+// GetUsageSummary 获取当前用户的 token 用量
+// @Summary 获取当前用户的 token 用量
+// @Description 获取当前登录用户今日、本月的 token 用量、配额及按模型汇总的明细
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.BaseResponse[vo.TokenUsageSummaryVO]
+// @Router /user/usage [get]
+func (ctrl *UserController) GetUsageSummary(c *gin.Context) {
+	result, err := ctrl.tokenUsageService.GetUsageSummary(c.Request.Context())
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(result))
+}
+
 // UserLogout 用户注销
 // @Summary 用户注销
 // @Description 用户注销登录
@@ -158,7 +265,6 @@ func (ctrl *UserController) UserLogout(c *gin.Context) {
 // @Success 200 {object} common.BaseResponse[int64]
 // @Router /user/add [post]
 func (ctrl *UserController) AddUser(c *gin.Context) {
-	// TODO: 添加管理员权限检查中间件
 	var req user.UserAddRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
@@ -188,7 +294,6 @@ func (ctrl *UserController) AddUser(c *gin.Context) {
 // @Success 200 {object} common.BaseResponse[entity.User]
 // @Router /user/get [get]
 func (ctrl *UserController) GetUserById(c *gin.Context) {
-	// TODO: 添加管理员权限检查中间件
 	idStr := c.Query("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil || id <= 0 {
@@ -249,7 +354,6 @@ func (ctrl *UserController) GetUserVOById(c *gin.Context) {
 // @Success 200 {object} common.BaseResponse[bool]
 // @Router /user/delete [post]
 func (ctrl *UserController) DeleteUser(c *gin.Context) {
-	// TODO: 添加管理员权限检查中间件
 	var req common.DeleteRequest
 	if err := c.ShouldBindJSON(&req); err != nil || req.ID <= 0 {
 		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
@@ -279,7 +383,6 @@ func (ctrl *UserController) DeleteUser(c *gin.Context) {
 // @Success 200 {object} common.BaseResponse[bool]
 // @Router /user/update [post]
 func (ctrl *UserController) UpdateUser(c *gin.Context) {
-	// TODO: 添加管理员权限检查中间件
 	var req user.UserUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil || req.ID == 0 {
 		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
@@ -309,7 +412,6 @@ func (ctrl *UserController) UpdateUser(c *gin.Context) {
 // @Success 200 {object} common.BaseResponse[common.MapResponse]
 // @Router /user/list/page/vo [post]
 func (ctrl *UserController) ListUserVOByPage(c *gin.Context) {
-	// TODO: 添加管理员权限检查中间件
 	var req user.UserQueryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
@@ -337,34 +439,61 @@ func (ctrl *UserController) ListUserVOByPage(c *gin.Context) {
 	c.JSON(http.StatusOK, common.Success(pageResponse))
 }
 
-// CheckAdminAuth 检查管理员权限的中间件（待实现）
-func CheckAdminAuth() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// 从上下文获取登录用户
-		userObj, exists := c.Get(constant.UserLoginState)
-		if !exists {
-			c.JSON(http.StatusUnauthorized, common.Error(exception.NotLoginError))
-			c.Abort()
+// UnlockUser 解锁账号（仅管理员）
+// @Summary 解锁账号
+// @Description 解除因连续登录失败导致的账号锁定，并将连续失败次数清零（仅管理员）
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param request body user.UserUnlockRequest true "解锁账号请求"
+// @Success 200 {object} common.BaseResponse[bool]
+// @Router /user/unlock [post]
+func (ctrl *UserController) UnlockUser(c *gin.Context) {
+	var req user.UserUnlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	result, err := ctrl.loginAttemptService.UnlockUser(c.Request.Context(), req.ID)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
 			return
 		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
 
-		// 类型断言获取用户实体
-		// loginUser, ok := userObj.(*entity.User)
-		// if !ok || loginUser == nil {
-		// 	c.JSON(http.StatusOK, common.Error(exception.NotLoginError))
-		// 	c.Abort()
-		// 	return
-		// }
-
-		// // 检查用户角色
-		// if loginUser.UserRole != constant.AdminRole {
-		// 	c.JSON(http.StatusOK, common.Error(exception.NoAuthError))
-		// 	c.Abort()
-		// 	return
-		// }
-
-		// TODO: 完善权限检查逻辑
-		_ = userObj
-		c.Next()
+	c.JSON(http.StatusOK, common.Success(result))
+}
+
+// ListLoginAttempts 查询用户的登录记录（仅管理员）
+// @Summary 查询用户的登录记录
+// @Description 按时间倒序查询用户账号最近 100 条登录记录，包括失败、被拒绝与解锁记录（仅管理员）
+// @Tags 用户模块
+// @Accept json
+// @Produce json
+// @Param id query int64 true "用户ID"
+// @Success 200 {object} common.BaseResponse[[]vo.LoginAttemptVO]
+// @Router /user/login_attempt/list [get]
+func (ctrl *UserController) ListLoginAttempts(c *gin.Context) {
+	idStr := c.Query("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	attempts, err := ctrl.loginAttemptService.ListLoginAttempts(c.Request.Context(), id)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
 	}
+
+	c.JSON(http.StatusOK, common.Success(attempts))
 }
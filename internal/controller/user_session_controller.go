@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"net/http"
+
+	"aicode/constant"
+	"aicode/internal/common"
+	"aicode/internal/exception"
+	"aicode/internal/model/dto/usersession"
+	_ "aicode/internal/model/vo"
+	"aicode/internal/router/middleware"
+	"aicode/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserSessionController 用户登录 session 管理控制层，全部接口需要 session:manage 权限
+type UserSessionController struct {
+	userSessionService service.UserSessionService
+}
+
+// NewUserSessionController 创建用户登录 session 管理控制器
+func NewUserSessionController(userSessionService service.UserSessionService) *UserSessionController {
+	return &UserSessionController{
+		userSessionService: userSessionService,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (ctrl *UserSessionController) RegisterRoutes(r *gin.RouterGroup) {
+	admin := middleware.Permission(r, constant.PermissionSessionManage)
+	{
+		admin.GET("/list", ctrl.ListUserSessions)
+		admin.POST("/expire", ctrl.ExpireUserSessions)
+	}
+}
+
+// ListUserSessions 查询用户的登录 session
+// @Summary 查询用户的登录 session
+// @Description 按最近访问时间倒序查询用户未过期的登录 session（仅管理员）
+// @Tags 用户 session 模块
+// @Accept json
+// @Produce json
+// @Param userId query int true "用户id"
+// @Success 200 {object} common.BaseResponse[[]vo.UserSessionVO]
+// @Router /user_session/list [get]
+func (ctrl *UserSessionController) ListUserSessions(c *gin.Context) {
+	var req usersession.UserSessionListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	sessions, err := ctrl.userSessionService.ListUserSessions(c.Request.Context(), req.UserID)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(sessions))
+}
+
+// ExpireUserSessions 强制失效用户的登录 session
+// @Summary 强制失效用户的登录 session
+// @Description 失效指定 session；不指定时失效该用户的全部 session 并吊销全部令牌会话（仅管理员），返回失效的 session 数量
+// @Tags 用户 session 模块
+// @Accept json
+// @Produce json
+// @Param request body usersession.UserSessionExpireRequest true "session 失效请求"
+// @Success 200 {object} common.BaseResponse[int64]
+// @Router /user_session/expire [post]
+func (ctrl *UserSessionController) ExpireUserSessions(c *gin.Context) {
+	var req usersession.UserSessionExpireRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(exception.ParamsError))
+		return
+	}
+
+	count, err := ctrl.userSessionService.ExpireUserSessions(c.Request.Context(), &req)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			c.JSON(http.StatusBadRequest, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.Error(exception.SystemError))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(count))
+}
@@ -32,8 +32,10 @@ var (
 	NotLoginError     = BaseErrorCode{40100, "未登录"}
 	NoAuthError       = BaseErrorCode{40101, "无权限"}
 	TooManyRequest    = BaseErrorCode{42900, "请求过于频繁"}
+	QuotaExceeded     = BaseErrorCode{42901, "Token 用量已超出配额"}
 	NotFoundError     = BaseErrorCode{40400, "请求数据不存在"}
 	ForbiddenError    = BaseErrorCode{40300, "禁止访问"}
+	AccountLocked     = BaseErrorCode{42300, "账号已锁定"}
 	SystemError       = BaseErrorCode{50000, "系统内部异常"}
 	OperationError    = BaseErrorCode{50001, "操作失败"}
 )
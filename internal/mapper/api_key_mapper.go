@@ -0,0 +1,69 @@
+package mapper
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"aicode/internal/model/entity"
+)
+
+// ApiKeyMapper 个人 API Key 数据访问层
+type ApiKeyMapper struct {
+	DB *gorm.DB
+}
+
+// NewApiKeyMapper 创建 API Key Mapper
+func NewApiKeyMapper(db *gorm.DB) *ApiKeyMapper {
+	return &ApiKeyMapper{DB: db}
+}
+
+// Save 保存 API Key
+func (m *ApiKeyMapper) Save(apiKey *entity.ApiKey) error {
+	return m.DB.Create(apiKey).Error
+}
+
+// GetById 根据id查询 API Key
+func (m *ApiKeyMapper) GetById(id int64) (*entity.ApiKey, error) {
+	var apiKey entity.ApiKey
+	err := m.DB.Where("id = ?", id).First(&apiKey).Error
+	if err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// GetByHash 根据密钥哈希查询 API Key
+func (m *ApiKeyMapper) GetByHash(keyHash string) (*entity.ApiKey, error) {
+	var apiKey entity.ApiKey
+	err := m.DB.Where("key_hash = ?", keyHash).First(&apiKey).Error
+	if err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// ListByUserId 按创建时间倒序查询用户未吊销的 API Key
+func (m *ApiKeyMapper) ListByUserId(userId int64) ([]entity.ApiKey, error) {
+	var apiKeys []entity.ApiKey
+	err := m.DB.Where("user_id = ? AND revoked = 0", userId).Order("id DESC").Find(&apiKeys).Error
+	return apiKeys, err
+}
+
+// CountByUserId 统计用户未吊销的 API Key 数量
+func (m *ApiKeyMapper) CountByUserId(userId int64) (int64, error) {
+	var count int64
+	err := m.DB.Model(&entity.ApiKey{}).Where("user_id = ? AND revoked = 0", userId).Count(&count).Error
+	return count, err
+}
+
+// Revoke 吊销 API Key
+func (m *ApiKeyMapper) Revoke(id int64) error {
+	return m.DB.Model(&entity.ApiKey{}).Where("id = ?", id).Update("revoked", 1).Error
+}
+
+// TouchLastUsed 更新最近一次使用时间
+func (m *ApiKeyMapper) TouchLastUsed(id int64, usedTime time.Time) error {
+	return m.DB.Model(&entity.ApiKey{}).Where("id = ?", id).
+		UpdateColumn("last_used_time", usedTime).Error
+}
@@ -0,0 +1,67 @@
+package mapper
+
+import (
+	"gorm.io/gorm"
+
+	"aicode/internal/model/entity"
+)
+
+// AppMapper 应用数据访问层
+type AppMapper struct {
+	DB *gorm.DB
+}
+
+// NewAppMapper 创建应用Mapper
+func NewAppMapper(db *gorm.DB) *AppMapper {
+	return &AppMapper{DB: db}
+}
+
+// Save 保存应用
+func (m *AppMapper) Save(app *entity.App) error {
+	return m.DB.Create(app).Error
+}
+
+// GetById 根据ID查询应用
+func (m *AppMapper) GetById(id int64) (*entity.App, error) {
+	var app entity.App
+	err := m.DB.Where("id = ? AND is_delete = 0", id).First(&app).Error
+	if err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// UpdateById 根据ID更新应用
+func (m *AppMapper) UpdateById(app *entity.App) error {
+	return m.DB.Model(&entity.App{}).Where("id = ?", app.ID).Updates(app).Error
+}
+
+// ExistsByDeployKey 判断部署标识是否已被占用
+func (m *AppMapper) ExistsByDeployKey(deployKey string) (bool, error) {
+	var count int64
+	err := m.DB.Model(&entity.App{}).Where("deploy_key = ?", deployKey).Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteById 根据ID删除应用（逻辑删除）
+func (m *AppMapper) DeleteById(id int64) error {
+	return m.DB.Model(&entity.App{}).Where("id = ?", id).Update("is_delete", 1).Error
+}
+
+// Page 分页查询应用
+func (m *AppMapper) Page(offset, limit int, query *gorm.DB) ([]entity.App, int64, error) {
+	var apps []entity.App
+	var total int64
+
+	// 统计总数
+	if err := query.Model(&entity.App{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	if err := query.Offset(offset).Limit(limit).Find(&apps).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return apps, total, nil
+}
@@ -0,0 +1,54 @@
+package mapper
+
+import (
+	"gorm.io/gorm"
+
+	"aicode/internal/model/entity"
+)
+
+// AppVersionMapper 应用代码版本数据访问层
+type AppVersionMapper struct {
+	DB *gorm.DB
+}
+
+// NewAppVersionMapper 创建应用代码版本Mapper
+func NewAppVersionMapper(db *gorm.DB) *AppVersionMapper {
+	return &AppVersionMapper{DB: db}
+}
+
+// Save 保存版本记录
+func (m *AppVersionMapper) Save(version *entity.AppVersion) error {
+	return m.DB.Create(version).Error
+}
+
+// GetMaxVersion 查询应用当前最大版本号，没有版本时返回 0
+func (m *AppVersionMapper) GetMaxVersion(appId int64) (int, error) {
+	var maxVersion int
+	err := m.DB.Model(&entity.AppVersion{}).
+		Select("COALESCE(MAX(version), 0)").
+		Where("app_id = ?", appId).
+		Scan(&maxVersion).Error
+	return maxVersion, err
+}
+
+// ListByAppId 按版本号倒序查询应用的全部版本
+func (m *AppVersionMapper) ListByAppId(appId int64) ([]entity.AppVersion, error) {
+	var versions []entity.AppVersion
+	err := m.DB.Where("app_id = ?", appId).Order("version DESC").Find(&versions).Error
+	return versions, err
+}
+
+// GetByAppIdAndVersion 查询应用的指定版本
+func (m *AppVersionMapper) GetByAppIdAndVersion(appId int64, version int) (*entity.AppVersion, error) {
+	var appVersion entity.AppVersion
+	err := m.DB.Where("app_id = ? AND version = ?", appId, version).First(&appVersion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &appVersion, nil
+}
+
+// DeleteByAppId 删除应用的全部版本记录
+func (m *AppVersionMapper) DeleteByAppId(appId int64) error {
+	return m.DB.Where("app_id = ?", appId).Delete(&entity.AppVersion{}).Error
+}
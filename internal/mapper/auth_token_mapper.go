@@ -0,0 +1,56 @@
+package mapper
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"aicode/internal/model/entity"
+)
+
+// AuthTokenMapper 令牌会话数据访问层
+type AuthTokenMapper struct {
+	DB *gorm.DB
+}
+
+// NewAuthTokenMapper 创建令牌会话Mapper
+func NewAuthTokenMapper(db *gorm.DB) *AuthTokenMapper {
+	return &AuthTokenMapper{DB: db}
+}
+
+// Save 保存令牌会话
+func (m *AuthTokenMapper) Save(token *entity.AuthToken) error {
+	return m.DB.Create(token).Error
+}
+
+// GetBySessionId 根据会话id查询令牌会话
+func (m *AuthTokenMapper) GetBySessionId(sessionId string) (*entity.AuthToken, error) {
+	var token entity.AuthToken
+	err := m.DB.Where("session_id = ?", sessionId).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RotateRefresh 轮换 refresh 令牌 id，只在会话未吊销且当前 id 仍为 oldJti 时更新，并发刷新时只有一个成功
+func (m *AuthTokenMapper) RotateRefresh(sessionId, oldJti, newJti string) (bool, error) {
+	result := m.DB.Model(&entity.AuthToken{}).
+		Where("session_id = ? AND refresh_jti = ? AND revoked = 0", sessionId, oldJti).
+		Updates(map[string]any{"refresh_jti": newJti, "last_used_time": time.Now()})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Revoke 吊销令牌会话
+func (m *AuthTokenMapper) Revoke(sessionId string) error {
+	return m.DB.Model(&entity.AuthToken{}).
+		Where("session_id = ?", sessionId).
+		Update("revoked", 1).Error
+}
+
+// RevokeByUserId 吊销用户的全部令牌会话
+func (m *AuthTokenMapper) RevokeByUserId(userId int64) error {
+	return m.DB.Model(&entity.AuthToken{}).
+		Where("user_id = ? AND revoked = 0", userId).
+		Update("revoked", 1).Error
+}
@@ -0,0 +1,40 @@
+package mapper
+
+import (
+	"gorm.io/gorm"
+
+	"aicode/internal/model/entity"
+)
+
+// ChatHistoryMapper 对话历史数据访问层
+type ChatHistoryMapper struct {
+	DB *gorm.DB
+}
+
+// NewChatHistoryMapper 创建对话历史Mapper
+func NewChatHistoryMapper(db *gorm.DB) *ChatHistoryMapper {
+	return &ChatHistoryMapper{DB: db}
+}
+
+// Save 保存对话消息
+func (m *ChatHistoryMapper) Save(history *entity.ChatHistory) error {
+	return m.DB.Create(history).Error
+}
+
+// ListBeforeId 按 id 倒序查询游标之前的消息，cursorId <= 0 表示从最新一条开始
+func (m *ChatHistoryMapper) ListBeforeId(query *gorm.DB, cursorId int64, limit int) ([]entity.ChatHistory, error) {
+	var histories []entity.ChatHistory
+	if cursorId > 0 {
+		query = query.Where("id < ?", cursorId)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&histories).Error
+	if err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
+
+// DeleteByAppId 删除应用下的全部对话消息（逻辑删除）
+func (m *ChatHistoryMapper) DeleteByAppId(appId int64) error {
+	return m.DB.Model(&entity.ChatHistory{}).Where("app_id = ?", appId).Update("is_delete", 1).Error
+}
@@ -0,0 +1,140 @@
+package mapper
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"aicode/consts"
+	"aicode/internal/model/entity"
+)
+
+// CodeGenJobMapper 异步代码生成任务数据访问层
+type CodeGenJobMapper struct {
+	DB *gorm.DB
+}
+
+// NewCodeGenJobMapper 创建异步代码生成任务Mapper
+func NewCodeGenJobMapper(db *gorm.DB) *CodeGenJobMapper {
+	return &CodeGenJobMapper{DB: db}
+}
+
+// Save 保存任务
+func (m *CodeGenJobMapper) Save(job *entity.CodeGenJob) error {
+	return m.DB.Create(job).Error
+}
+
+// GetById 根据id查询任务
+func (m *CodeGenJobMapper) GetById(id int64) (*entity.CodeGenJob, error) {
+	var job entity.CodeGenJob
+	err := m.DB.Where("id = ?", id).First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListByUserAndApp 按提交时间倒序查询用户在应用下最近的任务，不加载输出内容
+func (m *CodeGenJobMapper) ListByUserAndApp(userId, appId int64, limit int) ([]entity.CodeGenJob, error) {
+	var jobs []entity.CodeGenJob
+	err := m.DB.Omit("content").
+		Where("user_id = ? AND app_id = ?", userId, appId).
+		Order("id DESC").Limit(limit).Find(&jobs).Error
+	return jobs, err
+}
+
+// ListQueued 按提交顺序查询排队中的任务
+func (m *CodeGenJobMapper) ListQueued() ([]entity.CodeGenJob, error) {
+	var jobs []entity.CodeGenJob
+	err := m.DB.Omit("content").
+		Where("status = ?", consts.CodeJobStatusQueued).
+		Order("id ASC").Find(&jobs).Error
+	return jobs, err
+}
+
+// CountQueued 统计排队中的任务数
+func (m *CodeGenJobMapper) CountQueued() (int64, error) {
+	var count int64
+	err := m.DB.Model(&entity.CodeGenJob{}).
+		Where("status = ?", consts.CodeJobStatusQueued).Count(&count).Error
+	return count, err
+}
+
+// CountQueuedBefore 统计先于 id 提交且仍在排队的任务数
+func (m *CodeGenJobMapper) CountQueuedBefore(id int64) (int64, error) {
+	var count int64
+	err := m.DB.Model(&entity.CodeGenJob{}).
+		Where("status = ? AND id < ?", consts.CodeJobStatusQueued, id).Count(&count).Error
+	return count, err
+}
+
+// MarkRunning 将排队中的任务标记为由 instanceId 实例执行中，任务已不在排队状态时返回 false
+func (m *CodeGenJobMapper) MarkRunning(id int64, instanceId string) (bool, error) {
+	now := time.Now()
+	result := m.DB.Model(&entity.CodeGenJob{}).
+		Where("id = ? AND status = ?", id, consts.CodeJobStatusQueued).
+		Updates(map[string]any{
+			"status":         consts.CodeJobStatusRunning,
+			"instance_id":    instanceId,
+			"start_time":     now,
+			"heartbeat_time": now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Finish 记录执行中任务的结果
+func (m *CodeGenJobMapper) Finish(id int64, status consts.CodeJobStatus, resultModel, content, errorMsg string) error {
+	return m.DB.Model(&entity.CodeGenJob{}).
+		Where("id = ? AND status = ?", id, consts.CodeJobStatusRunning).
+		Updates(map[string]any{
+			"status":       status,
+			"result_model": resultModel,
+			"content":      content,
+			"error_msg":    errorMsg,
+			"finish_time":  time.Now(),
+		}).Error
+}
+
+// CancelQueued 取消排队中的任务，任务已不在排队状态时返回 false
+func (m *CodeGenJobMapper) CancelQueued(id int64) (bool, error) {
+	result := m.DB.Model(&entity.CodeGenJob{}).
+		Where("id = ? AND status = ?", id, consts.CodeJobStatusQueued).
+		Updates(map[string]any{"status": consts.CodeJobStatusCancelled, "finish_time": time.Now()})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RequestCancel 为执行中的任务记录取消请求，由执行实例轮询后取消，任务已不在执行中时返回 false
+func (m *CodeGenJobMapper) RequestCancel(id int64) (bool, error) {
+	result := m.DB.Model(&entity.CodeGenJob{}).
+		Where("id = ? AND status = ?", id, consts.CodeJobStatusRunning).
+		Update("cancel_requested", true)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListCancelRequested 查询 instanceId 实例执行中且已请求取消的任务id
+func (m *CodeGenJobMapper) ListCancelRequested(instanceId string) ([]int64, error) {
+	var ids []int64
+	err := m.DB.Model(&entity.CodeGenJob{}).
+		Where("status = ? AND instance_id = ? AND cancel_requested = ?", consts.CodeJobStatusRunning, instanceId, true).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Heartbeat 刷新 instanceId 实例执行中任务的心跳时间
+func (m *CodeGenJobMapper) Heartbeat(instanceId string) error {
+	return m.DB.Model(&entity.CodeGenJob{}).
+		Where("status = ? AND instance_id = ?", consts.CodeJobStatusRunning, instanceId).
+		Update("heartbeat_time", time.Now()).Error
+}
+
+// FailStale 将心跳早于 before 的执行中任务标记为失败，用于清理执行实例已退出的任务
+func (m *CodeGenJobMapper) FailStale(before time.Time, errorMsg string) (int64, error) {
+	result := m.DB.Model(&entity.CodeGenJob{}).
+		Where("status = ? AND (heartbeat_time IS NULL OR heartbeat_time < ?)", consts.CodeJobStatusRunning, before).
+		Updates(map[string]any{
+			"status":      consts.CodeJobStatusFailed,
+			"error_msg":   errorMsg,
+			"finish_time": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,32 @@
+package mapper
+
+import (
+	"gorm.io/gorm"
+
+	"aicode/internal/model/entity"
+)
+
+// LoginAttemptMapper 登录记录数据访问层
+type LoginAttemptMapper struct {
+	DB *gorm.DB
+}
+
+// NewLoginAttemptMapper 创建登录记录 Mapper
+func NewLoginAttemptMapper(db *gorm.DB) *LoginAttemptMapper {
+	return &LoginAttemptMapper{DB: db}
+}
+
+// Save 保存登录记录
+func (m *LoginAttemptMapper) Save(attempt *entity.LoginAttempt) error {
+	return m.DB.Create(attempt).Error
+}
+
+// ListByAccount 按时间倒序查询账号最近的登录记录
+func (m *LoginAttemptMapper) ListByAccount(userAccount string, limit int) ([]entity.LoginAttempt, error) {
+	var attempts []entity.LoginAttempt
+	err := m.DB.Where("user_account = ?", userAccount).
+		Order("id DESC").
+		Limit(limit).
+		Find(&attempts).Error
+	return attempts, err
+}
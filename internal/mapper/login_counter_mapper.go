@@ -0,0 +1,218 @@
+package mapper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"aicode/internal/model/entity"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginCounterMapper 登录失败计数数据访问层，同一 key 的读取、判断与更新是原子的
+type LoginCounterMapper interface {
+	// Update 原子地更新 key 的计数：fn 收到当前计数（不存在或已过期时 Count 为 0），返回 nil 时保存修改后的计数，
+	// 并在 ttl 后过期；返回错误时放弃修改并原样返回该错误。并发冲突重试时 fn 可能被调用多次
+	Update(ctx context.Context, key string, ttl time.Duration, fn func(counter *entity.LoginCounter) error) error
+
+	// Delete 删除 key 的计数
+	Delete(ctx context.Context, key string) error
+}
+
+// loginCounterSweepInterval 内存与 MySQL 存储清理过期计数的间隔
+const loginCounterSweepInterval = 10 * time.Minute
+
+// MemoryLoginCounterMapper 基于进程内存的登录失败计数，重启后清零，仅适用于单实例部署
+type MemoryLoginCounterMapper struct {
+	mu        sync.Mutex
+	counters  map[string]entity.LoginCounter
+	lastSweep time.Time
+}
+
+// NewMemoryLoginCounterMapper 创建内存登录失败计数
+func NewMemoryLoginCounterMapper() *MemoryLoginCounterMapper {
+	return &MemoryLoginCounterMapper{
+		counters:  make(map[string]entity.LoginCounter),
+		lastSweep: time.Now(),
+	}
+}
+
+// Update 在锁内更新计数
+func (m *MemoryLoginCounterMapper) Update(_ context.Context, key string, ttl time.Duration,
+	fn func(counter *entity.LoginCounter) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.sweep(now)
+	counter, ok := m.counters[key]
+	if !ok || !now.Before(counter.ExpireTime) {
+		counter = entity.LoginCounter{CounterKey: key}
+	}
+	if err := fn(&counter); err != nil {
+		return err
+	}
+	counter.ExpireTime = now.Add(ttl)
+	m.counters[key] = counter
+	return nil
+}
+
+// Delete 删除计数
+func (m *MemoryLoginCounterMapper) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.counters, key)
+	return nil
+}
+
+// sweep 定期删除已过期的计数，调用方须持有锁
+func (m *MemoryLoginCounterMapper) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < loginCounterSweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, counter := range m.counters {
+		if !now.Before(counter.ExpireTime) {
+			delete(m.counters, key)
+		}
+	}
+}
+
+// MySQLLoginCounterMapper 基于 login_counter 表的登录失败计数，在事务内以 SELECT ... FOR UPDATE 锁定计数行，多实例部署时共享
+type MySQLLoginCounterMapper struct {
+	db *gorm.DB
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewMySQLLoginCounterMapper 创建 MySQL 登录失败计数
+func NewMySQLLoginCounterMapper(db *gorm.DB) *MySQLLoginCounterMapper {
+	return &MySQLLoginCounterMapper{db: db, lastSweep: time.Now()}
+}
+
+// Update 锁定计数行后更新计数
+func (m *MySQLLoginCounterMapper) Update(ctx context.Context, key string, ttl time.Duration,
+	fn func(counter *entity.LoginCounter) error) error {
+	m.sweep(ctx)
+	db := m.db.WithContext(ctx)
+	// 先确保计数行存在，使并发请求都在同一行上排队，避免对不存在的行加锁时的间隙锁死锁
+	now := time.Now()
+	placeholder := entity.LoginCounter{CounterKey: key, LastTime: now, ExpireTime: now}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&placeholder).Error; err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		var counter entity.LoginCounter
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("counter_key = ?", key).Take(&counter).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		now := time.Now()
+		if err != nil || !now.Before(counter.ExpireTime) {
+			counter = entity.LoginCounter{CounterKey: key}
+		}
+		if err := fn(&counter); err != nil {
+			return err
+		}
+		counter.CounterKey = key
+		counter.ExpireTime = now.Add(ttl)
+		return tx.Save(&counter).Error
+	})
+}
+
+// Delete 删除计数
+func (m *MySQLLoginCounterMapper) Delete(ctx context.Context, key string) error {
+	return m.db.WithContext(ctx).Where("counter_key = ?", key).Delete(&entity.LoginCounter{}).Error
+}
+
+// sweep 定期删除已过期的计数，失败时等待下一个周期重试
+func (m *MySQLLoginCounterMapper) sweep(ctx context.Context) {
+	m.mu.Lock()
+	now := time.Now()
+	if now.Sub(m.lastSweep) < loginCounterSweepInterval {
+		m.mu.Unlock()
+		return
+	}
+	m.lastSweep = now
+	m.mu.Unlock()
+	// 保留刚过期的计数行，避免删除其他请求刚插入、尚未锁定的占位行
+	m.db.WithContext(ctx).Where("expire_time <= ?", now.Add(-time.Minute)).Delete(&entity.LoginCounter{})
+}
+
+// Redis 乐观锁冲突时的最大重试次数与重试间隔上限
+const (
+	redisLoginCounterMaxRetries   = 50
+	redisLoginCounterRetryBackoff = 5 * time.Millisecond
+)
+
+// RedisLoginCounterMapper 基于 Redis 的登录失败计数，以 WATCH/MULTI 乐观锁保证原子性，多实例部署时共享；
+// 计数以 JSON 保存在 {prefix}{key}，随过期时间自动删除
+type RedisLoginCounterMapper struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisLoginCounterMapper 创建 Redis 登录失败计数，prefix 为 key 的前缀
+func NewRedisLoginCounterMapper(client redis.UniversalClient, prefix string) *RedisLoginCounterMapper {
+	return &RedisLoginCounterMapper{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Update 在 WATCH 事务内更新计数，计数被其他请求修改时重试
+func (m *RedisLoginCounterMapper) Update(ctx context.Context, key string, ttl time.Duration,
+	fn func(counter *entity.LoginCounter) error) error {
+	redisKey := m.prefix + key
+	update := func(tx *redis.Tx) error {
+		var counter entity.LoginCounter
+		data, err := tx.Get(ctx, redisKey).Bytes()
+		switch {
+		case errors.Is(err, redis.Nil):
+			counter = entity.LoginCounter{CounterKey: key}
+		case err != nil:
+			return err
+		default:
+			if err := json.Unmarshal(data, &counter); err != nil {
+				return err
+			}
+		}
+		if err := fn(&counter); err != nil {
+			return err
+		}
+		counter.CounterKey = key
+		counter.ExpireTime = time.Now().Add(ttl)
+		if data, err = json.Marshal(&counter); err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, redisKey, data, ttl)
+			return nil
+		})
+		return err
+	}
+	for i := 0; i < redisLoginCounterMaxRetries; i++ {
+		err := m.client.Watch(ctx, update, redisKey)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+		// 随机等待后重试，避免并发请求同时重试再次冲突
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(rand.Int63n(int64(redisLoginCounterRetryBackoff)))):
+		}
+	}
+	return redis.TxFailedErr
+}
+
+// Delete 删除计数
+func (m *RedisLoginCounterMapper) Delete(ctx context.Context, key string) error {
+	return m.client.Del(ctx, m.prefix+key).Err()
+}
@@ -0,0 +1,110 @@
+package mapper
+
+import (
+	"gorm.io/gorm"
+
+	"aicode/constant"
+	"aicode/internal/model/entity"
+)
+
+// ExperimentVariantSum 按模板版本汇总的实验结果
+type ExperimentVariantSum struct {
+	TemplateVersion int
+	Total           int64
+	Success         int64
+	Failed          int64
+	Retries         int64
+	RolledBack      int64
+}
+
+// PromptExperimentMapper 提示词实验数据访问层
+type PromptExperimentMapper struct {
+	DB *gorm.DB
+}
+
+// NewPromptExperimentMapper 创建提示词实验Mapper
+func NewPromptExperimentMapper(db *gorm.DB) *PromptExperimentMapper {
+	return &PromptExperimentMapper{DB: db}
+}
+
+// Save 保存实验
+func (m *PromptExperimentMapper) Save(experiment *entity.PromptExperiment) error {
+	return m.DB.Create(experiment).Error
+}
+
+// GetById 根据id查询实验
+func (m *PromptExperimentMapper) GetById(id int64) (*entity.PromptExperiment, error) {
+	var experiment entity.PromptExperiment
+	err := m.DB.Where("id = ?", id).First(&experiment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &experiment, nil
+}
+
+// GetRunningByTemplateName 查询模板进行中的实验
+func (m *PromptExperimentMapper) GetRunningByTemplateName(name string) (*entity.PromptExperiment, error) {
+	var experiment entity.PromptExperiment
+	err := m.DB.Where("template_name = ? AND status = ?", name, constant.PromptExperimentRunning).
+		Order("id DESC").First(&experiment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &experiment, nil
+}
+
+// List 按创建时间倒序查询全部实验
+func (m *PromptExperimentMapper) List() ([]entity.PromptExperiment, error) {
+	var experiments []entity.PromptExperiment
+	err := m.DB.Order("id DESC").Find(&experiments).Error
+	return experiments, err
+}
+
+// Stop 停止实验
+func (m *PromptExperimentMapper) Stop(id int64) error {
+	return m.DB.Model(&entity.PromptExperiment{}).
+		Where("id = ?", id).
+		Update("status", constant.PromptExperimentStopped).Error
+}
+
+// SaveRecord 保存实验记录
+func (m *PromptExperimentMapper) SaveRecord(record *entity.PromptExperimentRecord) error {
+	return m.DB.Create(record).Error
+}
+
+// IncrRecordRetries 实验记录的续写次数加一
+func (m *PromptExperimentMapper) IncrRecordRetries(id int64) error {
+	return m.DB.Model(&entity.PromptExperimentRecord{}).
+		Where("id = ?", id).
+		Update("retries", gorm.Expr("retries + 1")).Error
+}
+
+// FinishRecord 记录生成结果，只更新仍在进行中的记录
+func (m *PromptExperimentMapper) FinishRecord(id int64, status, appVersion int) error {
+	return m.DB.Model(&entity.PromptExperimentRecord{}).
+		Where("id = ? AND status = ?", id, constant.ExperimentRecordPending).
+		Updates(map[string]any{"status": status, "app_version": appVersion}).Error
+}
+
+// MarkRolledBack 将应用中版本号大于 version 的成功记录标记为被回滚
+func (m *PromptExperimentMapper) MarkRolledBack(appId int64, version int) error {
+	return m.DB.Model(&entity.PromptExperimentRecord{}).
+		Where("app_id = ? AND app_version > ? AND status = ? AND rolled_back = 0",
+			appId, version, constant.ExperimentRecordSuccess).
+		Update("rolled_back", 1).Error
+}
+
+// SumByVersion 按模板版本汇总实验结果
+func (m *PromptExperimentMapper) SumByVersion(experimentId int64) ([]ExperimentVariantSum, error) {
+	var sums []ExperimentVariantSum
+	err := m.DB.Model(&entity.PromptExperimentRecord{}).
+		Select("template_version, COUNT(*) AS total, "+
+			"SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS success, "+
+			"SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS failed, "+
+			"SUM(retries) AS retries, SUM(rolled_back) AS rolled_back",
+			constant.ExperimentRecordSuccess, constant.ExperimentRecordFailed).
+		Where("experiment_id = ?", experimentId).
+		Group("template_version").
+		Scan(&sums).Error
+	return sums, err
+}
@@ -0,0 +1,95 @@
+package mapper
+
+import (
+	"gorm.io/gorm"
+
+	"aicode/internal/model/entity"
+)
+
+// PromptTemplateMapper 提示词模板数据访问层
+type PromptTemplateMapper struct {
+	DB *gorm.DB
+}
+
+// NewPromptTemplateMapper 创建提示词模板Mapper
+func NewPromptTemplateMapper(db *gorm.DB) *PromptTemplateMapper {
+	return &PromptTemplateMapper{DB: db}
+}
+
+// SaveActive 保存新版本并设为生效版本，同名模板的其他版本失效
+func (m *PromptTemplateMapper) SaveActive(template *entity.PromptTemplate) error {
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.PromptTemplate{}).
+			Where("name = ? AND is_active = 1", template.Name).
+			Update("is_active", 0).Error; err != nil {
+			return err
+		}
+		template.IsActive = 1
+		return tx.Create(template).Error
+	})
+}
+
+// GetActiveByName 查询模板的生效版本
+func (m *PromptTemplateMapper) GetActiveByName(name string) (*entity.PromptTemplate, error) {
+	var template entity.PromptTemplate
+	err := m.DB.Where("name = ? AND is_active = 1 AND is_delete = 0", name).First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetByNameAndVersion 查询模板的指定版本
+func (m *PromptTemplateMapper) GetByNameAndVersion(name string, version int) (*entity.PromptTemplate, error) {
+	var template entity.PromptTemplate
+	err := m.DB.Where("name = ? AND version = ? AND is_delete = 0", name, version).First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetMaxVersion 查询模板的最大版本号（含已删除的版本），从未创建过时返回 0
+func (m *PromptTemplateMapper) GetMaxVersion(name string) (int, error) {
+	var maxVersion int
+	err := m.DB.Model(&entity.PromptTemplate{}).
+		Select("COALESCE(MAX(version), 0)").
+		Where("name = ?", name).
+		Scan(&maxVersion).Error
+	return maxVersion, err
+}
+
+// ListActive 查询全部模板的生效版本
+func (m *PromptTemplateMapper) ListActive() ([]entity.PromptTemplate, error) {
+	var templates []entity.PromptTemplate
+	err := m.DB.Where("is_active = 1 AND is_delete = 0").Order("name ASC").Find(&templates).Error
+	return templates, err
+}
+
+// ListVersionsByName 按版本号倒序查询模板的全部版本
+func (m *PromptTemplateMapper) ListVersionsByName(name string) ([]entity.PromptTemplate, error) {
+	var templates []entity.PromptTemplate
+	err := m.DB.Where("name = ? AND is_delete = 0", name).Order("version DESC").Find(&templates).Error
+	return templates, err
+}
+
+// Activate 将指定版本设为生效版本
+func (m *PromptTemplateMapper) Activate(name string, version int) error {
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.PromptTemplate{}).
+			Where("name = ? AND is_active = 1", name).
+			Update("is_active", 0).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.PromptTemplate{}).
+			Where("name = ? AND version = ? AND is_delete = 0", name, version).
+			Update("is_active", 1).Error
+	})
+}
+
+// DeleteByName 删除模板的全部版本（逻辑删除）
+func (m *PromptTemplateMapper) DeleteByName(name string) error {
+	return m.DB.Model(&entity.PromptTemplate{}).
+		Where("name = ?", name).
+		Updates(map[string]any{"is_delete": 1, "is_active": 0}).Error
+}
@@ -0,0 +1,24 @@
+package mapper
+
+import (
+	"gorm.io/gorm"
+
+	"aicode/internal/model/entity"
+)
+
+// RolePermissionMapper 角色权限数据访问层
+type RolePermissionMapper struct {
+	DB *gorm.DB
+}
+
+// NewRolePermissionMapper 创建角色权限 Mapper
+func NewRolePermissionMapper(db *gorm.DB) *RolePermissionMapper {
+	return &RolePermissionMapper{DB: db}
+}
+
+// ListAll 查询全部角色权限关联
+func (m *RolePermissionMapper) ListAll() ([]entity.RolePermission, error) {
+	var rolePermissions []entity.RolePermission
+	err := m.DB.Find(&rolePermissions).Error
+	return rolePermissions, err
+}
@@ -0,0 +1,432 @@
+package mapper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"aicode/internal/model/entity"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// SessionRecord 服务端保存的 session
+type SessionRecord struct {
+	ID             string    `json:"id"`
+	UserID         int64     `json:"userId"` // 登录用户 id，未登录的 session 为 0
+	Data           []byte    `json:"data"`   // gob 编码的 session 值
+	ClientIP       string    `json:"clientIp"`
+	UserAgent      string    `json:"userAgent"`
+	CreateTime     time.Time `json:"createTime"`
+	LastAccessTime time.Time `json:"lastAccessTime"`
+	ExpireTime     time.Time `json:"expireTime"`
+}
+
+// expired 判断 session 是否已过期
+func (r *SessionRecord) expired(now time.Time) bool {
+	return !now.Before(r.ExpireTime)
+}
+
+// SessionMapper 服务端 session 数据访问层，已过期的 session 视为不存在
+type SessionMapper interface {
+	// Get 查询 session，不存在或已过期时返回 nil, nil
+	Get(ctx context.Context, id string) (*SessionRecord, error)
+
+	// Save 保存 session，已存在时整体覆盖
+	Save(ctx context.Context, record *SessionRecord) error
+
+	// Touch 更新最近访问时间与过期时间
+	Touch(ctx context.Context, id string, lastAccessTime, expireTime time.Time) error
+
+	// Delete 删除 session
+	Delete(ctx context.Context, id string) error
+
+	// ListByUser 按最近访问时间倒序查询用户未过期的 session，不返回 Data
+	ListByUser(ctx context.Context, userId int64) ([]SessionRecord, error)
+
+	// DeleteByUser 删除用户的全部 session，返回删除的数量
+	DeleteByUser(ctx context.Context, userId int64) (int64, error)
+}
+
+// sessionSweepInterval 内存与 MySQL 存储清理过期 session 的间隔
+const sessionSweepInterval = 10 * time.Minute
+
+// sortSessionRecords 按最近访问时间倒序排列
+func sortSessionRecords(records []SessionRecord) {
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].LastAccessTime.After(records[j].LastAccessTime)
+	})
+}
+
+// MemorySessionMapper 基于进程内存的 session 存储，重启后全部失效，仅适用于单实例部署
+type MemorySessionMapper struct {
+	mu        sync.Mutex
+	records   map[string]SessionRecord
+	lastSweep time.Time
+}
+
+// NewMemorySessionMapper 创建内存 session 存储
+func NewMemorySessionMapper() *MemorySessionMapper {
+	return &MemorySessionMapper{
+		records:   make(map[string]SessionRecord),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get 查询 session
+func (m *MemorySessionMapper) Get(_ context.Context, id string) (*SessionRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.records[id]
+	if !ok || record.expired(time.Now()) {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+// Save 保存 session
+func (m *MemorySessionMapper) Save(_ context.Context, record *SessionRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.sweep(now)
+	m.records[record.ID] = *record
+	return nil
+}
+
+// Touch 更新最近访问时间与过期时间
+func (m *MemorySessionMapper) Touch(_ context.Context, id string, lastAccessTime, expireTime time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if record, ok := m.records[id]; ok {
+		record.LastAccessTime = lastAccessTime
+		record.ExpireTime = expireTime
+		m.records[id] = record
+	}
+	return nil
+}
+
+// Delete 删除 session
+func (m *MemorySessionMapper) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, id)
+	return nil
+}
+
+// ListByUser 查询用户未过期的 session
+func (m *MemorySessionMapper) ListByUser(_ context.Context, userId int64) ([]SessionRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	records := make([]SessionRecord, 0)
+	for _, record := range m.records {
+		if record.UserID == userId && !record.expired(now) {
+			record.Data = nil
+			records = append(records, record)
+		}
+	}
+	sortSessionRecords(records)
+	return records, nil
+}
+
+// DeleteByUser 删除用户的全部 session
+func (m *MemorySessionMapper) DeleteByUser(_ context.Context, userId int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var count int64
+	for id, record := range m.records {
+		if record.UserID == userId {
+			if !record.expired(now) {
+				count++
+			}
+			delete(m.records, id)
+		}
+	}
+	return count, nil
+}
+
+// sweep 定期删除已过期的 session，调用方须持有锁
+func (m *MemorySessionMapper) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sessionSweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for id, record := range m.records {
+		if record.expired(now) {
+			delete(m.records, id)
+		}
+	}
+}
+
+// MySQLSessionMapper 基于 user_session 表的 session 存储，多实例部署时共享登录态
+type MySQLSessionMapper struct {
+	db *gorm.DB
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewMySQLSessionMapper 创建 MySQL session 存储
+func NewMySQLSessionMapper(db *gorm.DB) *MySQLSessionMapper {
+	return &MySQLSessionMapper{db: db, lastSweep: time.Now()}
+}
+
+// Get 查询 session
+func (m *MySQLSessionMapper) Get(ctx context.Context, id string) (*SessionRecord, error) {
+	var session entity.UserSession
+	err := m.db.WithContext(ctx).Where("id = ? AND expire_time > ?", id, time.Now()).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	record := sessionRecordFromEntity(&session)
+	return &record, nil
+}
+
+// Save 保存 session
+func (m *MySQLSessionMapper) Save(ctx context.Context, record *SessionRecord) error {
+	m.sweep(ctx)
+	session := entity.UserSession{
+		ID:             record.ID,
+		UserID:         record.UserID,
+		Data:           record.Data,
+		ClientIP:       record.ClientIP,
+		UserAgent:      record.UserAgent,
+		CreateTime:     record.CreateTime,
+		LastAccessTime: record.LastAccessTime,
+		ExpireTime:     record.ExpireTime,
+	}
+	return m.db.WithContext(ctx).Save(&session).Error
+}
+
+// Touch 更新最近访问时间与过期时间
+func (m *MySQLSessionMapper) Touch(ctx context.Context, id string, lastAccessTime, expireTime time.Time) error {
+	return m.db.WithContext(ctx).Model(&entity.UserSession{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"last_access_time": lastAccessTime, "expire_time": expireTime}).Error
+}
+
+// Delete 删除 session
+func (m *MySQLSessionMapper) Delete(ctx context.Context, id string) error {
+	return m.db.WithContext(ctx).Where("id = ?", id).Delete(&entity.UserSession{}).Error
+}
+
+// ListByUser 查询用户未过期的 session
+func (m *MySQLSessionMapper) ListByUser(ctx context.Context, userId int64) ([]SessionRecord, error) {
+	var sessions []entity.UserSession
+	err := m.db.WithContext(ctx).Omit("data").
+		Where("user_id = ? AND expire_time > ?", userId, time.Now()).
+		Order("last_access_time DESC").Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	records := make([]SessionRecord, 0, len(sessions))
+	for i := range sessions {
+		records = append(records, sessionRecordFromEntity(&sessions[i]))
+	}
+	return records, nil
+}
+
+// DeleteByUser 删除用户的全部 session
+func (m *MySQLSessionMapper) DeleteByUser(ctx context.Context, userId int64) (int64, error) {
+	result := m.db.WithContext(ctx).Where("user_id = ?", userId).Delete(&entity.UserSession{})
+	return result.RowsAffected, result.Error
+}
+
+// sweep 定期删除已过期的 session，失败时等待下一个周期重试
+func (m *MySQLSessionMapper) sweep(ctx context.Context) {
+	m.mu.Lock()
+	now := time.Now()
+	if now.Sub(m.lastSweep) < sessionSweepInterval {
+		m.mu.Unlock()
+		return
+	}
+	m.lastSweep = now
+	m.mu.Unlock()
+	m.db.WithContext(ctx).Where("expire_time <= ?", now).Delete(&entity.UserSession{})
+}
+
+// sessionRecordFromEntity 实体转换为 session 记录
+func sessionRecordFromEntity(session *entity.UserSession) SessionRecord {
+	return SessionRecord{
+		ID:             session.ID,
+		UserID:         session.UserID,
+		Data:           session.Data,
+		ClientIP:       session.ClientIP,
+		UserAgent:      session.UserAgent,
+		CreateTime:     session.CreateTime,
+		LastAccessTime: session.LastAccessTime,
+		ExpireTime:     session.ExpireTime,
+	}
+}
+
+// RedisSessionMapper 基于 Redis 的 session 存储，多实例部署时共享登录态
+// session 以 JSON 保存在 {prefix}s:{id}，随过期时间自动删除；
+// 用户的 session id 集合保存在 {prefix}u:{userId}，查询时顺带清理已过期的成员
+type RedisSessionMapper struct {
+	client redis.Cmdable
+	prefix string
+}
+
+// NewRedisSessionMapper 创建 Redis session 存储，prefix 为 key 的前缀
+func NewRedisSessionMapper(client redis.Cmdable, prefix string) *RedisSessionMapper {
+	return &RedisSessionMapper{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Get 查询 session
+func (m *RedisSessionMapper) Get(ctx context.Context, id string) (*SessionRecord, error) {
+	data, err := m.client.Get(ctx, m.sessionKey(id)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var record SessionRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	if record.expired(time.Now()) {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+// Save 保存 session，并将 session id 加入用户集合
+func (m *RedisSessionMapper) Save(ctx context.Context, record *SessionRecord) error {
+	ttl := time.Until(record.ExpireTime)
+	if ttl <= 0 {
+		return m.Delete(ctx, record.ID)
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := m.client.Set(ctx, m.sessionKey(record.ID), data, ttl).Err(); err != nil {
+		return err
+	}
+	if record.UserID == 0 {
+		return nil
+	}
+	userKey := m.userKey(record.UserID)
+	if err := m.client.SAdd(ctx, userKey, record.ID).Err(); err != nil {
+		return err
+	}
+	// 用户集合的过期时间不早于其中最晚过期的 session
+	current, err := m.client.PTTL(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+	if current < ttl {
+		return m.client.PExpire(ctx, userKey, ttl).Err()
+	}
+	return nil
+}
+
+// Touch 更新最近访问时间与过期时间
+func (m *RedisSessionMapper) Touch(ctx context.Context, id string, lastAccessTime, expireTime time.Time) error {
+	record, err := m.Get(ctx, id)
+	if err != nil || record == nil {
+		return err
+	}
+	record.LastAccessTime = lastAccessTime
+	record.ExpireTime = expireTime
+	return m.Save(ctx, record)
+}
+
+// Delete 删除 session
+func (m *RedisSessionMapper) Delete(ctx context.Context, id string) error {
+	record, err := m.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := m.client.Del(ctx, m.sessionKey(id)).Err(); err != nil {
+		return err
+	}
+	if record != nil && record.UserID != 0 {
+		return m.client.SRem(ctx, m.userKey(record.UserID), id).Err()
+	}
+	return nil
+}
+
+// ListByUser 查询用户未过期的 session
+func (m *RedisSessionMapper) ListByUser(ctx context.Context, userId int64) ([]SessionRecord, error) {
+	userKey := m.userKey(userId)
+	ids, err := m.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]SessionRecord, 0, len(ids))
+	if len(ids) == 0 {
+		return records, nil
+	}
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, m.sessionKey(id))
+	}
+	values, err := m.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	stale := make([]any, 0)
+	for i, value := range values {
+		data, ok := value.(string)
+		var record SessionRecord
+		if !ok || json.Unmarshal([]byte(data), &record) != nil || record.expired(now) {
+			stale = append(stale, ids[i])
+			continue
+		}
+		record.Data = nil
+		records = append(records, record)
+	}
+	if len(stale) > 0 {
+		if err := m.client.SRem(ctx, userKey, stale...).Err(); err != nil {
+			return nil, err
+		}
+	}
+	sortSessionRecords(records)
+	return records, nil
+}
+
+// DeleteByUser 删除用户的全部 session
+func (m *RedisSessionMapper) DeleteByUser(ctx context.Context, userId int64) (int64, error) {
+	userKey := m.userKey(userId)
+	ids, err := m.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	if len(ids) > 0 {
+		keys := make([]string, 0, len(ids))
+		for _, id := range ids {
+			keys = append(keys, m.sessionKey(id))
+		}
+		if count, err = m.client.Del(ctx, keys...).Result(); err != nil {
+			return 0, err
+		}
+	}
+	return count, m.client.Del(ctx, userKey).Err()
+}
+
+// sessionKey session 的 key
+func (m *RedisSessionMapper) sessionKey(id string) string {
+	return m.prefix + "s:" + id
+}
+
+// userKey 用户 session id 集合的 key
+func (m *RedisSessionMapper) userKey(userId int64) string {
+	return m.prefix + "u:" + strconv.FormatInt(userId, 10)
+}
@@ -0,0 +1,54 @@
+package mapper
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"aicode/internal/model/entity"
+)
+
+// ModelUsageSum 按模型汇总的 token 用量
+type ModelUsageSum struct {
+	Model            string
+	PromptTokens     int64
+	CompletionTokens int64
+	TotalTokens      int64
+}
+
+// TokenUsageMapper Token 用量数据访问层
+type TokenUsageMapper struct {
+	DB *gorm.DB
+}
+
+// NewTokenUsageMapper 创建Token 用量Mapper
+func NewTokenUsageMapper(db *gorm.DB) *TokenUsageMapper {
+	return &TokenUsageMapper{DB: db}
+}
+
+// Save 保存用量记录
+func (m *TokenUsageMapper) Save(usage *entity.TokenUsage) error {
+	return m.DB.Create(usage).Error
+}
+
+// SumTotalByUserSince 统计用户自 since 起的总 token 数
+func (m *TokenUsageMapper) SumTotalByUserSince(userId int64, since time.Time) (int64, error) {
+	var total int64
+	err := m.DB.Model(&entity.TokenUsage{}).
+		Select("COALESCE(SUM(total_tokens), 0)").
+		Where("user_id = ? AND create_time >= ?", userId, since).
+		Scan(&total).Error
+	return total, err
+}
+
+// SumByModelSince 按模型汇总用户自 since 起的 token 用量
+func (m *TokenUsageMapper) SumByModelSince(userId int64, since time.Time) ([]ModelUsageSum, error) {
+	var sums []ModelUsageSum
+	err := m.DB.Model(&entity.TokenUsage{}).
+		Select("model, SUM(prompt_tokens) AS prompt_tokens, SUM(completion_tokens) AS completion_tokens, SUM(total_tokens) AS total_tokens").
+		Where("user_id = ? AND create_time >= ?", userId, since).
+		Group("model").
+		Order("total_tokens DESC").
+		Scan(&sums).Error
+	return sums, err
+}
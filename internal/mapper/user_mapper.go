@@ -41,14 +41,9 @@ func (m *UserMapper) GetByAccount(account string) (*entity.User, error) {
 	return &user, nil
 }
 
-// GetByAccountAndPassword 根据账号和密码查询用户
-func (m *UserMapper) GetByAccountAndPassword(account, password string) (*entity.User, error) {
-	var user entity.User
-	err := m.DB.Where("user_account = ? AND user_password = ? AND is_delete = 0", account, password).First(&user).Error
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
+// UpdatePassword 更新用户密码哈希
+func (m *UserMapper) UpdatePassword(id int64, password string) error {
+	return m.DB.Model(&entity.User{}).Where("id = ?", id).Update("user_password", password).Error
 }
 
 // CountByAccount 根据账号统计数量
@@ -0,0 +1,13 @@
+package apikey
+
+// ApiKeyCreateRequest API Key 创建请求
+type ApiKeyCreateRequest struct {
+	Name       string   `json:"name" binding:"required,max=64"`       // 名称
+	Scopes     []string `json:"scopes" binding:"required,min=1,dive"` // 授权范围：* 或路由组名，路由组名加 :read 后缀表示只读
+	ExpireDays int      `json:"expireDays" binding:"omitempty,min=0"` // 有效天数，0 表示永不过期
+}
+
+// ApiKeyRevokeRequest API Key 吊销请求
+type ApiKeyRevokeRequest struct {
+	ID int64 `json:"id" binding:"required"` // API Key id
+}
@@ -0,0 +1,9 @@
+package app
+
+// AppAddRequest 应用创建请求
+type AppAddRequest struct {
+	AppName     string `json:"appName" binding:"omitempty"`     // 应用名称（为空时取初始化 prompt 前缀）
+	Cover       string `json:"cover" binding:"omitempty"`       // 应用封面
+	InitPrompt  string `json:"initPrompt" binding:"required"`   // 应用初始化的 prompt
+	CodeGenType string `json:"codeGenType" binding:"omitempty"` // 代码生成类型：single/multi/vue_project/agent
+}
@@ -0,0 +1,6 @@
+package app
+
+// AppDeployRequest 应用部署请求
+type AppDeployRequest struct {
+	AppID int64 `json:"appId" binding:"required"` // 应用id
+}
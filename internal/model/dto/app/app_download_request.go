@@ -0,0 +1,7 @@
+package app
+
+// AppDownloadRequest 应用代码下载请求
+type AppDownloadRequest struct {
+	AppID   int64 `json:"appId" form:"appId" binding:"required"` // 应用id
+	Version int   `json:"version" form:"version"`                // 版本号，为空时下载当前代码
+}
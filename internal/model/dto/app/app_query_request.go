@@ -0,0 +1,12 @@
+package app
+
+import "aicode/internal/common"
+
+// AppQueryRequest 应用查询请求
+type AppQueryRequest struct {
+	common.PageRequest
+	ID          *int64 `json:"id" form:"id"`                   // id
+	AppName     string `json:"appName" form:"appName"`         // 应用名称
+	CodeGenType string `json:"codeGenType" form:"codeGenType"` // 代码生成类型
+	UserID      *int64 `json:"userId" form:"userId"`           // 创建用户id（仅管理员可按用户筛选）
+}
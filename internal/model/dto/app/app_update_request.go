@@ -0,0 +1,9 @@
+package app
+
+// AppUpdateRequest 应用更新请求
+type AppUpdateRequest struct {
+	ID       int64  `json:"id" binding:"required"`        // id
+	AppName  string `json:"appName" binding:"omitempty"`  // 应用名称
+	Cover    string `json:"cover" binding:"omitempty"`    // 应用封面
+	Priority *int   `json:"priority" binding:"omitempty"` // 优先级（仅管理员可修改）
+}
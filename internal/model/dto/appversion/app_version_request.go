@@ -0,0 +1,19 @@
+package appversion
+
+// AppVersionListRequest 应用版本列表查询请求
+type AppVersionListRequest struct {
+	AppID int64 `json:"appId" form:"appId" binding:"required"` // 应用id
+}
+
+// AppVersionDiffRequest 应用版本差异查询请求
+type AppVersionDiffRequest struct {
+	AppID int64 `json:"appId" form:"appId" binding:"required"` // 应用id
+	From  int   `json:"from" form:"from" binding:"required"`   // 起始版本号
+	To    int   `json:"to" form:"to" binding:"required"`       // 目标版本号
+}
+
+// AppVersionRollbackRequest 应用版本回滚请求
+type AppVersionRollbackRequest struct {
+	AppID   int64 `json:"appId" binding:"required"`   // 应用id
+	Version int   `json:"version" binding:"required"` // 回滚到的版本号
+}
@@ -0,0 +1,10 @@
+package chathistory
+
+// ChatHistoryQueryRequest 对话历史游标查询请求
+// AppID 与 ConversationID 二选一：前者查询代码生成对话，后者查询普通聊天对话
+type ChatHistoryQueryRequest struct {
+	AppID          int64  `json:"appId" form:"appId"`                   // 应用id
+	ConversationID string `json:"conversationId" form:"conversationId"` // 会话id
+	Cursor         int64  `json:"cursor" form:"cursor"`                 // 游标：上一页最后一条消息的 id，为空表示从最新消息开始
+	PageSize       int    `json:"pageSize" form:"pageSize"`             // 页面大小
+}
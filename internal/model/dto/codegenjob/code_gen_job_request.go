@@ -0,0 +1,16 @@
+package codegenjob
+
+// CodeGenJobGetRequest 异步代码生成任务查询请求
+type CodeGenJobGetRequest struct {
+	ID int64 `json:"id" form:"id" binding:"required"` // 任务id
+}
+
+// CodeGenJobListRequest 异步代码生成任务列表查询请求
+type CodeGenJobListRequest struct {
+	AppID int64 `json:"appId" form:"appId" binding:"required"` // 应用id
+}
+
+// CodeGenJobCancelRequest 异步代码生成任务取消请求
+type CodeGenJobCancelRequest struct {
+	ID int64 `json:"id" binding:"required"` // 任务id
+}
@@ -0,0 +1,24 @@
+package promptexperiment
+
+// PromptExperimentVariantRequest 实验分组
+type PromptExperimentVariantRequest struct {
+	Version int `json:"version" binding:"required,min=1"` // 模板版本号
+	Weight  int `json:"weight" binding:"required,min=1"`  // 分流权重
+}
+
+// PromptExperimentCreateRequest 提示词实验创建请求
+type PromptExperimentCreateRequest struct {
+	TemplateName string                           `json:"templateName" binding:"required"`               // 参与实验的模板名称
+	Variants     []PromptExperimentVariantRequest `json:"variants" binding:"required,min=2,max=10,dive"` // 实验分组，至少两组
+	Description  string                           `json:"description" binding:"omitempty"`               // 实验说明
+}
+
+// PromptExperimentStopRequest 提示词实验停止请求
+type PromptExperimentStopRequest struct {
+	ID int64 `json:"id" binding:"required"` // 实验id
+}
+
+// PromptExperimentStatsRequest 提示词实验统计查询请求
+type PromptExperimentStatsRequest struct {
+	ID int64 `json:"id" form:"id" binding:"required"` // 实验id
+}
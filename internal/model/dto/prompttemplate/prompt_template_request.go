@@ -0,0 +1,32 @@
+package prompttemplate
+
+// PromptTemplateAddRequest 提示词模板创建请求
+type PromptTemplateAddRequest struct {
+	Name        string `json:"name" binding:"required"`         // 模板名称，代码生成模板与生成类型同名
+	Content     string `json:"content" binding:"required"`      // 模板内容（Go text/template 语法）
+	Description string `json:"description" binding:"omitempty"` // 版本说明
+}
+
+// PromptTemplateUpdateRequest 提示词模板修改请求，保存为新版本并立即生效
+type PromptTemplateUpdateRequest struct {
+	Name        string `json:"name" binding:"required"`         // 模板名称
+	Content     string `json:"content" binding:"required"`      // 模板内容
+	Description string `json:"description" binding:"omitempty"` // 版本说明
+}
+
+// PromptTemplateActivateRequest 提示词模板版本生效请求
+type PromptTemplateActivateRequest struct {
+	Name    string `json:"name" binding:"required"`    // 模板名称
+	Version int    `json:"version" binding:"required"` // 版本号
+}
+
+// PromptTemplateDeleteRequest 提示词模板删除请求
+type PromptTemplateDeleteRequest struct {
+	Name string `json:"name" binding:"required"` // 模板名称
+}
+
+// PromptTemplateGetRequest 提示词模板查询请求
+type PromptTemplateGetRequest struct {
+	Name    string `json:"name" form:"name" binding:"required"` // 模板名称
+	Version int    `json:"version" form:"version"`              // 版本号，为空时查询生效版本
+}
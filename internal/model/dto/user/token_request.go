@@ -0,0 +1,11 @@
+package user
+
+// TokenRefreshRequest 令牌刷新请求
+type TokenRefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"` // refresh 令牌
+}
+
+// TokenRevokeRequest 令牌吊销请求
+type TokenRevokeRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"` // 要吊销的令牌会话的 refresh 令牌
+}
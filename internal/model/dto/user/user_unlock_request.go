@@ -0,0 +1,6 @@
+package user
+
+// UserUnlockRequest 解锁账号请求
+type UserUnlockRequest struct {
+	ID int64 `json:"id" binding:"required"` // 用户id
+}
@@ -0,0 +1,12 @@
+package usersession
+
+// UserSessionListRequest 用户登录 session 查询请求
+type UserSessionListRequest struct {
+	UserID int64 `json:"userId" form:"userId" binding:"required"` // 用户id
+}
+
+// UserSessionExpireRequest 用户登录 session 强制失效请求
+type UserSessionExpireRequest struct {
+	UserID    int64  `json:"userId" binding:"required"`     // 用户id
+	SessionID string `json:"sessionId" binding:"omitempty"` // 要失效的 session id，为空时失效该用户的全部 session 与令牌会话
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+)
+
+// ApiKey 个人 API Key 实体类，只保存密钥的哈希
+type ApiKey struct {
+	ID           int64      `json:"id" gorm:"primaryKey;autoIncrement;comment:id"`
+	UserID       int64      `json:"userId" gorm:"column:user_id;not null;index:idx_user_id;comment:所属用户id"`
+	Name         string     `json:"name" gorm:"column:name;type:varchar(64);not null;comment:名称"`
+	KeyPrefix    string     `json:"keyPrefix" gorm:"column:key_prefix;type:varchar(16);not null;comment:密钥前缀"`
+	KeyHash      string     `json:"-" gorm:"column:key_hash;type:char(64);not null;uniqueIndex:uk_key_hash;comment:密钥的 SHA-256 哈希"`
+	Scopes       []string   `json:"scopes" gorm:"column:scopes;type:varchar(1024);serializer:json;not null;comment:授权范围"`
+	ExpireTime   *time.Time `json:"expireTime" gorm:"column:expire_time;comment:过期时间"`
+	LastUsedTime *time.Time `json:"lastUsedTime" gorm:"column:last_used_time;comment:最近一次使用时间"`
+	Revoked      int        `json:"revoked" gorm:"column:revoked;type:tinyint;default:0;not null;comment:是否已吊销"`
+	CreateTime   time.Time  `json:"createTime" gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateTime   time.Time  `json:"updateTime" gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+}
+
+// TableName 指定表名
+func (ApiKey) TableName() string {
+	return "api_key"
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+)
+
+// App 应用实体类
+type App struct {
+	ID           int64      `json:"id" gorm:"primaryKey;autoIncrement;comment:id"`
+	AppName      string     `json:"appName" gorm:"column:app_name;type:varchar(256);comment:应用名称"`
+	Cover        string     `json:"cover" gorm:"column:cover;type:varchar(512);comment:应用封面"`
+	InitPrompt   string     `json:"initPrompt" gorm:"column:init_prompt;type:text;comment:应用初始化的 prompt"`
+	CodeGenType  string     `json:"codeGenType" gorm:"column:code_gen_type;type:varchar(64);comment:代码生成类型"`
+	Priority     int        `json:"priority" gorm:"column:priority;default:0;not null;comment:优先级"`
+	DeployKey    string     `json:"deployKey" gorm:"column:deploy_key;type:varchar(64);default:null;uniqueIndex;comment:部署标识"`
+	DeployedTime *time.Time `json:"deployedTime" gorm:"column:deployed_time;comment:部署时间"`
+	UserID       int64      `json:"userId" gorm:"column:user_id;not null;index;comment:创建用户id"`
+	EditTime     time.Time  `json:"editTime" gorm:"column:edit_time;comment:编辑时间"`
+	CreateTime   time.Time  `json:"createTime" gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateTime   time.Time  `json:"updateTime" gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+	IsDelete     int        `json:"isDelete" gorm:"column:is_delete;type:tinyint;default:0;not null;comment:是否删除(0-未删除，1-已删除)"`
+}
+
+// TableName 指定表名
+func (App) TableName() string {
+	return "app"
+}
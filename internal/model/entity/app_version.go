@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+)
+
+// AppVersion 应用代码版本实体类，每次成功生成代码后记录一条，对应一份目录快照
+type AppVersion struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:id"`
+	AppID      int64     `json:"appId" gorm:"column:app_id;not null;uniqueIndex:uk_app_version;comment:应用id"`
+	Version    int       `json:"version" gorm:"column:version;not null;uniqueIndex:uk_app_version;comment:版本号"`
+	Prompt     string    `json:"prompt" gorm:"column:prompt;type:text;comment:生成该版本的用户 prompt"`
+	Model      string    `json:"model" gorm:"column:model;type:varchar(128);default:'';not null;comment:实际响应的模型名"`
+	GenType    string    `json:"genType" gorm:"column:gen_type;type:varchar(64);default:'';not null;comment:代码生成类型"`
+	UserID     int64     `json:"userId" gorm:"column:user_id;not null;comment:创建用户id"`
+	CreateTime time.Time `json:"createTime" gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+}
+
+// TableName 指定表名
+func (AppVersion) TableName() string {
+	return "app_version"
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+)
+
+// AuthToken 令牌会话实体类，每次登录签发一组 access/refresh 令牌对应一条
+type AuthToken struct {
+	ID           int64      `json:"id" gorm:"primaryKey;autoIncrement;comment:id"`
+	UserID       int64      `json:"userId" gorm:"column:user_id;not null;index:idx_user_id;comment:用户id"`
+	SessionID    string     `json:"sessionId" gorm:"column:session_id;type:varchar(64);not null;uniqueIndex:uk_session_id;comment:会话id"`
+	RefreshJTI   string     `json:"-" gorm:"column:refresh_jti;type:varchar(64);not null;comment:当前有效的 refresh 令牌 id"`
+	ExpireTime   time.Time  `json:"expireTime" gorm:"column:expire_time;not null;comment:会话过期时间"`
+	Revoked      int        `json:"revoked" gorm:"column:revoked;type:tinyint;default:0;not null;comment:是否已吊销"`
+	LastUsedTime *time.Time `json:"lastUsedTime" gorm:"column:last_used_time;comment:最近一次刷新时间"`
+	CreateTime   time.Time  `json:"createTime" gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateTime   time.Time  `json:"updateTime" gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+}
+
+// TableName 指定表名
+func (AuthToken) TableName() string {
+	return "auth_token"
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+)
+
+// ChatHistory 对话历史实体类
+// 代码生成对话按 AppID 归档，普通聊天对话按 UserID + ConversationID 归档
+type ChatHistory struct {
+	ID             int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:id"`
+	Message        string    `json:"message" gorm:"column:message;type:mediumtext;not null;comment:消息内容"`
+	MessageType    string    `json:"messageType" gorm:"column:message_type;type:varchar(32);not null;comment:消息类型：user/assistant"`
+	AppID          int64     `json:"appId" gorm:"column:app_id;default:0;not null;comment:应用id"`
+	ConversationID string    `json:"conversationId" gorm:"column:conversation_id;type:varchar(64);default:'';not null;comment:会话id"`
+	UserID         int64     `json:"userId" gorm:"column:user_id;not null;comment:创建用户id"`
+	CreateTime     time.Time `json:"createTime" gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateTime     time.Time `json:"updateTime" gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+	IsDelete       int       `json:"isDelete" gorm:"column:is_delete;type:tinyint;default:0;not null;comment:是否删除(0-未删除，1-已删除)"`
+}
+
+// TableName 指定表名
+func (ChatHistory) TableName() string {
+	return "chat_history"
+}
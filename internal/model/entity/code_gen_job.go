@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+)
+
+// CodeGenJob 异步代码生成任务实体类
+type CodeGenJob struct {
+	ID              int64      `json:"id" gorm:"primaryKey;autoIncrement;comment:id"`
+	AppID           int64      `json:"appId" gorm:"column:app_id;not null;index:idx_user_app;comment:应用id"`
+	UserID          int64      `json:"userId" gorm:"column:user_id;not null;index:idx_user_app;comment:提交用户id"`
+	Model           string     `json:"model" gorm:"column:model;type:varchar(128);not null;comment:请求的模型名"`
+	GenType         string     `json:"genType" gorm:"column:gen_type;type:varchar(64);not null;comment:代码生成类型"`
+	Question        string     `json:"question" gorm:"column:question;type:text;not null;comment:用户提问"`
+	Status          string     `json:"status" gorm:"column:status;type:varchar(16);default:queued;not null;index:idx_status;comment:状态"`
+	InstanceID      string     `json:"instanceId" gorm:"column:instance_id;type:varchar(64);default:'';not null;comment:执行任务的服务实例id"`
+	CancelRequested bool       `json:"cancelRequested" gorm:"column:cancel_requested;default:0;not null;comment:是否已请求取消执行中的任务"`
+	ResultModel     string     `json:"resultModel" gorm:"column:result_model;type:varchar(128);default:'';not null;comment:实际响应的模型名"`
+	Content         string     `json:"content" gorm:"column:content;type:mediumtext;comment:模型输出的原始内容"`
+	ErrorMsg        string     `json:"errorMsg" gorm:"column:error_msg;type:varchar(1024);default:'';not null;comment:失败原因"`
+	StartTime       *time.Time `json:"startTime" gorm:"column:start_time;comment:开始执行时间"`
+	HeartbeatTime   *time.Time `json:"heartbeatTime" gorm:"column:heartbeat_time;comment:执行实例最近一次心跳时间"`
+	FinishTime      *time.Time `json:"finishTime" gorm:"column:finish_time;comment:结束时间"`
+	CreateTime      time.Time  `json:"createTime" gorm:"column:create_time;autoCreateTime;comment:提交时间"`
+	UpdateTime      time.Time  `json:"updateTime" gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+}
+
+// TableName 指定表名
+func (CodeGenJob) TableName() string {
+	return "code_gen_job"
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+)
+
+// LoginAttempt 登录记录实体类
+type LoginAttempt struct {
+	ID          int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:id"`
+	UserAccount string    `json:"userAccount" gorm:"column:user_account;type:varchar(256);not null;index:idx_account_time,priority:1;comment:登录时提交的账号"`
+	UserID      int64     `json:"userId" gorm:"column:user_id;default:0;not null;comment:用户id，账号不存在时为 0"`
+	ClientIP    string    `json:"clientIp" gorm:"column:client_ip;type:varchar(64);default:'';not null;index:idx_ip_time,priority:1;comment:客户端 IP"`
+	UserAgent   string    `json:"userAgent" gorm:"column:user_agent;type:varchar(512);default:'';not null;comment:客户端 User-Agent"`
+	Result      int       `json:"result" gorm:"column:result;type:tinyint;not null;comment:结果（0-失败，1-成功，2-被拒绝，3-管理员解锁）"`
+	Reason      string    `json:"reason" gorm:"column:reason;type:varchar(128);default:'';not null;comment:失败或拒绝原因"`
+	OperatorID  int64     `json:"operatorId" gorm:"column:operator_id;default:0;not null;comment:解锁操作的管理员id"`
+	CreateTime  time.Time `json:"createTime" gorm:"column:create_time;autoCreateTime;index:idx_account_time,priority:2;index:idx_ip_time,priority:2;comment:创建时间"`
+}
+
+// TableName 指定表名
+func (LoginAttempt) TableName() string {
+	return "login_attempt"
+}
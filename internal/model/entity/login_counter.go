@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+)
+
+// LoginCounter 登录失败计数实体类，security.login.store 为 mysql 时保存在 login_counter 表
+type LoginCounter struct {
+	CounterKey string    `json:"counterKey" gorm:"column:counter_key;primaryKey;type:varchar(300);comment:计数 key：account:{账号} 或 ip:{客户端 IP}"`
+	Count      int64     `json:"count" gorm:"column:count;default:0;not null;comment:时间窗口内已计入、尚未登录成功的尝试次数"`
+	LastTime   time.Time `json:"lastTime" gorm:"column:last_time;type:datetime(3);not null;comment:最近一次计入的尝试时间"`
+	ExpireTime time.Time `json:"expireTime" gorm:"column:expire_time;type:datetime(3);not null;index:idx_expire_time;comment:过期时间，过期后计数视为 0"`
+}
+
+// TableName 指定表名
+func (LoginCounter) TableName() string {
+	return "login_counter"
+}
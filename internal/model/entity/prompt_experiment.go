@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"time"
+)
+
+// PromptExperimentVariant 实验分组：模板版本与分流权重
+type PromptExperimentVariant struct {
+	Version int `json:"version"` // 模板版本号
+	Weight  int `json:"weight"`  // 分流权重
+}
+
+// PromptExperiment 提示词实验实体类
+type PromptExperiment struct {
+	ID           int64                     `json:"id" gorm:"primaryKey;autoIncrement;comment:id"`
+	TemplateName string                    `json:"templateName" gorm:"column:template_name;type:varchar(64);not null;index:idx_template_status;comment:参与实验的模板名称"`
+	Variants     []PromptExperimentVariant `json:"variants" gorm:"column:variants;type:varchar(1024);serializer:json;not null;comment:实验分组"`
+	Description  string                    `json:"description" gorm:"column:description;type:varchar(512);default:'';not null;comment:实验说明"`
+	Status       int                       `json:"status" gorm:"column:status;type:tinyint;default:1;not null;index:idx_template_status;comment:状态(1-进行中，0-已停止)"`
+	UserID       int64                     `json:"userId" gorm:"column:user_id;not null;comment:创建用户id"`
+	CreateTime   time.Time                 `json:"createTime" gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateTime   time.Time                 `json:"updateTime" gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+}
+
+// TableName 指定表名
+func (PromptExperiment) TableName() string {
+	return "prompt_experiment"
+}
+
+// PromptExperimentRecord 提示词实验记录实体类，每次参与实验的代码生成记录一条
+type PromptExperimentRecord struct {
+	ID              int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:id"`
+	ExperimentID    int64     `json:"experimentId" gorm:"column:experiment_id;not null;index:idx_experiment_version;comment:实验id"`
+	TemplateVersion int       `json:"templateVersion" gorm:"column:template_version;not null;index:idx_experiment_version;comment:分配到的模板版本号"`
+	AppID           int64     `json:"appId" gorm:"column:app_id;not null;index:idx_app_version;comment:应用id"`
+	UserID          int64     `json:"userId" gorm:"column:user_id;not null;comment:用户id"`
+	GenType         string    `json:"genType" gorm:"column:gen_type;type:varchar(64);default:'';not null;comment:代码生成类型"`
+	Model           string    `json:"model" gorm:"column:model;type:varchar(128);default:'';not null;comment:请求的模型名"`
+	Status          int       `json:"status" gorm:"column:status;type:tinyint;default:0;not null;comment:存储结果(0-进行中，1-成功，2-失败)"`
+	Retries         int       `json:"retries" gorm:"column:retries;default:0;not null;comment:输出被截断后请求续写的次数"`
+	AppVersion      int       `json:"appVersion" gorm:"column:app_version;default:0;not null;index:idx_app_version;comment:生成成功后创建的应用版本号"`
+	RolledBack      int       `json:"rolledBack" gorm:"column:rolled_back;type:tinyint;default:0;not null;comment:生成的版本是否被用户回滚"`
+	CreateTime      time.Time `json:"createTime" gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateTime      time.Time `json:"updateTime" gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+}
+
+// TableName 指定表名
+func (PromptExperimentRecord) TableName() string {
+	return "prompt_experiment_record"
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+)
+
+// PromptTemplate 提示词模板实体类，同名模板的每个版本一条记录
+type PromptTemplate struct {
+	ID          int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:id"`
+	Name        string    `json:"name" gorm:"column:name;type:varchar(64);not null;uniqueIndex:uk_name_version;comment:模板名称"`
+	Version     int       `json:"version" gorm:"column:version;not null;uniqueIndex:uk_name_version;comment:版本号"`
+	Content     string    `json:"content" gorm:"column:content;type:mediumtext;not null;comment:模板内容"`
+	Description string    `json:"description" gorm:"column:description;type:varchar(512);default:'';not null;comment:版本说明"`
+	IsActive    int       `json:"isActive" gorm:"column:is_active;type:tinyint;default:0;not null;comment:是否为生效版本"`
+	UserID      int64     `json:"userId" gorm:"column:user_id;default:0;not null;comment:创建用户id"`
+	CreateTime  time.Time `json:"createTime" gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateTime  time.Time `json:"updateTime" gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+	IsDelete    int       `json:"isDelete" gorm:"column:is_delete;type:tinyint;default:0;not null;comment:是否删除(0-未删除，1-已删除)"`
+}
+
+// TableName 指定表名
+func (PromptTemplate) TableName() string {
+	return "prompt_template"
+}
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"time"
+)
+
+// Role 角色实体类，RoleKey 与 User.UserRole 对应
+type Role struct {
+	ID          int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:id"`
+	RoleKey     string    `json:"roleKey" gorm:"column:role_key;type:varchar(64);not null;uniqueIndex:uk_role_key;comment:角色标识"`
+	RoleName    string    `json:"roleName" gorm:"column:role_name;type:varchar(128);not null;comment:角色名称"`
+	Description string    `json:"description" gorm:"column:description;type:varchar(512);default:'';not null;comment:角色说明"`
+	CreateTime  time.Time `json:"createTime" gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateTime  time.Time `json:"updateTime" gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+}
+
+// TableName 指定表名
+func (Role) TableName() string {
+	return "role"
+}
+
+// Permission 权限实体类，PermissionKey 与路由注册时声明的权限标识对应
+type Permission struct {
+	ID             int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:id"`
+	PermissionKey  string    `json:"permissionKey" gorm:"column:permission_key;type:varchar(64);not null;uniqueIndex:uk_permission_key;comment:权限标识"`
+	PermissionName string    `json:"permissionName" gorm:"column:permission_name;type:varchar(128);not null;comment:权限名称"`
+	Description    string    `json:"description" gorm:"column:description;type:varchar(512);default:'';not null;comment:权限说明"`
+	CreateTime     time.Time `json:"createTime" gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+	UpdateTime     time.Time `json:"updateTime" gorm:"column:update_time;autoUpdateTime;comment:更新时间"`
+}
+
+// TableName 指定表名
+func (Permission) TableName() string {
+	return "permission"
+}
+
+// RolePermission 角色权限关联实体类
+type RolePermission struct {
+	ID            int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:id"`
+	RoleKey       string    `json:"roleKey" gorm:"column:role_key;type:varchar(64);not null;uniqueIndex:uk_role_permission;comment:角色标识"`
+	PermissionKey string    `json:"permissionKey" gorm:"column:permission_key;type:varchar(64);not null;uniqueIndex:uk_role_permission;comment:权限标识"`
+	CreateTime    time.Time `json:"createTime" gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+}
+
+// TableName 指定表名
+func (RolePermission) TableName() string {
+	return "role_permission"
+}
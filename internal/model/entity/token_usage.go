@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+)
+
+// TokenUsage Token 用量实体类，每次模型调用记录一条
+type TokenUsage struct {
+	ID               int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:id"`
+	UserID           int64     `json:"userId" gorm:"column:user_id;not null;comment:用户id"`
+	AppID            int64     `json:"appId" gorm:"column:app_id;default:0;not null;comment:应用id"`
+	Model            string    `json:"model" gorm:"column:model;type:varchar(128);not null;comment:实际响应的模型名"`
+	BizType          string    `json:"bizType" gorm:"column:biz_type;type:varchar(32);not null;comment:业务类型：chat/code"`
+	PromptTokens     int       `json:"promptTokens" gorm:"column:prompt_tokens;default:0;not null;comment:输入 token 数"`
+	CompletionTokens int       `json:"completionTokens" gorm:"column:completion_tokens;default:0;not null;comment:输出 token 数"`
+	TotalTokens      int       `json:"totalTokens" gorm:"column:total_tokens;default:0;not null;comment:总 token 数"`
+	CreateTime       time.Time `json:"createTime" gorm:"column:create_time;autoCreateTime;comment:创建时间"`
+}
+
+// TableName 指定表名
+func (TokenUsage) TableName() string {
+	return "token_usage"
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+)
+
+// UserSession 服务端 session 实体类，session.store 为 mysql 时使用
+type UserSession struct {
+	ID             string    `json:"id" gorm:"primaryKey;type:varchar(64);comment:session id"`
+	UserID         int64     `json:"userId" gorm:"column:user_id;default:0;not null;index:idx_user_id;comment:登录用户id，未登录为 0"`
+	Data           []byte    `json:"-" gorm:"column:data;type:blob;comment:gob 编码的 session 值"`
+	ClientIP       string    `json:"clientIp" gorm:"column:client_ip;type:varchar(64);default:'';not null;comment:客户端 IP"`
+	UserAgent      string    `json:"userAgent" gorm:"column:user_agent;type:varchar(512);default:'';not null;comment:客户端 User-Agent"`
+	CreateTime     time.Time `json:"createTime" gorm:"column:create_time;not null;comment:创建时间"`
+	LastAccessTime time.Time `json:"lastAccessTime" gorm:"column:last_access_time;not null;comment:最近访问时间"`
+	ExpireTime     time.Time `json:"expireTime" gorm:"column:expire_time;not null;index:idx_expire_time;comment:过期时间"`
+}
+
+// TableName 指定表名
+func (UserSession) TableName() string {
+	return "user_session"
+}
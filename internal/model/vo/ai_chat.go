@@ -3,20 +3,28 @@ package vo
 import "aicode/consts"
 
 // AIChatRequest 聊天测试请求结构
+// 历史对话由服务端按 ConversationId 保存并回填，不再由前端传递
 type AIChatRequest struct {
-	Model    consts.ChatModelType `json:"model" binding:"required"`
-	Question string               `json:"question" binding:"required"`
-	History  []AIChatMessage      `json:"history,omitempty"`
-}
-
-// AIChatMessage 聊天消息结构（用于前端传递）
-type AIChatMessage struct {
-	Role    consts.ChatRole `json:"role"` // "user" 或 "assistant"
-	Content string          `json:"content"`
+	Model          consts.ChatModelType `json:"model" binding:"required"`
+	ConversationId string               `json:"conversationId" binding:"required,max=64"`
+	Question       string               `json:"question" binding:"required"`
 }
 
 // AIChatResponse 聊天测试响应结构
 type AIChatResponse struct {
 	Question string `json:"question"`
 	Answer   string `json:"answer"`
+	Model    string `json:"model"` // 实际响应的模型，发生降级时与请求的模型不同
+}
+
+// ChatModelVO 可用聊天模型信息
+type ChatModelVO struct {
+	Name            string                `json:"name"`            // 模型名，即请求中 model 字段的取值
+	DisplayName     string                `json:"displayName"`     // 展示名称
+	Provider        string                `json:"provider"`        // 供应商类型
+	RespTypes       []consts.ChatRespType `json:"respTypes"`       // 支持的响应类型：generate/stream
+	ContextWindow   int                   `json:"contextWindow"`   // 上下文窗口大小（token），0 表示未知
+	SupportToolCall bool                  `json:"supportToolCall"` // 是否支持工具调用
+	SupportVision   bool                  `json:"supportVision"`   // 是否支持图片输入
+	Allowed         bool                  `json:"allowed"`         // 当前用户角色是否允许使用
 }
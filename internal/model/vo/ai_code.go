@@ -2,23 +2,77 @@ package vo
 
 import "aicode/consts"
 
-// AIChatRequest 聊天测试请求结构
+// AICodeRequest 代码生成请求结构
+// 历史对话由服务端按 AppId 保存并回填，不再由前端传递；
+// 请求可以是 JSON（图片以 base64 或 data URL 传递），也可以是 multipart 表单（图片以 images 文件字段上传）
 type AICodeRequest struct {
-	AppId    string                  `json:"appId" binding:"required"`
-	Model    consts.ChatModelType    `json:"model" binding:"required"`
-	GenType  consts.CodeGenarateType `json:"genType" binding:"required"`
-	Question string                  `json:"question" binding:"required"`
-	History  []AICodeMessage         `json:"history,omitempty"`
+	AppId    int64                   `json:"appId" form:"appId" binding:"required"`
+	Model    consts.ChatModelType    `json:"model" form:"model" binding:"required"`
+	GenType  consts.CodeGenarateType `json:"genType" form:"genType" binding:"required"`
+	Question string                  `json:"question" form:"question" binding:"required"`
+	Images   []string                `json:"images" form:"-" binding:"omitempty"` // 设计稿等参考图片，仅支持图片输入的模型可用
+
+	// ExperimentRecordID 服务端分配的提示词实验记录id，不由前端传递，未参与实验时为 0
+	ExperimentRecordID int64 `json:"-" form:"-"`
+}
+
+// AICodeResponse 代码生成响应结构
+type AICodeResponse struct {
+	Content string `json:"content"` // 模型输出的原始内容
+	Model   string `json:"model"`   // 实际响应的模型，发生降级时与请求的模型不同
+}
+
+// CodeJobVO 流式代码生成任务信息
+type CodeJobVO struct {
+	JobID string `json:"jobId"` // 任务id，用于续传与取消
+	Model string `json:"model"` // 实际响应的模型，发生降级时与请求的模型不同
+}
+
+// CodeJobEvent 任务缓存的 SSE 事件，ID 在任务内从 1 递增，客户端断线后以 Last-Event-ID 续传
+type CodeJobEvent struct {
+	ID    int64
+	Event string
+	Data  any
 }
 
-// AICodeMessage 代码消息结构（用于前端传递）
-type AICodeMessage struct {
-	Role    consts.ChatRole `json:"role"` // "user" 或 "assistant"
-	Content string          `json:"content"`
+// CodeJobCancelRequest 取消代码生成任务请求
+type CodeJobCancelRequest struct {
+	JobID string `json:"jobId" binding:"required"` // 任务id
+}
+
+// CodeJobResumeRequest 续传代码生成任务事件请求
+type CodeJobResumeRequest struct {
+	JobID       string `form:"jobId" binding:"required"` // 任务id
+	LastEventID int64  `form:"lastEventId"`              // 已收到的最后一个事件id，Last-Event-ID 请求头优先
 }
 
 // CodeStreamResult channel 中传递的流式结果单元
+// Event 非空时表示一个独立类型的 SSE 事件，以 Event 为事件名推送 Payload
 type CodeStreamResult struct {
 	Content string
+	Event   consts.CodeStreamEvent
+	Payload any
 	Err     error
 }
+
+// ToolCallEvent 模型发起工具调用事件
+type ToolCallEvent struct {
+	ID        string `json:"id"`        // 工具调用 id
+	Name      string `json:"name"`      // 工具名
+	Arguments string `json:"arguments"` // 调用参数（JSON）
+}
+
+// ToolResultEvent 工具执行结果事件
+type ToolResultEvent struct {
+	ID      string `json:"id"`      // 工具调用 id
+	Name    string `json:"name"`    // 工具名
+	Success bool   `json:"success"` // 是否执行成功
+	Result  string `json:"result"`  // 执行结果（过长时截断）
+	Error   string `json:"error"`   // 失败原因
+}
+
+// FileEvent 增量解析出的文件事件，file_chunk 事件携带内容增量
+type FileEvent struct {
+	Path    string `json:"path"`              // 文件相对路径
+	Content string `json:"content,omitempty"` // 内容增量
+}
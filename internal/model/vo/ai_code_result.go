@@ -11,3 +11,14 @@ type MultiHTMLResult struct {
 	CSS        string `json:"css"`
 	JavaScript string `json:"javascript"`
 }
+
+// ProjectFileResult 工程模式生成结果，对应 vue_project_generate 提示词输出的 JSON 结构
+type ProjectFileResult struct {
+	Files []ProjectFile `json:"files"`
+}
+
+// ProjectFile 工程中的单个文件
+type ProjectFile struct {
+	Path    string `json:"path"`    // 相对于工程根目录的路径，如 src/App.js
+	Content string `json:"content"` // 文件完整内容
+}
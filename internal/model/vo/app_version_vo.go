@@ -0,0 +1,28 @@
+package vo
+
+import "time"
+
+// AppVersionVO 应用代码版本信息
+type AppVersionVO struct {
+	Version    int       `json:"version"`    // 版本号
+	Prompt     string    `json:"prompt"`     // 生成该版本的用户 prompt
+	Model      string    `json:"model"`      // 实际响应的模型名
+	GenType    string    `json:"genType"`    // 代码生成类型
+	UserID     int64     `json:"userId"`     // 创建用户id
+	CreateTime time.Time `json:"createTime"` // 创建时间
+}
+
+// AppVersionDiffVO 两个版本之间的差异
+type AppVersionDiffVO struct {
+	From  int                    `json:"from"`  // 起始版本号
+	To    int                    `json:"to"`    // 目标版本号
+	Files []AppVersionFileDiffVO `json:"files"` // 变更文件列表，未变更的文件不返回
+}
+
+// AppVersionFileDiffVO 单个文件的差异
+type AppVersionFileDiffVO struct {
+	Path   string `json:"path"`   // 相对于应用目录的路径
+	Status string `json:"status"` // 变更类型：added/removed/modified
+	Binary bool   `json:"binary"` // 是否为二进制或过大的文件，此时不返回 diff
+	Diff   string `json:"diff"`   // unified diff 文本
+}
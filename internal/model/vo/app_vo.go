@@ -0,0 +1,20 @@
+package vo
+
+import "time"
+
+// AppVO 应用信息
+type AppVO struct {
+	ID           int64      `json:"id"`           // id
+	AppName      string     `json:"appName"`      // 应用名称
+	Cover        string     `json:"cover"`        // 应用封面
+	InitPrompt   string     `json:"initPrompt"`   // 应用初始化的 prompt
+	CodeGenType  string     `json:"codeGenType"`  // 代码生成类型
+	Priority     int        `json:"priority"`     // 优先级
+	DeployKey    string     `json:"deployKey"`    // 部署标识，未部署时为空
+	DeployURL    string     `json:"deployUrl"`    // 部署访问地址，未部署时为空
+	DeployedTime *time.Time `json:"deployedTime"` // 最近一次部署时间
+	UserID       int64      `json:"userId"`       // 创建用户id
+	User         *UserVO    `json:"user"`         // 创建用户信息
+	CreateTime   time.Time  `json:"createTime"`   // 创建时间
+	UpdateTime   time.Time  `json:"updateTime"`   // 更新时间
+}
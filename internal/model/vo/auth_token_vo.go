@@ -0,0 +1,29 @@
+package vo
+
+import "time"
+
+// AuthTokenVO 签发的令牌
+type AuthTokenVO struct {
+	AccessToken      string    `json:"accessToken"`      // access 令牌，通过请求头 Authorization: Bearer {accessToken} 访问接口
+	RefreshToken     string    `json:"refreshToken"`     // refresh 令牌，只能用于换取新的令牌，每次刷新后旧令牌失效
+	TokenType        string    `json:"tokenType"`        // 令牌类型，固定为 Bearer
+	ExpiresIn        int64     `json:"expiresIn"`        // access 令牌有效期（秒）
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"` // refresh 令牌过期时间
+}
+
+// ApiKeyVO 个人 API Key 信息，不包含密钥
+type ApiKeyVO struct {
+	ID           int64      `json:"id"`           // id
+	Name         string     `json:"name"`         // 名称
+	KeyPrefix    string     `json:"keyPrefix"`    // 密钥前缀，用于辨认
+	Scopes       []string   `json:"scopes"`       // 授权范围
+	ExpireTime   *time.Time `json:"expireTime"`   // 过期时间，为空表示永不过期
+	LastUsedTime *time.Time `json:"lastUsedTime"` // 最近一次使用时间
+	CreateTime   time.Time  `json:"createTime"`   // 创建时间
+}
+
+// ApiKeyCreatedVO 新建的 API Key，密钥明文只返回这一次
+type ApiKeyCreatedVO struct {
+	ApiKeyVO
+	Key string `json:"key"` // 密钥明文
+}
@@ -0,0 +1,20 @@
+package vo
+
+import "time"
+
+// ChatHistoryVO 对话消息
+type ChatHistoryVO struct {
+	ID             int64     `json:"id"`             // id
+	Message        string    `json:"message"`        // 消息内容
+	MessageType    string    `json:"messageType"`    // 消息类型：user/assistant
+	AppID          int64     `json:"appId"`          // 应用id
+	ConversationID string    `json:"conversationId"` // 会话id
+	CreateTime     time.Time `json:"createTime"`     // 创建时间
+}
+
+// ChatHistoryCursorPageVO 对话历史游标分页结果，记录按时间倒序排列
+type ChatHistoryCursorPageVO struct {
+	Records    []ChatHistoryVO `json:"records"`    // 消息列表
+	NextCursor int64           `json:"nextCursor"` // 下一页游标，hasMore 为 false 时无意义
+	HasMore    bool            `json:"hasMore"`    // 是否还有更早的消息
+}
@@ -0,0 +1,20 @@
+package vo
+
+import "time"
+
+// CodeGenJobVO 异步代码生成任务信息
+type CodeGenJobVO struct {
+	ID            int64      `json:"id"`                // 任务id
+	AppID         int64      `json:"appId"`             // 应用id
+	Model         string     `json:"model"`             // 请求的模型名
+	GenType       string     `json:"genType"`           // 代码生成类型
+	Question      string     `json:"question"`          // 用户提问
+	Status        string     `json:"status"`            // 状态：queued/running/succeeded/failed/cancelled
+	QueuePosition int        `json:"queuePosition"`     // 排队位置，从 1 开始，不在排队时为 0
+	ResultModel   string     `json:"resultModel"`       // 实际响应的模型，发生降级时与请求的模型不同
+	Content       string     `json:"content,omitempty"` // 模型输出的原始内容，仅查询单个任务时返回
+	ErrorMsg      string     `json:"errorMsg"`          // 失败原因
+	StartTime     *time.Time `json:"startTime"`         // 开始执行时间
+	FinishTime    *time.Time `json:"finishTime"`        // 结束时间
+	CreateTime    time.Time  `json:"createTime"`        // 提交时间
+}
@@ -0,0 +1,15 @@
+package vo
+
+import "time"
+
+// LoginAttemptVO 登录记录
+type LoginAttemptVO struct {
+	ID          int64     `json:"id"`          // id
+	UserAccount string    `json:"userAccount"` // 登录时提交的账号
+	ClientIP    string    `json:"clientIp"`    // 客户端 IP
+	UserAgent   string    `json:"userAgent"`   // 客户端 User-Agent
+	Result      int       `json:"result"`      // 结果：0-失败，1-成功，2-被拒绝，3-管理员解锁
+	Reason      string    `json:"reason"`      // 失败或拒绝原因
+	OperatorID  int64     `json:"operatorId"`  // 解锁操作的管理员id
+	CreateTime  time.Time `json:"createTime"`  // 时间
+}
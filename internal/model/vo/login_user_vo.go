@@ -12,4 +12,6 @@ type LoginUserVO struct {
 	UserRole    string    `json:"userRole"`    // 用户角色：user/admin
 	CreateTime  time.Time `json:"createTime"`  // 创建时间
 	UpdateTime  time.Time `json:"updateTime"`  // 更新时间
+
+	Token *AuthTokenVO `json:"token,omitempty"` // 登录时签发的令牌，供不使用 cookie 的客户端调用
 }
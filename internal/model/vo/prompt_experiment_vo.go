@@ -0,0 +1,47 @@
+package vo
+
+import "time"
+
+// PromptExperimentVO 提示词实验信息
+type PromptExperimentVO struct {
+	ID           int64                       `json:"id"`           // 实验id
+	TemplateName string                      `json:"templateName"` // 参与实验的模板名称
+	Variants     []PromptExperimentVariantVO `json:"variants"`     // 实验分组
+	Description  string                      `json:"description"`  // 实验说明
+	Running      bool                        `json:"running"`      // 是否进行中
+	UserID       int64                       `json:"userId"`       // 创建用户id
+	CreateTime   time.Time                   `json:"createTime"`   // 创建时间
+}
+
+// PromptExperimentVariantVO 实验分组
+type PromptExperimentVariantVO struct {
+	Version int `json:"version"` // 模板版本号
+	Weight  int `json:"weight"`  // 分流权重
+}
+
+// PromptExperimentStatsVO 提示词实验按分组汇总的结果
+type PromptExperimentStatsVO struct {
+	Experiment PromptExperimentVO     `json:"experiment"` // 实验信息
+	Variants   []PromptVariantStatsVO `json:"variants"`   // 各分组结果，按实验分组顺序排列
+}
+
+// PromptVariantStatsVO 单个分组的结果信号
+type PromptVariantStatsVO struct {
+	Version      int     `json:"version"`      // 模板版本号
+	Weight       int     `json:"weight"`       // 分流权重
+	Total        int64   `json:"total"`        // 参与的生成次数
+	Success      int64   `json:"success"`      // 代码存储成功次数
+	Failed       int64   `json:"failed"`       // 代码存储失败次数（含解析失败、生成中断）
+	SuccessRate  float64 `json:"successRate"`  // 存储成功率，按已结束的生成计算
+	Retries      int64   `json:"retries"`      // 输出被截断后请求续写的总次数
+	AvgRetries   float64 `json:"avgRetries"`   // 平均每次生成的续写次数
+	RolledBack   int64   `json:"rolledBack"`   // 生成的版本被用户回滚的次数
+	RollbackRate float64 `json:"rollbackRate"` // 回滚率，按存储成功的生成计算
+}
+
+// PromptAssignment 代码生成请求分配到的实验分组
+type PromptAssignment struct {
+	ExperimentID int64 // 实验id
+	RecordID     int64 // 实验记录id，用于回写结果信号
+	Version      int   // 分配到的模板版本号
+}
@@ -0,0 +1,25 @@
+package vo
+
+import "time"
+
+// PromptTemplateVO 提示词模板版本信息
+type PromptTemplateVO struct {
+	ID          int64     `json:"id"`          // id
+	Name        string    `json:"name"`        // 模板名称
+	Version     int       `json:"version"`     // 版本号
+	Content     string    `json:"content"`     // 模板内容
+	Description string    `json:"description"` // 版本说明
+	IsActive    bool      `json:"isActive"`    // 是否为生效版本
+	UserID      int64     `json:"userId"`      // 创建用户id
+	CreateTime  time.Time `json:"createTime"`  // 创建时间
+}
+
+// PromptVariables 渲染提示词模板时可用的变量，模板中以 {{.GenType}} 等形式引用
+type PromptVariables struct {
+	GenType  string // 代码生成类型
+	Model    string // 请求的模型名
+	AppId    int64  // 应用id
+	AppName  string // 应用名称
+	UserName string // 当前用户昵称
+	Date     string // 当前日期，格式 2006-01-02
+}
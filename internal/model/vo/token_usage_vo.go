@@ -0,0 +1,18 @@
+package vo
+
+// TokenUsageSummaryVO 当前用户的 token 用量与配额
+type TokenUsageSummaryVO struct {
+	DailyUsed    int64          `json:"dailyUsed"`    // 今日已用 token
+	DailyQuota   int64          `json:"dailyQuota"`   // 每日配额，0 表示不限制
+	MonthlyUsed  int64          `json:"monthlyUsed"`  // 本月已用 token
+	MonthlyQuota int64          `json:"monthlyQuota"` // 每月配额，0 表示不限制
+	Models       []ModelUsageVO `json:"models"`       // 本月按模型汇总的用量
+}
+
+// ModelUsageVO 按模型汇总的 token 用量
+type ModelUsageVO struct {
+	Model            string `json:"model"`            // 模型名
+	PromptTokens     int64  `json:"promptTokens"`     // 输入 token 数
+	CompletionTokens int64  `json:"completionTokens"` // 输出 token 数
+	TotalTokens      int64  `json:"totalTokens"`      // 总 token 数
+}
@@ -0,0 +1,13 @@
+package vo
+
+import "time"
+
+// UserSessionVO 用户登录 session 信息
+type UserSessionVO struct {
+	SessionID      string    `json:"sessionId"`      // session id
+	ClientIP       string    `json:"clientIp"`       // 最近一次保存 session 时的客户端 IP
+	UserAgent      string    `json:"userAgent"`      // 最近一次保存 session 时的客户端 User-Agent
+	CreateTime     time.Time `json:"createTime"`     // 创建时间
+	LastAccessTime time.Time `json:"lastAccessTime"` // 最近访问时间，精度为 1 分钟
+	ExpireTime     time.Time `json:"expireTime"`     // 过期时间
+}
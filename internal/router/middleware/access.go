@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLevel 路由的访问级别
+type AccessLevel int
+
+const (
+	// AccessUser 需要登录，未声明访问级别的路由默认使用该级别
+	AccessUser AccessLevel = iota
+	// AccessPublic 无需登录
+	AccessPublic
+	// AccessAdmin 需要管理员角色
+	AccessAdmin
+	// AccessPermission 需要登录用户的角色拥有指定权限
+	AccessPermission
+)
+
+// RouteAccess 路由的访问控制声明
+type RouteAccess struct {
+	Level      AccessLevel
+	Permission string // Level 为 AccessPermission 时需要的权限标识
+}
+
+// routeAccessTable 路由访问控制声明表，key 为 "方法 完整路径"，完整路径与 gin 的 FullPath 一致，已包含 rootPath
+var (
+	routeAccessMu    sync.RWMutex
+	routeAccessTable = make(map[string]RouteAccess)
+)
+
+// lookupRouteAccess 查询路由的访问控制声明，未声明的路由需要登录
+func lookupRouteAccess(method, fullPath string) RouteAccess {
+	routeAccessMu.RLock()
+	defer routeAccessMu.RUnlock()
+	if access, ok := routeAccessTable[method+" "+fullPath]; ok {
+		return access
+	}
+	return RouteAccess{Level: AccessUser}
+}
+
+// AccessGroup 带访问控制声明的路由注册器，通过它注册的路由由 AuthMiddleware 按声明校验
+type AccessGroup struct {
+	group  *gin.RouterGroup
+	access RouteAccess
+}
+
+// Public 返回注册无需登录路由的注册器
+func Public(group *gin.RouterGroup) *AccessGroup {
+	return &AccessGroup{group: group, access: RouteAccess{Level: AccessPublic}}
+}
+
+// User 返回注册需要登录路由的注册器，与直接在路由组上注册效果相同
+func User(group *gin.RouterGroup) *AccessGroup {
+	return &AccessGroup{group: group, access: RouteAccess{Level: AccessUser}}
+}
+
+// Admin 返回注册仅限管理员路由的注册器
+func Admin(group *gin.RouterGroup) *AccessGroup {
+	return &AccessGroup{group: group, access: RouteAccess{Level: AccessAdmin}}
+}
+
+// Permission 返回注册需要指定权限路由的注册器
+func Permission(group *gin.RouterGroup, permission string) *AccessGroup {
+	return &AccessGroup{group: group, access: RouteAccess{Level: AccessPermission, Permission: permission}}
+}
+
+// Handle 注册路由并记录访问控制声明
+func (g *AccessGroup) Handle(method, relativePath string, handlers ...gin.HandlerFunc) {
+	g.group.Handle(method, relativePath, handlers...)
+	g.annotate(method, relativePath)
+}
+
+// GET 注册 GET 路由
+func (g *AccessGroup) GET(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodGet, relativePath, handlers...)
+}
+
+// POST 注册 POST 路由
+func (g *AccessGroup) POST(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodPost, relativePath, handlers...)
+}
+
+// HEAD 注册 HEAD 路由
+func (g *AccessGroup) HEAD(relativePath string, handlers ...gin.HandlerFunc) {
+	g.Handle(http.MethodHead, relativePath, handlers...)
+}
+
+// Static 注册静态文件目录，与 gin 的 Static 相同会注册 GET 与 HEAD 两条路由
+func (g *AccessGroup) Static(relativePath, root string) {
+	g.group.Static(relativePath, root)
+	urlPattern := path.Join(relativePath, "/*filepath")
+	g.annotate(http.MethodGet, urlPattern)
+	g.annotate(http.MethodHead, urlPattern)
+}
+
+// annotate 以与 gin 相同的规则计算完整路径并记录访问控制声明
+func (g *AccessGroup) annotate(method, relativePath string) {
+	fullPath := joinPaths(g.group.BasePath(), relativePath)
+	routeAccessMu.Lock()
+	defer routeAccessMu.Unlock()
+	routeAccessTable[method+" "+fullPath] = g.access
+}
+
+// joinPaths 拼接路由组路径与相对路径，规则与 gin 内部一致：保留相对路径末尾的 "/"
+func joinPaths(absolutePath, relativePath string) string {
+	if relativePath == "" {
+		return absolutePath
+	}
+	finalPath := path.Join(absolutePath, relativePath)
+	if relativePath[len(relativePath)-1] == '/' && finalPath[len(finalPath)-1] != '/' {
+		return finalPath + "/"
+	}
+	return finalPath
+}
@@ -3,7 +3,10 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"slices"
+	"strings"
 
+	"aicode/config"
 	"aicode/constant"
 	"aicode/internal/common"
 	"aicode/internal/exception"
@@ -13,49 +16,85 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// skipRoute 描述一条不需要登录校验的路由（方法 + 完整路径）
-type skipRoute struct {
-	Method string
-	Path   string
+// Authenticator 按请求携带的凭证加载登录用户
+type Authenticator interface {
+	// AuthenticateBearer 返回请求头 Authorization: Bearer {token} 中凭证对应的登录用户；scopes 为 nil 表示不限制访问范围
+	AuthenticateBearer(ctx context.Context, token string) (user *entity.User, scopes []string, err error)
+
+	// AuthenticateSession 按 session 中保存的用户 id 加载登录用户
+	AuthenticateSession(ctx context.Context, userId int64) (*entity.User, error)
 }
 
-// skipRoutes 白名单列表，路径须含 rootPath 前缀，与 config.yml server.root_path 保持一致
-// 后续需要跳过登录校验的接口，直接在此处追加即可
-var skipRoutes = []skipRoute{
-	{http.MethodPost, "/api/v1/user/register"},
-	{http.MethodPost, "/api/v1/user/login"},
-	{http.MethodGet, "/swagger/*any"},
+// PermissionChecker 判断角色是否拥有权限
+type PermissionChecker interface {
+	// HasPermission 判断角色是否拥有权限
+	HasPermission(ctx context.Context, role, permission string) (bool, error)
 }
 
-// AuthMiddleware 登录态校验中间件
-//   - 白名单内的路由直接放行
-//   - 其余路由从 session 中读取登录用户，未登录则返回 NotLoginError
+// AuthMiddleware 登录态与访问控制校验中间件，按路由注册时声明的访问级别（见 access.go）校验
+//   - 无需登录的路由直接放行
+//   - 携带 Authorization: Bearer 请求头时按 access 令牌或个人 API Key 认证，不再读取 session；
+//     API Key 只能访问授权范围内的路由组
+//   - 否则从 session 中读取登录用户 id 并重新加载用户，未登录则返回 NotLoginError
+//   - 仅限管理员的路由要求管理员角色，声明了权限的路由要求登录用户的角色拥有该权限，否则返回 NoAuthError
 //   - 登录用户同时写入 gin context（c.Set）和 request context（context.WithValue），
 //     后续业务层可通过 c.Get(constant.UserLoginState) 或 ctx.Value(constant.UserLoginState) 取用
-func AuthMiddleware() gin.HandlerFunc {
+func AuthMiddleware(authenticator Authenticator, permissionChecker PermissionChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 白名单检查
-		for _, route := range skipRoutes {
-			if c.Request.Method == route.Method && c.FullPath() == route.Path {
-				c.Next()
-				return
-			}
+		access := lookupRouteAccess(c.Request.Method, c.FullPath())
+		if access.Level == AccessPublic {
+			c.Next()
+			return
 		}
 
-		// 从 session 中获取登录用户
-		session := sessions.Default(c)
-		userObj := session.Get(constant.UserLoginState)
-		if userObj == nil {
-			c.JSON(http.StatusOK, common.Error(exception.NotLoginError))
-			c.Abort()
-			return
+		var loginUser *entity.User
+		if token, ok := bearerToken(c); ok {
+			user, scopes, err := authenticator.AuthenticateBearer(c.Request.Context(), token)
+			if err != nil {
+				abortAuthError(c, err)
+				return
+			}
+			if scopes != nil && !scopeAllows(scopes, routeGroup(c.FullPath()), c.Request.Method) {
+				c.JSON(http.StatusOK, common.ErrorWithMessage(exception.NoAuthError, "API Key 未授权访问该接口"))
+				c.Abort()
+				return
+			}
+			loginUser = user
+		} else {
+			// session 中只保存登录用户 id，每次请求重新加载用户，角色变更与删除即时生效
+			userId, ok := sessions.Default(c).Get(constant.UserLoginState).(int64)
+			if !ok || userId <= 0 {
+				c.JSON(http.StatusOK, common.Error(exception.NotLoginError))
+				c.Abort()
+				return
+			}
+			user, err := authenticator.AuthenticateSession(c.Request.Context(), userId)
+			if err != nil {
+				abortAuthError(c, err)
+				return
+			}
+			loginUser = user
 		}
 
-		loginUser, ok := userObj.(*entity.User)
-		if !ok || loginUser == nil || loginUser.ID == 0 {
-			c.JSON(http.StatusOK, common.Error(exception.NotLoginError))
-			c.Abort()
-			return
+		switch access.Level {
+		case AccessAdmin:
+			if loginUser.UserRole != constant.AdminRole {
+				c.JSON(http.StatusOK, common.Error(exception.NoAuthError))
+				c.Abort()
+				return
+			}
+		case AccessPermission:
+			allowed, err := permissionChecker.HasPermission(c.Request.Context(), loginUser.UserRole, access.Permission)
+			if err != nil {
+				c.JSON(http.StatusOK, common.ErrorWithMessage(exception.SystemError, "查询角色权限失败"))
+				c.Abort()
+				return
+			}
+			if !allowed {
+				c.JSON(http.StatusOK, common.Error(exception.NoAuthError))
+				c.Abort()
+				return
+			}
 		}
 
 		// 将用户信息写入 gin context
@@ -67,3 +106,45 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// abortAuthError 认证失败时返回业务错误，非业务错误统一视为未登录
+func abortAuthError(c *gin.Context, err error) {
+	if bizErr, ok := err.(*exception.BusinessError); ok {
+		c.JSON(http.StatusOK, common.ErrorWithCode(bizErr.Code(), bizErr.Message()))
+	} else {
+		c.JSON(http.StatusOK, common.Error(exception.NotLoginError))
+	}
+	c.Abort()
+}
+
+// bearerToken 读取 Authorization: Bearer 请求头中的凭证
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// routeGroup 从路由路径中取出 rootPath 之后的第一段，即路由组名
+func routeGroup(fullPath string) string {
+	rootPath := strings.TrimSuffix(config.GetConfig().Server.RootPath, "/")
+	rest := strings.TrimPrefix(strings.TrimPrefix(fullPath, rootPath), "/")
+	group, _, _ := strings.Cut(rest, "/")
+	return group
+}
+
+// scopeAllows 判断 API Key 的授权范围是否允许访问路由组；只读授权只允许 GET/HEAD 请求，
+// API Key 管理接口不允许通过 API Key 访问
+func scopeAllows(scopes []string, group, method string) bool {
+	if group == "" || group == constant.ApiKeyRouteGroup {
+		return false
+	}
+	if slices.Contains(scopes, constant.ApiKeyScopeAll) || slices.Contains(scopes, group) {
+		return true
+	}
+	readOnly := method == http.MethodGet || method == http.MethodHead
+	return readOnly && slices.Contains(scopes, group+constant.ApiKeyScopeReadSuffix)
+}
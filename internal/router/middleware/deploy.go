@@ -0,0 +1,18 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// deployContentSecurityPolicy 已部署站点的内容安全策略：sandbox 使页面运行在不透明源中，
+// 页面脚本无法读取本站 cookie，也无法以登录用户身份调用接口；仍允许脚本、表单、弹窗与对话框
+const deployContentSecurityPolicy = "sandbox allow-scripts allow-forms allow-popups allow-modals"
+
+// DeploySandboxMiddleware 已部署站点隔离中间件
+// 部署的页面包含用户或模型生成的任意 HTML/JS，与接口同源提供时须隔离，防止存储型 XSS 冒用访问者的登录态；
+// 生产环境建议通过 file.deploy_base_url 将部署站点放到独立域名
+func DeploySandboxMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Security-Policy", deployContentSecurityPolicy)
+		c.Header("X-Content-Type-Options", "nosniff")
+		c.Next()
+	}
+}
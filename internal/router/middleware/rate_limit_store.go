@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimitStore 令牌桶存储，Allow 从 key 对应的桶中取走一个令牌，桶为空时返回 false
+// rate 为每秒补充的令牌数，burst 为桶容量
+type RateLimitStore interface {
+	Allow(ctx context.Context, key string, rate float64, burst int) (bool, error)
+}
+
+// memoryBucket 内存令牌桶，记录所属规则的 rate 与 burst，清理时按各自的回满时间判断
+type memoryBucket struct {
+	tokens   float64
+	lastTime time.Time
+	rate     float64
+	burst    int
+}
+
+// full 判断桶在 now 时是否已经回满，不补充令牌的桶永远不会回满
+func (b *memoryBucket) full(now time.Time) bool {
+	if b.rate <= 0 {
+		return false
+	}
+	return b.tokens+now.Sub(b.lastTime).Seconds()*b.rate >= float64(b.burst)
+}
+
+// memoryStoreSweepInterval 内存存储清理已回满的桶的间隔
+const memoryStoreSweepInterval = time.Minute
+
+// MemoryRateLimitStore 基于进程内存的令牌桶存储，仅适用于单实例部署
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+// NewMemoryRateLimitStore 创建内存令牌桶存储
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:   make(map[string]*memoryBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow 取走一个令牌
+func (s *MemoryRateLimitStore) Allow(_ context.Context, key string, rate float64, burst int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(burst), lastTime: now}
+		s.buckets[key] = bucket
+	}
+	bucket.rate = rate
+	bucket.burst = burst
+	elapsed := now.Sub(bucket.lastTime).Seconds()
+	bucket.tokens = math.Min(float64(burst), bucket.tokens+elapsed*rate)
+	bucket.lastTime = now
+	if bucket.tokens < 1 {
+		return false, nil
+	}
+	bucket.tokens--
+	return true, nil
+}
+
+// sweep 定期删除已经回满的桶，避免按 IP 计数时 map 无限增长
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memoryStoreSweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, bucket := range s.buckets {
+		if bucket.full(now) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// tokenBucketScript 令牌桶 Lua 脚本，保证多实例并发下取令牌的原子性
+// KEYS[1] 桶 key；ARGV: rate（每秒）、burst、当前时间（毫秒）
+// 桶在回满所需时间后自动过期
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+local elapsed = math.max(0, now - ts)
+tokens = math.min(burst, tokens + elapsed * rate / 1000)
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return allowed
+`)
+
+// RedisRateLimitStore 基于 Redis 的令牌桶存储，多实例部署时共享限流状态
+// 兼容任意实现了 EVALSHA/EVAL、HMGET/HSET、PEXPIRE 的 Redis 协议服务
+type RedisRateLimitStore struct {
+	client redis.Scripter
+	prefix string
+}
+
+// NewRedisRateLimitStore 创建 Redis 令牌桶存储，prefix 为桶 key 的前缀
+func NewRedisRateLimitStore(client redis.Scripter, prefix string) *RedisRateLimitStore {
+	return &RedisRateLimitStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Allow 取走一个令牌
+func (s *RedisRateLimitStore) Allow(ctx context.Context, key string, rate float64, burst int) (bool, error) {
+	allowed, err := tokenBucketScript.Run(ctx, s.client, []string{s.prefix + key},
+		rate, burst, time.Now().UnixMilli()).Int()
+	if err != nil {
+		return false, err
+	}
+	return allowed == 1, nil
+}
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/base32"
+	"encoding/gob"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"aicode/constant"
+	"aicode/internal/mapper"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/securecookie"
+	gsessions "github.com/gorilla/sessions"
+)
+
+// sessionTouchInterval 刷新 session 最近访问时间的最小间隔，避免每次请求都写存储
+const sessionTouchInterval = time.Minute
+
+// SessionStore 服务端 session 存储，cookie 中只保存签名后的 session id，session 值保存在 mapper.SessionMapper 中
+// session 在自创建起超过 ttl，或超过 idleTimeout 无请求时失效；登录用户变化时更换 session id
+type SessionStore struct {
+	codecs      []securecookie.Codec
+	options     *gsessions.Options
+	backend     mapper.SessionMapper
+	ttl         time.Duration
+	idleTimeout time.Duration
+}
+
+// NewSessionStore 创建服务端 session 存储，keyPairs 为 cookie 签名（与可选的加密）密钥
+func NewSessionStore(backend mapper.SessionMapper, ttl, idleTimeout time.Duration, keyPairs ...[]byte) *SessionStore {
+	store := &SessionStore{
+		codecs:      securecookie.CodecsFromPairs(keyPairs...),
+		backend:     backend,
+		ttl:         ttl,
+		idleTimeout: idleTimeout,
+	}
+	store.Options(sessions.Options{Path: "/", MaxAge: int(ttl.Seconds()), HttpOnly: true,
+		SameSite: http.SameSiteLaxMode})
+	return store
+}
+
+// Options 设置 cookie 选项，cookie 签名的有效期与 MaxAge 一致
+func (s *SessionStore) Options(options sessions.Options) {
+	s.options = options.ToGorillaOptions()
+	for _, codec := range s.codecs {
+		if sc, ok := codec.(*securecookie.SecureCookie); ok {
+			sc.MaxAge(options.MaxAge)
+		}
+	}
+}
+
+// Get 返回请求内缓存的 session
+func (s *SessionStore) Get(r *http.Request, name string) (*gsessions.Session, error) {
+	return gsessions.GetRegistry(r).Get(s, name)
+}
+
+// New 按 cookie 中的 session id 加载 session，cookie 无效或 session 已失效时返回新的空 session
+func (s *SessionStore) New(r *http.Request, name string) (*gsessions.Session, error) {
+	session := gsessions.NewSession(s, name)
+	options := *s.options
+	session.Options = &options
+	session.IsNew = true
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+	var id string
+	if err := securecookie.DecodeMulti(name, cookie.Value, &id, s.codecs...); err != nil {
+		// 签名无效或已过期，视为新 session
+		return session, nil
+	}
+	record, err := s.backend.Get(r.Context(), id)
+	if err != nil {
+		return session, err
+	}
+	if record == nil {
+		return session, nil
+	}
+	if err := gob.NewDecoder(bytes.NewReader(record.Data)).Decode(&session.Values); err != nil {
+		return session, err
+	}
+	session.ID = id
+	session.IsNew = false
+
+	now := time.Now()
+	if now.Sub(record.LastAccessTime) >= sessionTouchInterval {
+		if err := s.backend.Touch(r.Context(), id, now, s.expireTime(record.CreateTime, now)); err != nil {
+			return session, err
+		}
+	}
+	return session, nil
+}
+
+// Save 保存 session 并写入 cookie；Options.MaxAge 小于 0 或 session 已清空时删除 session
+func (s *SessionStore) Save(r *http.Request, w http.ResponseWriter, session *gsessions.Session) error {
+	ctx := r.Context()
+	if session.Options.MaxAge < 0 || len(session.Values) == 0 {
+		if session.ID != "" {
+			if err := s.backend.Delete(ctx, session.ID); err != nil {
+				return err
+			}
+		}
+		for key := range session.Values {
+			delete(session.Values, key)
+		}
+		options := *session.Options
+		options.MaxAge = -1
+		http.SetCookie(w, gsessions.NewCookie(session.Name(), "", &options))
+		return nil
+	}
+
+	now := time.Now()
+	createTime := now
+	userId := sessionUserId(session)
+	if session.ID != "" {
+		existing, err := s.backend.Get(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+		switch {
+		case existing == nil:
+			session.ID = ""
+		case existing.UserID != userId:
+			// 登录、注销或切换用户时更换 session id，防止会话固定攻击
+			if err := s.backend.Delete(ctx, session.ID); err != nil {
+				return err
+			}
+			session.ID = ""
+		default:
+			createTime = existing.CreateTime
+		}
+	}
+	if session.ID == "" {
+		session.ID = strings.TrimRight(base32.StdEncoding.EncodeToString(securecookie.GenerateRandomKey(32)), "=")
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(session.Values); err != nil {
+		return err
+	}
+	record := &mapper.SessionRecord{
+		ID:             session.ID,
+		UserID:         userId,
+		Data:           buf.Bytes(),
+		ClientIP:       requestIP(r),
+		UserAgent:      truncateString(r.UserAgent(), 512),
+		CreateTime:     createTime,
+		LastAccessTime: now,
+		ExpireTime:     s.expireTime(createTime, now),
+	}
+	if err := s.backend.Save(ctx, record); err != nil {
+		return err
+	}
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, s.codecs...)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, gsessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
+
+// expireTime 取自创建起的最长有效期与空闲超时中较早的时间
+func (s *SessionStore) expireTime(createTime, lastAccessTime time.Time) time.Time {
+	expire := createTime.Add(s.ttl)
+	if idle := lastAccessTime.Add(s.idleTimeout); idle.Before(expire) {
+		return idle
+	}
+	return expire
+}
+
+// sessionUserId 取出 session 中登录用户的 id，未登录时为 0
+func sessionUserId(session *gsessions.Session) int64 {
+	userId, _ := session.Values[constant.UserLoginState].(int64)
+	return userId
+}
+
+// clientIPKey request context 中客户端 IP 的键
+type clientIPKey struct{}
+
+// SessionClientIP 将 gin 按可信代理配置解析出的客户端 IP 写入 request context，供 SessionStore 记录 session 的客户端 IP；
+// sessions 中间件会持有注册时的请求，因此须注册在 sessions 中间件之前
+func SessionClientIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.WithValue(c.Request.Context(), clientIPKey{}, c.ClientIP())
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// requestIP 取出 SessionClientIP 写入的客户端 IP，未注册该中间件时使用连接的对端地址，不读取可伪造的转发请求头
+func requestIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok && ip != "" {
+		return truncateString(ip, 64)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return truncateString(r.RemoteAddr, 64)
+	}
+	return host
+}
+
+// truncateString 按字节截断超出列长度的字符串，并去掉被截断的不完整字符
+func truncateString(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return strings.ToValidUTF8(s[:max], "")
+}
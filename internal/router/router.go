@@ -3,28 +3,32 @@ package router
 import (
 	"aicode/config"
 	"aicode/docs"
+	"aicode/file"
 	"aicode/internal/controller"
-	"aicode/internal/model/entity"
+	"aicode/internal/mapper"
 	"aicode/internal/router/middleware"
-	"encoding/gob"
+	"aicode/internal/service"
 
 	"github.com/gin-contrib/sessions"
-	"github.com/gin-contrib/sessions/memstore"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func init() {
-	// 注册 gob 类型，供 session memstore 序列化/反序列化使用
-	gob.Register(&entity.User{})
-}
-
 type HttpRouter struct {
-	healthController *controller.HealthController
-	userController   *controller.UserController
-	aiController     *controller.AIController
-	aiCodeController *controller.AICodeController
+	healthController           *controller.HealthController
+	userController             *controller.UserController
+	aiController               *controller.AIController
+	aiCodeController           *controller.AICodeController
+	appController              *controller.AppController
+	chatHistoryController      *controller.ChatHistoryController
+	appVersionController       *controller.AppVersionController
+	promptTemplateController   *controller.PromptTemplateController
+	promptExperimentController *controller.PromptExperimentController
+	codeGenJobController       *controller.CodeGenJobController
+	apiKeyController           *controller.ApiKeyController
+	userSessionController      *controller.UserSessionController
 }
 
 // SetupRouter 设置路由
@@ -33,32 +37,57 @@ func SetupRouter(
 	userController *controller.UserController,
 	aiController *controller.AIController,
 	aiCodeController *controller.AICodeController,
+	appController *controller.AppController,
+	chatHistoryController *controller.ChatHistoryController,
+	appVersionController *controller.AppVersionController,
+	promptTemplateController *controller.PromptTemplateController,
+	promptExperimentController *controller.PromptExperimentController,
+	codeGenJobController *controller.CodeGenJobController,
+	apiKeyController *controller.ApiKeyController,
+	userSessionController *controller.UserSessionController,
+	authTokenService service.AuthTokenService,
+	permissionService service.PermissionService,
+	rateLimitStore middleware.RateLimitStore,
+	sessionMapper mapper.SessionMapper,
 ) *gin.Engine {
 	cfg := config.GetConfig()
 	hr := &HttpRouter{
-		healthController: healthController,
-		userController:   userController,
-		aiController:     aiController,
-		aiCodeController: aiCodeController,
+		healthController:           healthController,
+		userController:             userController,
+		aiController:               aiController,
+		aiCodeController:           aiCodeController,
+		appController:              appController,
+		chatHistoryController:      chatHistoryController,
+		appVersionController:       appVersionController,
+		promptTemplateController:   promptTemplateController,
+		promptExperimentController: promptExperimentController,
+		codeGenJobController:       codeGenJobController,
+		apiKeyController:           apiKeyController,
+		userSessionController:      userSessionController,
 	}
-	// 创建 Gin 引擎
+	// 创建 Gin 引擎，只采信可信代理转发的客户端 IP
 	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logrus.Panicf("可信代理配置无效: %v", err)
+	}
 
-	// 初始化服务端 session（memstore：session 数据存于服务端内存，客户端只持有 cookie 中的 session ID）
+	// 初始化服务端 session（session 数据存于 server.session.store 配置的存储，客户端只持有 cookie 中签名后的 session ID）
 	sessionSecret := cfg.Server.SessionSecret
 	if sessionSecret == "" {
 		sessionSecret = "default_session_secret"
 	}
-	store := memstore.NewStore([]byte(sessionSecret))
+	store := middleware.NewSessionStore(sessionMapper, cfg.Server.Session.GetTTL(),
+		cfg.Server.Session.GetIdleTimeout(), []byte(sessionSecret))
 
 	// 添加全局中间件
 	r.Use(
+		middleware.SessionClientIP(), // 须在 sessions 之前，供 session 记录客户端 IP
 		sessions.Sessions("session_id", store),
-		middleware.CORSMiddleware(),      // CORS 跨域
-		middleware.TraceMiddleware(),     // 注入/生成 traceId
-		middleware.AccessLogMiddleware(), // 请求/响应完整日志
-		middleware.GlobalErrorHandler(),  // 全局异常处理+堆栈打印
-		middleware.AuthMiddleware(),      // 登录态校验（白名单接口除外）
+		middleware.CORSMiddleware(),                                    // CORS 跨域
+		middleware.TraceMiddleware(),                                   // 注入/生成 traceId
+		middleware.AccessLogMiddleware(),                               // 请求/响应完整日志
+		middleware.GlobalErrorHandler(),                                // 全局异常处理+堆栈打印
+		middleware.AuthMiddleware(authTokenService, permissionService), // 按路由声明的访问级别校验登录态与权限，支持 session 与 Bearer 令牌
 	)
 
 	// 获取配置
@@ -70,34 +99,82 @@ func SetupRouter(
 	// docs 接口文档（注册到根路由，不受 rootPath 影响）
 	if gin.IsDebugging() {
 		docs.SwaggerInfo.BasePath = rootPath
-		r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+		middleware.Public(&r.RouterGroup).GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 	}
 
 	// 创建主路由组
 	apiGroup := r.Group(rootPath)
 
+	// 各路由组按 config.yml server.rate_limit.groups 中的同名规则限流，未配置的路由组不限流
+	// 各路由默认需要登录，无需登录、仅限管理员或需要指定权限的路由在 RegisterRoutes 中通过 middleware.Public/Admin/Permission 注册
 	// 注册健康检查路由
 	{
-		health := apiGroup.Group("/health")
+		health := apiGroup.Group("/health", middleware.RateLimitMiddleware(rateLimitStore, "health"))
 		hr.healthController.RegisterRoutes(health)
 	}
 
 	// 注册用户路由
 	{
-		user := apiGroup.Group("/user")
+		user := apiGroup.Group("/user", middleware.RateLimitMiddleware(rateLimitStore, "user"))
 		hr.userController.RegisterRoutes(user)
 	}
+	// 用户登录 session 管理（需要 session:manage 权限）
+	{
+		userSession := apiGroup.Group("/user_session", middleware.RateLimitMiddleware(rateLimitStore, "user_session"))
+		hr.userSessionController.RegisterRoutes(userSession)
+	}
+	// 个人 API Key
+	{
+		apiKey := apiGroup.Group("/api_key", middleware.RateLimitMiddleware(rateLimitStore, "api_key"))
+		hr.apiKeyController.RegisterRoutes(apiKey)
+	}
 
 	// 注册ai交互路由
 	{
-		aiChat := apiGroup.Group("/ai_chat")
+		aiChat := apiGroup.Group("/ai_chat", middleware.RateLimitMiddleware(rateLimitStore, "ai_chat"))
 		hr.aiController.RegisterRoutes(aiChat)
 	}
 	// 代码生成
 	{
-		aiCode := apiGroup.Group("/ai_code")
+		aiCode := apiGroup.Group("/ai_code", middleware.RateLimitMiddleware(rateLimitStore, "ai_code"))
 		hr.aiCodeController.RegisterRoutes(aiCode)
 	}
+	// 异步代码生成任务
+	{
+		codeGenJob := apiGroup.Group("/code_gen_job", middleware.RateLimitMiddleware(rateLimitStore, "code_gen_job"))
+		hr.codeGenJobController.RegisterRoutes(codeGenJob)
+	}
+	// 应用管理
+	{
+		app := apiGroup.Group("/app", middleware.RateLimitMiddleware(rateLimitStore, "app"))
+		hr.appController.RegisterRoutes(app)
+	}
+	// 对话历史
+	{
+		chatHistory := apiGroup.Group("/chat_history", middleware.RateLimitMiddleware(rateLimitStore, "chat_history"))
+		hr.chatHistoryController.RegisterRoutes(chatHistory)
+	}
+	// 应用代码版本
+	{
+		appVersion := apiGroup.Group("/app_version", middleware.RateLimitMiddleware(rateLimitStore, "app_version"))
+		hr.appVersionController.RegisterRoutes(appVersion)
+	}
+	// 系统提示词模板（需要 prompt:manage 权限）
+	{
+		promptTemplate := apiGroup.Group("/prompt_template", middleware.RateLimitMiddleware(rateLimitStore, "prompt_template"))
+		hr.promptTemplateController.RegisterRoutes(promptTemplate)
+	}
+	// 系统提示词实验（需要 prompt:manage 权限）
+	{
+		promptExperiment := apiGroup.Group("/prompt_experiment", middleware.RateLimitMiddleware(rateLimitStore, "prompt_experiment"))
+		hr.promptExperimentController.RegisterRoutes(promptExperiment)
+	}
+	// 已部署应用的静态站点：{rootPath}/deploy/{deployKey}/，无需登录即可访问，页面在 CSP sandbox 中运行
+	{
+		deploy := apiGroup.Group("/deploy", middleware.RateLimitMiddleware(rateLimitStore, "deploy"),
+			middleware.DeploySandboxMiddleware())
+		middleware.Public(deploy).Static("/", file.DeployRootDir())
+	}
 
 	return r
 }
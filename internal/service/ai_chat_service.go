@@ -8,6 +8,10 @@ import (
 )
 
 type AIChatService interface {
-	ChatGenerate(ctx context.Context, params *vo.AIChatRequest) (string, error)
-	ChatStream(ctx context.Context, params *vo.AIChatRequest) (*schema.StreamReader[*schema.Message], error)
+	// ChatGenerate 非流式聊天，返回结果中包含实际响应的模型（发生降级时与请求的模型不同）
+	ChatGenerate(ctx context.Context, params *vo.AIChatRequest) (*vo.AIChatResponse, error)
+	// ChatStream 流式聊天，同时返回实际响应的模型名
+	ChatStream(ctx context.Context, params *vo.AIChatRequest) (*schema.StreamReader[*schema.Message], string, error)
+	// ListChatModels 列出已注册的聊天模型及其能力，并标注当前用户角色是否可用
+	ListChatModels(ctx context.Context) ([]vo.ChatModelVO, error)
 }
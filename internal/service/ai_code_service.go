@@ -7,7 +7,13 @@ import (
 )
 
 type AICodeService interface {
-	// CodeGenerateStream 启动流式代码生成，结果逐块写入 ch，stream 读取与文件写入均在内部 goroutine 中异步完成
-	CodeGenerateStream(ctx context.Context, params *vo.AICodeRequest, ch chan<- vo.CodeStreamResult) error
-	CodeGenerate(ctx context.Context, params *vo.AICodeRequest) (string, error)
+	// CodeGenerateStream 以与 HTTP 请求解耦的后台任务启动流式代码生成，返回任务id与实际响应的模型名；
+	// 生成结果缓存为带递增 id 的事件，通过 SubscribeCodeJob 读取
+	CodeGenerateStream(ctx context.Context, params *vo.AICodeRequest) (*vo.CodeJobVO, error)
+	// SubscribeCodeJob 订阅当前用户任务中 id 大于 lastEventId 的事件（0 表示从头读取）；
+	// 任务结束且事件推送完毕或 ctx 结束时关闭 channel，客户端断开不影响任务运行
+	SubscribeCodeJob(ctx context.Context, jobId string, lastEventId int64) (<-chan vo.CodeJobEvent, error)
+	// CancelCodeJob 取消当前用户运行中的任务，中止上游模型调用
+	CancelCodeJob(ctx context.Context, jobId string) (bool, error)
+	CodeGenerate(ctx context.Context, params *vo.AICodeRequest) (*vo.AICodeResponse, error)
 }
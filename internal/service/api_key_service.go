@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"aicode/internal/model/dto/apikey"
+	"aicode/internal/model/vo"
+)
+
+// ApiKeyService 个人 API Key 服务接口，只允许操作本人的 API Key
+type ApiKeyService interface {
+	// CreateApiKey 创建 API Key，返回的密钥明文只出现这一次
+	CreateApiKey(ctx context.Context, req *apikey.ApiKeyCreateRequest) (*vo.ApiKeyCreatedVO, error)
+
+	// ListApiKeys 按创建时间倒序查询当前用户未吊销的 API Key
+	ListApiKeys(ctx context.Context) ([]vo.ApiKeyVO, error)
+
+	// RevokeApiKey 吊销 API Key
+	RevokeApiKey(ctx context.Context, id int64) (bool, error)
+}
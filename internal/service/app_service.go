@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"aicode/internal/model/dto/app"
+	"aicode/internal/model/entity"
+	"aicode/internal/model/vo"
+)
+
+// AppService 应用服务接口
+// 所有方法均从 ctx 中读取 AuthMiddleware 写入的登录用户做归属校验
+type AppService interface {
+	// AddApp 创建应用，归属于当前登录用户
+	AddApp(ctx context.Context, req *app.AppAddRequest) (int64, error)
+
+	// UpdateApp 更新应用（仅本人或管理员）
+	UpdateApp(ctx context.Context, req *app.AppUpdateRequest) (bool, error)
+
+	// DeleteApp 删除应用（仅本人或管理员）
+	DeleteApp(ctx context.Context, id int64) (bool, error)
+
+	// GetAppVO 获取应用详情（仅本人或管理员）
+	GetAppVO(ctx context.Context, id int64) (*vo.AppVO, error)
+
+	// ListAppVOByPage 分页获取应用列表，普通用户只能查看自己的应用
+	ListAppVOByPage(ctx context.Context, req *app.AppQueryRequest) ([]vo.AppVO, int64, error)
+
+	// DeployApp 部署应用（仅本人），返回部署访问地址；重复部署沿用原部署标识
+	DeployApp(ctx context.Context, id int64) (string, error)
+
+	// DownloadApp 校验应用（仅本人）及版本后返回 zip 文件名与打包函数；version 为 0 时打包当前代码
+	// 打包函数在调用方写好响应头后执行，将 zip 写入传入的 io.Writer
+	DownloadApp(ctx context.Context, id int64, version int) (string, func(w io.Writer) error, error)
+
+	// GetOwnedApp 获取当前登录用户名下的应用，应用不存在或不属于当前用户时返回业务异常
+	GetOwnedApp(ctx context.Context, id int64) (*entity.App, error)
+}
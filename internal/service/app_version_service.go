@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"aicode/consts"
+	"aicode/internal/model/vo"
+)
+
+// AppVersionService 应用代码版本服务接口
+// 所有方法均从 ctx 中读取登录用户，且只允许操作本人名下的应用
+type AppVersionService interface {
+	// CreateVersion 为应用目录当前内容创建新的版本快照，返回新版本号
+	CreateVersion(ctx context.Context, appId int64, prompt, model string,
+		genType consts.CodeGenarateType) (int, error)
+
+	// ListVersions 按版本号倒序列出应用的全部版本
+	ListVersions(ctx context.Context, appId int64) ([]vo.AppVersionVO, error)
+
+	// DiffVersions 比较应用的两个版本，返回各变更文件的 unified diff
+	DiffVersions(ctx context.Context, appId int64, from, to int) (*vo.AppVersionDiffVO, error)
+
+	// Rollback 将应用目录恢复为指定版本，并记录为一个新版本，返回新版本号
+	Rollback(ctx context.Context, appId int64, version int) (int, error)
+}
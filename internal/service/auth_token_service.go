@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"aicode/internal/model/entity"
+	"aicode/internal/model/vo"
+)
+
+// AuthTokenService 令牌认证服务接口
+// access/refresh 令牌为 HS256 签名的 JWT，同一次登录签发的令牌共享一个令牌会话，吊销会话后其中的令牌全部失效
+type AuthTokenService interface {
+	// IssueTokens 为用户创建令牌会话并签发 access/refresh 令牌
+	IssueTokens(ctx context.Context, userId int64) (*vo.AuthTokenVO, error)
+
+	// RefreshTokens 使用 refresh 令牌换取新的令牌，旧的 refresh 令牌随即失效；
+	// 已轮换的 refresh 令牌被再次使用时视为泄露，吊销整个令牌会话
+	RefreshTokens(ctx context.Context, refreshToken string) (*vo.AuthTokenVO, error)
+
+	// RevokeToken 吊销 refresh 令牌所属的令牌会话
+	RevokeToken(ctx context.Context, refreshToken string) (bool, error)
+
+	// AuthenticateBearer 解析请求头中的 access 令牌或个人 API Key，返回登录用户；
+	// 凭证为 API Key 时同时返回其授权范围，为 access 令牌时 scopes 为 nil，表示不限制
+	AuthenticateBearer(ctx context.Context, token string) (user *entity.User, scopes []string, err error)
+
+	// AuthenticateSession 按 session 中保存的用户 id 加载登录用户，用户已删除时返回 NotLoginError
+	AuthenticateSession(ctx context.Context, userId int64) (*entity.User, error)
+}
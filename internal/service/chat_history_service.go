@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"aicode/consts"
+	"aicode/internal/model/dto/chathistory"
+	"aicode/internal/model/vo"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// ChatHistoryService 对话历史服务接口
+// appId > 0 时按应用归档（代码生成），否则按当前登录用户 + conversationId 归档（普通聊天）
+type ChatHistoryService interface {
+	// AddChatMessage 保存一条对话消息
+	AddChatMessage(ctx context.Context, appId int64, conversationId string,
+		role consts.ChatRole, message string) error
+
+	// LoadHistoryMessages 加载最近的 maxCount 条消息，按时间正序转换为模型消息
+	LoadHistoryMessages(ctx context.Context, appId int64, conversationId string,
+		maxCount int) ([]*schema.Message, error)
+
+	// ListByCursor 游标分页查询对话历史，按时间倒序返回
+	ListByCursor(ctx context.Context, req *chathistory.ChatHistoryQueryRequest) (*vo.ChatHistoryCursorPageVO, error)
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"aicode/internal/model/vo"
+)
+
+// CodeGenJobService 异步代码生成任务服务接口
+// 任务持久化在数据库中，由各实例的调度器认领后按全局与单模型并发上限执行；用户方法只允许操作本人的任务
+type CodeGenJobService interface {
+	// Start 将执行实例已退出的任务标记为失败，并启动定期认领排队任务的调度器；服务启动时调用一次
+	Start()
+
+	// SubmitJob 校验后提交任务，返回任务id；排队已满时返回业务异常
+	SubmitJob(ctx context.Context, params *vo.AICodeRequest) (int64, error)
+
+	// GetJob 查询任务状态与结果
+	GetJob(ctx context.Context, id int64) (*vo.CodeGenJobVO, error)
+
+	// ListJobs 按提交时间倒序查询当前用户在应用下最近的任务
+	ListJobs(ctx context.Context, appId int64) ([]vo.CodeGenJobVO, error)
+
+	// CancelJob 取消排队中或执行中的任务
+	CancelJob(ctx context.Context, id int64) (bool, error)
+}
@@ -2,67 +2,162 @@ package impl
 
 import (
 	"aicode/ai/chatmodel"
+	"aicode/config"
 	"aicode/consts"
 	"aicode/internal/model/vo"
 	"aicode/internal/service"
 	"context"
+	"io"
+	"strings"
 
 	"github.com/cloudwego/eino/schema"
+	"github.com/sirupsen/logrus"
 )
 
 // AIChatServiceImpl ai聊天服务实现
 type AIChatServiceImpl struct {
+	chatHistoryService service.ChatHistoryService
+	tokenUsageService  service.TokenUsageService
 }
 
 // NewAIChatService 创建ai聊天服务实例
-func NewAIChatService() service.AIChatService {
-	return &AIChatServiceImpl{}
+func NewAIChatService(chatHistoryService service.ChatHistoryService,
+	tokenUsageService service.TokenUsageService) service.AIChatService {
+	return &AIChatServiceImpl{
+		chatHistoryService: chatHistoryService,
+		tokenUsageService:  tokenUsageService,
+	}
 }
 
 func (s *AIChatServiceImpl) ChatGenerate(ctx context.Context,
-	params *vo.AIChatRequest) (string, error) {
+	params *vo.AIChatRequest) (*vo.AIChatResponse, error) {
+	// 校验模型权限
+	if err := checkChatModelAccess(ctx, string(params.Model)); err != nil {
+		return nil, err
+	}
+	// 校验 token 配额
+	if err := s.tokenUsageService.CheckQuota(ctx); err != nil {
+		return nil, err
+	}
 	// 构建消息列表
-	messages := dealChatMessages(params.Question, params.History)
-
-	// 获取模型实例
-	chat, _ := chatmodel.GetChatModel(ctx, string(params.Model))
-	// 调用模型
-	message, _, err := chatmodel.AutoChat(ctx,
-		chat, messages, consts.ChatRespTypeGenerate)
+	messages, err := s.prepareChatMessages(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	// 调用模型（带重试与降级）
+	result, err := resilientChat(ctx, string(params.Model), messages, consts.ChatRespTypeGenerate)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return message.Content, nil
+	s.tokenUsageService.RecordUsage(ctx, 0, result.Model, consts.UsageBizTypeChat, messages, result.Message)
+	// 保存模型回答
+	if err := s.chatHistoryService.AddChatMessage(ctx, 0, params.ConversationId,
+		consts.ChatRoleAssistant, result.Message.Content); err != nil {
+		logrus.Errorf("保存对话历史失败: %v", err)
+	}
+	return &vo.AIChatResponse{
+		Question: params.Question,
+		Answer:   result.Message.Content,
+		Model:    result.Model,
+	}, nil
 }
 
 func (s *AIChatServiceImpl) ChatStream(ctx context.Context,
-	params *vo.AIChatRequest) (*schema.StreamReader[*schema.Message], error) {
+	params *vo.AIChatRequest) (*schema.StreamReader[*schema.Message], string, error) {
+	// 校验模型权限
+	if err := checkChatModelAccess(ctx, string(params.Model)); err != nil {
+		return nil, "", err
+	}
+	// 校验 token 配额
+	if err := s.tokenUsageService.CheckQuota(ctx); err != nil {
+		return nil, "", err
+	}
 	// 构建消息列表
-	messages := dealChatMessages(params.Question, params.History)
+	messages, err := s.prepareChatMessages(ctx, params)
+	if err != nil {
+		return nil, "", err
+	}
+	// 调用模型（带重试与降级）
+	result, err := resilientChat(ctx, string(params.Model), messages, consts.ChatRespTypeStream)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// 复制 stream，异步收集完整回答后写入对话历史并记录 token 用量
+	streams := result.Stream.Copy(3)
+	go s.saveStreamAnswer(context.WithoutCancel(ctx), params.ConversationId, streams[1])
+	go s.tokenUsageService.RecordStreamUsage(context.WithoutCancel(ctx), 0,
+		result.Model, consts.UsageBizTypeChat, messages, streams[2])
+	return streams[0], result.Model, nil
+}
+
+func (s *AIChatServiceImpl) ListChatModels(ctx context.Context) ([]vo.ChatModelVO, error) {
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metas := chatmodel.ListChatModelMetas()
+	models := make([]vo.ChatModelVO, 0, len(metas))
+	for _, meta := range metas {
+		models = append(models, vo.ChatModelVO{
+			Name:            meta.Name,
+			DisplayName:     meta.DisplayName,
+			Provider:        string(meta.Provider),
+			RespTypes:       meta.RespTypes,
+			ContextWindow:   meta.ContextWindow,
+			SupportToolCall: meta.SupportToolCall,
+			SupportVision:   meta.SupportVision,
+			Allowed:         meta.AllowRole(loginUser.UserRole),
+		})
+	}
+	return models, nil
+}
 
-	// 获取模型实例
-	chat, _ := chatmodel.GetChatModel(ctx, string(params.Model))
-	// 调用模型
-	_, stream, err := chatmodel.AutoChat(ctx, chat, messages, consts.ChatRespTypeStream)
+// prepareChatMessages 加载服务端对话历史并保存本轮用户提问，返回发送给模型的消息列表
+func (s *AIChatServiceImpl) prepareChatMessages(ctx context.Context,
+	params *vo.AIChatRequest) ([]*schema.Message, error) {
+	maxTurns := config.GetConfig().AI.GetHistoryMaxTurns()
+	history, err := s.chatHistoryService.LoadHistoryMessages(ctx, 0,
+		params.ConversationId, maxTurns*2)
 	if err != nil {
 		return nil, err
 	}
-	return stream, nil
+	if err := s.chatHistoryService.AddChatMessage(ctx, 0, params.ConversationId,
+		consts.ChatRoleUser, params.Question); err != nil {
+		return nil, err
+	}
+	return dealChatMessages(params.Question, history), nil
 }
 
-func dealChatMessages(question string, history []vo.AIChatMessage) []*schema.Message {
-	messages := make([]*schema.Message, 0)
-	// 添加历史对话
-	for _, msg := range history {
-		switch msg.Role {
-		case consts.ChatRoleUser:
-			messages = append(messages,
-				schema.UserMessage(msg.Content))
-		case consts.ChatRoleAssistant:
-			messages = append(messages,
-				schema.AssistantMessage(msg.Content, []schema.ToolCall{}))
+// saveStreamAnswer 读取完整的流式回答并写入对话历史，流异常中断时不保存
+func (s *AIChatServiceImpl) saveStreamAnswer(ctx context.Context,
+	conversationId string, stream *schema.StreamReader[*schema.Message]) {
+	defer stream.Close()
+
+	var buf strings.Builder
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				logrus.Errorf("读取流式回答失败，跳过保存对话历史: %v", err)
+				return
+			}
+			break
 		}
+		if msg != nil {
+			buf.WriteString(msg.Content)
+		}
+	}
+	if err := s.chatHistoryService.AddChatMessage(ctx, 0, conversationId,
+		consts.ChatRoleAssistant, buf.String()); err != nil {
+		logrus.Errorf("保存对话历史失败: %v", err)
 	}
+}
+
+func dealChatMessages(question string, history []*schema.Message) []*schema.Message {
+	messages := make([]*schema.Message, 0, len(history)+1)
+	// 添加服务端保存的历史对话
+	messages = append(messages, history...)
 	// 添加当前问题
 	messages = append(messages, schema.UserMessage(question))
 	return messages
@@ -0,0 +1,121 @@
+package impl
+
+import (
+	"context"
+	"strconv"
+
+	"aicode/ai/agent"
+	"aicode/consts"
+	"aicode/internal/model/vo"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/sirupsen/logrus"
+)
+
+// toolResultMaxRunes 推送给前端的工具结果最大字符数
+const toolResultMaxRunes = 500
+
+// agentGenerateStream 以工具调用模式异步生成代码：增量文本、工具调用与工具结果依次写入 channel
+func (s *AICodeServiceImpl) agentGenerateStream(ctx context.Context, params *vo.AICodeRequest,
+	messages []*schema.Message, ch chan<- vo.CodeStreamResult) error {
+	allow, err := allowLoginUserRole(ctx)
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer close(ch)
+		defer s.recoverStreamPanic(ctx, params, ch)
+
+		result, err := agent.Run(ctx, agent.Request{
+			Model:    string(params.Model),
+			AppId:    strconv.FormatInt(params.AppId, 10),
+			Messages: messages,
+			Stream:   true,
+			Allow:    allow,
+		}, s.agentCallbacks(ctx, params.AppId, ch))
+		if err != nil {
+			logrus.Errorf("工具调用模式生成代码失败, appId=%d: %v", params.AppId, err)
+			s.recordExperimentFailure(ctx, params)
+			ch <- vo.CodeStreamResult{Err: err}
+			return
+		}
+		s.saveAnswer(ctx, params.AppId, result.Content)
+		s.createVersion(ctx, params, result.Model)
+	}()
+	return nil
+}
+
+// agentGenerate 以工具调用模式同步生成代码，返回模型最终的文本回复
+func (s *AICodeServiceImpl) agentGenerate(ctx context.Context, params *vo.AICodeRequest,
+	messages []*schema.Message) (*vo.AICodeResponse, error) {
+	allow, err := allowLoginUserRole(ctx)
+	if err != nil {
+		s.recordExperimentFailure(ctx, params)
+		return nil, err
+	}
+	result, err := agent.Run(ctx, agent.Request{
+		Model:    string(params.Model),
+		AppId:    strconv.FormatInt(params.AppId, 10),
+		Messages: messages,
+		Allow:    allow,
+	}, s.agentCallbacks(ctx, params.AppId, nil))
+	if err != nil {
+		s.recordExperimentFailure(ctx, params)
+		return nil, err
+	}
+	s.saveAnswer(ctx, params.AppId, result.Content)
+	s.createVersion(ctx, params, result.Model)
+	return &vo.AICodeResponse{
+		Content: result.Content,
+		Model:   result.Model,
+	}, nil
+}
+
+// agentCallbacks 构造 agent 回调：每轮记录 token 用量；ch 非空时推送增量文本与工具事件
+func (s *AICodeServiceImpl) agentCallbacks(ctx context.Context, appId int64,
+	ch chan<- vo.CodeStreamResult) agent.Callbacks {
+	cb := agent.Callbacks{
+		OnStep: func(model string, prompt []*schema.Message, msg *schema.Message) {
+			s.tokenUsageService.RecordUsage(context.WithoutCancel(ctx), appId,
+				model, consts.UsageBizTypeCode, prompt, msg)
+		},
+	}
+	if ch == nil {
+		return cb
+	}
+	cb.OnDelta = func(content string) {
+		ch <- vo.CodeStreamResult{Content: content}
+	}
+	cb.OnToolCall = func(call schema.ToolCall) {
+		ch <- vo.CodeStreamResult{
+			Event: consts.CodeStreamEventToolCall,
+			Payload: vo.ToolCallEvent{
+				ID:        call.ID,
+				Name:      call.Function.Name,
+				Arguments: call.Function.Arguments,
+			},
+		}
+	}
+	cb.OnToolResult = func(call schema.ToolCall, result string, err error) {
+		event := vo.ToolResultEvent{
+			ID:      call.ID,
+			Name:    call.Function.Name,
+			Success: err == nil,
+			Result:  truncateRunes(result, toolResultMaxRunes),
+		}
+		if err != nil {
+			event.Error = err.Error()
+		}
+		ch <- vo.CodeStreamResult{Event: consts.CodeStreamEventToolResult, Payload: event}
+	}
+	return cb
+}
+
+// truncateRunes 按字符数截断字符串
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "..."
+}
@@ -0,0 +1,58 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	"aicode/consts"
+	"aicode/file"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// maxCodeContinuations 模型输出被截断时最多请求续写的次数
+const maxCodeContinuations = 2
+
+// continuationPrompt 请求模型从中断处续写的提示
+const continuationPrompt = "你上一次的输出在中途被截断了。请从中断的位置开始，直接继续输出剩余的内容：" +
+	"不要重复已经输出的内容，不要重新开始，不要添加任何解释或 Markdown 代码块标记。"
+
+// isTruncatedOutput 判断错误是否为模型输出被截断
+func isTruncatedOutput(err error) bool {
+	var truncatedErr *file.TruncatedOutputError
+	return errors.As(err, &truncatedErr)
+}
+
+// continuationMessages 在原消息列表后追加已输出的部分回答与续写请求
+func continuationMessages(messages []*schema.Message, partial string) []*schema.Message {
+	continued := make([]*schema.Message, 0, len(messages)+2)
+	continued = append(continued, messages...)
+	continued = append(continued, schema.AssistantMessage(partial, nil), schema.UserMessage(continuationPrompt))
+	return continued
+}
+
+// continueGenerate 请求模型续写被截断的输出，返回续写内容
+func (s *AICodeServiceImpl) continueGenerate(ctx context.Context, appId int64, model string,
+	messages []*schema.Message, partial string) (string, error) {
+	continuation := continuationMessages(messages, partial)
+	result, err := resilientChat(ctx, model, continuation, consts.ChatRespTypeGenerate)
+	if err != nil {
+		return "", err
+	}
+	s.tokenUsageService.RecordUsage(ctx, appId, result.Model, consts.UsageBizTypeCode, continuation, result.Message)
+	return result.Message.Content, nil
+}
+
+// continueStream 以流式请求模型续写被截断的输出
+func (s *AICodeServiceImpl) continueStream(ctx context.Context, appId int64, model string,
+	messages []*schema.Message, partial string) (*schema.StreamReader[*schema.Message], error) {
+	continuation := continuationMessages(messages, partial)
+	result, err := resilientChat(ctx, model, continuation, consts.ChatRespTypeStream)
+	if err != nil {
+		return nil, err
+	}
+	streams := result.Stream.Copy(2)
+	go s.tokenUsageService.RecordStreamUsage(context.WithoutCancel(ctx), appId,
+		result.Model, consts.UsageBizTypeCode, continuation, streams[1])
+	return streams[0], nil
+}
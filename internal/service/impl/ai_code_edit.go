@@ -0,0 +1,146 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"aicode/consts"
+	"aicode/file"
+	"aicode/internal/exception"
+	"aicode/internal/model/vo"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/sirupsen/logrus"
+)
+
+// editContextMaxBytes 增量修改模式下随提问发送给模型的代码总字节数上限
+const editContextMaxBytes = 200 * 1024
+
+// editFenceLangs 文件扩展名与代码块语言的对应关系
+var editFenceLangs = map[string]string{
+	".html": "html",
+	".css":  "css",
+	".js":   "javascript",
+	".json": "json",
+	".vue":  "vue",
+	".ts":   "typescript",
+	".md":   "markdown",
+}
+
+// buildEditQuestion 读取应用当前代码，与修改需求拼接为发送给模型的提问
+// 超过大小上限或无法按文本读取的文件只列出路径
+func buildEditQuestion(appId, question string) (string, error) {
+	entries, err := file.ListAppDir(appId, "")
+	if err != nil {
+		logrus.Errorf("读取应用目录失败, appId=%s: %v", appId, err)
+		return "", exception.NewBusinessErrorWithMessage(exception.SystemError, "读取应用代码失败")
+	}
+
+	var b strings.Builder
+	b.WriteString("应用当前的代码文件如下：\n\n")
+	total, count := 0, 0
+	omitted := make([]string, 0)
+	for _, entry := range entries {
+		if entry.IsDir {
+			continue
+		}
+		count++
+		if total+int(entry.Size) > editContextMaxBytes {
+			omitted = append(omitted, entry.Path)
+			continue
+		}
+		content, err := file.ReadAppFile(appId, entry.Path)
+		if err != nil {
+			omitted = append(omitted, entry.Path)
+			continue
+		}
+		total += len(content)
+		fmt.Fprintf(&b, "### %s\n```%s\n%s", entry.Path, editFenceLangs[path.Ext(entry.Path)], content)
+		if !strings.HasSuffix(content, "\n") {
+			b.WriteByte('\n')
+		}
+		b.WriteString("```\n\n")
+	}
+	if count == 0 {
+		return "", exception.NewBusinessErrorWithMessage(exception.ParamsError, "应用代码不存在，请先生成代码")
+	}
+	if len(omitted) > 0 {
+		fmt.Fprintf(&b, "以下文件过大未展示内容，请不要修改：%s\n\n", strings.Join(omitted, "、"))
+	}
+	b.WriteString("本次的修改需求：\n")
+	b.WriteString(question)
+	return b.String(), nil
+}
+
+// editGenerateStream 异步读取增量修改模式的 stream：说明文字与补丁原样推送，输出结束后整体应用补丁，
+// 补丁被截断时请求模型续写；应用成功后为每个被修改的文件推送 file_completed 事件
+func (s *AICodeServiceImpl) editGenerateStream(ctx context.Context, params *vo.AICodeRequest, model string,
+	messages []*schema.Message, stream *schema.StreamReader[*schema.Message], ch chan<- vo.CodeStreamResult) {
+	defer close(ch)
+	defer s.recoverStreamPanic(ctx, params, ch)
+
+	appId := strconv.FormatInt(params.AppId, 10)
+	var buf strings.Builder
+	var changed []string
+	var applyErr error
+	for attempt := 0; ; attempt++ {
+		if err := consumeTextStream(stream, &buf, ch); err != nil {
+			s.recordExperimentFailure(ctx, params)
+			ch <- vo.CodeStreamResult{Err: err}
+			return
+		}
+		if ctx.Err() != nil {
+			// 任务已取消，不应用不完整的补丁
+			s.recordExperimentFailure(ctx, params)
+			ch <- vo.CodeStreamResult{Err: ctx.Err()}
+			return
+		}
+		changed, applyErr = file.ApplyEditOutput(appId, buf.String())
+		if !isTruncatedOutput(applyErr) || attempt >= maxCodeContinuations {
+			break
+		}
+		logrus.Warnf("增量修改输出被截断，请求模型续写, appId=%d, 第 %d 次", params.AppId, attempt+1)
+		s.promptExperimentService.RecordRetry(ctx, params.ExperimentRecordID)
+		var err error
+		if stream, err = s.continueStream(ctx, params.AppId, model, messages, buf.String()); err != nil {
+			logrus.Errorf("请求模型续写失败: %v", err)
+			break
+		}
+	}
+
+	s.saveAnswer(ctx, params.AppId, buf.String())
+	if applyErr != nil {
+		logrus.Errorf("应用修改补丁失败, appId=%d: %v", params.AppId, applyErr)
+		s.recordExperimentFailure(ctx, params)
+		ch <- vo.CodeStreamResult{Err: applyErr}
+		return
+	}
+	for _, p := range changed {
+		ch <- vo.CodeStreamResult{Event: consts.CodeStreamEventFileCompleted,
+			Payload: vo.FileEvent{Path: p}}
+	}
+	s.createVersion(ctx, params, model)
+}
+
+// consumeTextStream 读取一段 stream 直至结束，内容累积到 buf 并作为增量文本推送
+func consumeTextStream(stream *schema.StreamReader[*schema.Message], buf *strings.Builder,
+	ch chan<- vo.CodeStreamResult) error {
+	defer stream.Close()
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg != nil && msg.Content != "" {
+			buf.WriteString(msg.Content)
+			ch <- vo.CodeStreamResult{Content: msg.Content}
+		}
+	}
+}
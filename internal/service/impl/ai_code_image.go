@@ -0,0 +1,118 @@
+package impl
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"aicode/ai/chatmodel"
+	"aicode/consts"
+	"aicode/file"
+	"aicode/internal/exception"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/sirupsen/logrus"
+)
+
+// codeImageExts 支持的图片格式与保存时使用的扩展名
+var codeImageExts = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// codeImage 解码后的请求图片
+type codeImage struct {
+	mimeType string
+	data     []byte
+}
+
+// decodeCodeImages 解码请求中的图片，每张可以是 data URL 或纯 base64；
+// 图片格式按内容识别，不信任 data URL 中声明的类型
+func decodeCodeImages(images []string) ([]codeImage, error) {
+	if len(images) > consts.CodeImageMaxCount {
+		return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError,
+			fmt.Sprintf("最多上传 %d 张图片", consts.CodeImageMaxCount))
+	}
+	decoded := make([]codeImage, 0, len(images))
+	for i, image := range images {
+		payload := strings.TrimSpace(image)
+		if strings.HasPrefix(payload, "data:") {
+			comma := strings.IndexByte(payload, ',')
+			if comma < 0 || !strings.HasSuffix(payload[:comma], ";base64") {
+				return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError,
+					fmt.Sprintf("第 %d 张图片不是 base64 编码的 data URL", i+1))
+			}
+			payload = payload[comma+1:]
+		}
+		if base64.StdEncoding.DecodedLen(len(payload)) > consts.CodeImageMaxBytes+3 {
+			return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError,
+				fmt.Sprintf("第 %d 张图片超过 %dMB", i+1, consts.CodeImageMaxBytes>>20))
+		}
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil || len(data) == 0 {
+			return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError,
+				fmt.Sprintf("第 %d 张图片 base64 解码失败", i+1))
+		}
+		if len(data) > consts.CodeImageMaxBytes {
+			return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError,
+				fmt.Sprintf("第 %d 张图片超过 %dMB", i+1, consts.CodeImageMaxBytes>>20))
+		}
+		mimeType := http.DetectContentType(data)
+		if _, ok := codeImageExts[mimeType]; !ok {
+			return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError,
+				fmt.Sprintf("第 %d 张图片格式不支持，仅支持 png、jpeg、gif、webp", i+1))
+		}
+		decoded = append(decoded, codeImage{mimeType: mimeType, data: data})
+	}
+	return decoded, nil
+}
+
+// checkVisionSupport 校验模型支持图片输入，须在 checkChatModelAccess 之后调用
+func checkVisionSupport(name string) error {
+	meta, _ := chatmodel.GetChatModelMeta(name)
+	if !meta.SupportVision {
+		return exception.NewBusinessErrorWithMessage(exception.ParamsError, "该模型不支持图片输入: "+name)
+	}
+	return nil
+}
+
+// saveCodeImages 将请求图片保存到应用目录的 .uploads 下，返回保存路径
+func saveCodeImages(appId int64, images []codeImage) ([]string, error) {
+	paths := make([]string, 0, len(images))
+	for _, image := range images {
+		p, err := file.SaveAppUpload(strconv.FormatInt(appId, 10), codeImageExts[image.mimeType], image.data)
+		if err != nil {
+			logrus.Errorf("保存上传图片失败, appId=%d: %v", appId, err)
+			return nil, exception.NewBusinessErrorWithMessage(exception.OperationError, "保存图片失败")
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+// codeQuestionMessage 构造本轮提问消息：没有图片时为纯文本消息，有图片时以多模态内容依次放入文本与图片
+func codeQuestionMessage(question string, images []codeImage) *schema.Message {
+	if len(images) == 0 {
+		return schema.UserMessage(question)
+	}
+	parts := make([]schema.MessageInputPart, 0, len(images)+1)
+	parts = append(parts, schema.MessageInputPart{Type: schema.ChatMessagePartTypeText, Text: question})
+	for _, image := range images {
+		data := base64.StdEncoding.EncodeToString(image.data)
+		parts = append(parts, schema.MessageInputPart{
+			Type: schema.ChatMessagePartTypeImageURL,
+			Image: &schema.MessageInputImage{
+				MessagePartCommon: schema.MessagePartCommon{
+					Base64Data: &data,
+					MIMEType:   image.mimeType,
+				},
+				Detail: schema.ImageURLDetailAuto,
+			},
+		})
+	}
+	return &schema.Message{Role: schema.User, UserInputMultiContent: parts}
+}
@@ -0,0 +1,274 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"aicode/consts"
+	"aicode/internal/exception"
+	"aicode/internal/model/vo"
+)
+
+const (
+	// codeJobRetention 任务结束后事件的保留时长，期间客户端仍可续传
+	codeJobRetention = 10 * time.Minute
+	// codeJobMaxEvents 单个任务最多缓存的事件数，超出时丢弃最早的事件
+	codeJobMaxEvents = 20000
+)
+
+// codeJob 与 HTTP 请求解耦的流式代码生成任务，缓存已产生的全部 SSE 事件
+type codeJob struct {
+	id     string
+	userId int64
+	appId  int64
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	status consts.CodeJobStatus
+	events []vo.CodeJobEvent
+	nextId int64
+	// notify 在追加事件或任务结束时关闭并替换，用于唤醒等待中的订阅者
+	notify     chan struct{}
+	finishedAt time.Time
+}
+
+// append 追加一个事件并唤醒订阅者
+func (j *codeJob) append(event string, data any) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.nextId++
+	j.events = append(j.events, vo.CodeJobEvent{ID: j.nextId, Event: event, Data: data})
+	if len(j.events) > codeJobMaxEvents {
+		// 整体复制以释放被丢弃事件占用的底层数组
+		j.events = append([]vo.CodeJobEvent(nil), j.events[len(j.events)-codeJobMaxEvents:]...)
+	}
+	j.wake()
+}
+
+// finish 将任务标记为结束，重复调用时保留第一次的状态
+func (j *codeJob) finish(status consts.CodeJobStatus) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.status != consts.CodeJobStatusRunning {
+		return
+	}
+	j.status = status
+	j.finishedAt = time.Now()
+	j.wake()
+}
+
+// wake 唤醒订阅者，调用方须持有锁
+func (j *codeJob) wake() {
+	close(j.notify)
+	j.notify = make(chan struct{})
+}
+
+// getStatus 返回任务状态
+func (j *codeJob) getStatus() consts.CodeJobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// eventsAfter 返回 id 大于 lastId 的事件；done 表示任务已结束、不会再有新事件；
+// 未结束时 wait 在有新事件时关闭。lastId 之后的事件已被丢弃时 ok 为 false
+func (j *codeJob) eventsAfter(lastId int64) (events []vo.CodeJobEvent, done bool,
+	wait <-chan struct{}, ok bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if len(j.events) > 0 && j.events[0].ID > lastId+1 {
+		return nil, true, nil, false
+	}
+	idx := sort.Search(len(j.events), func(i int) bool { return j.events[i].ID > lastId })
+	events = append([]vo.CodeJobEvent(nil), j.events[idx:]...)
+	return events, j.status != consts.CodeJobStatusRunning, j.notify, true
+}
+
+// expired 判断任务是否已结束且超过保留时长
+func (j *codeJob) expired(now time.Time) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status != consts.CodeJobStatusRunning && now.Sub(j.finishedAt) > codeJobRetention
+}
+
+// codeJobHub 进程内的任务表
+type codeJobHub struct {
+	mu   sync.Mutex
+	jobs map[string]*codeJob
+}
+
+// newCodeJobHub 创建任务表
+func newCodeJobHub() *codeJobHub {
+	return &codeJobHub{jobs: make(map[string]*codeJob)}
+}
+
+// create 创建并登记运行中的任务，同时清理已过期的任务
+func (h *codeJobHub) create(userId, appId int64, cancel context.CancelFunc) (*codeJob, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	job := &codeJob{
+		id:     hex.EncodeToString(b),
+		userId: userId,
+		appId:  appId,
+		cancel: cancel,
+		status: consts.CodeJobStatusRunning,
+		notify: make(chan struct{}),
+	}
+	now := time.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for id, existing := range h.jobs {
+		if existing.expired(now) {
+			delete(h.jobs, id)
+		}
+	}
+	h.jobs[job.id] = job
+	return job, nil
+}
+
+// get 查询未过期的任务
+func (h *codeJobHub) get(id string) (*codeJob, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	job, ok := h.jobs[id]
+	if !ok || job.expired(time.Now()) {
+		return nil, false
+	}
+	return job, true
+}
+
+// startCodeJob 以脱离 HTTP 请求的 context 启动流式代码生成，并在后台将结果转换为任务事件
+func (s *AICodeServiceImpl) startCodeJob(ctx context.Context, params *vo.AICodeRequest) (*vo.CodeJobVO, error) {
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 保留 ctx 中的登录用户等值，但不随请求结束而取消
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	ch := make(chan vo.CodeStreamResult, 32)
+	model, err := s.codeGenerateStream(jobCtx, params, ch)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	job, err := s.jobs.create(loginUser.ID, params.AppId, cancel)
+	if err != nil {
+		cancel()
+		// 生成已开始，丢弃后续结果
+		go func() {
+			for range ch {
+			}
+		}()
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "创建生成任务失败")
+	}
+	job.append("message", map[string]any{
+		"type":    "start",
+		"content": "",
+		"model":   model,
+		"jobId":   job.id,
+	})
+	go pumpCodeJob(jobCtx, job, ch)
+	return &vo.CodeJobVO{JobID: job.id, Model: model}, nil
+}
+
+// pumpCodeJob 持续读取生成结果直至 channel 关闭，转换为任务事件；客户端是否在线不影响读取
+func pumpCodeJob(jobCtx context.Context, job *codeJob, ch <-chan vo.CodeStreamResult) {
+	defer job.cancel()
+	failed := false
+	for result := range ch {
+		switch {
+		case result.Err != nil:
+			failed = true
+			if jobCtx.Err() == nil {
+				job.append("error", map[string]any{"error": result.Err.Error()})
+			}
+		case result.Event != "":
+			job.append(string(result.Event), result.Payload)
+		default:
+			job.append("message", map[string]any{"type": "data", "content": result.Content})
+		}
+	}
+	switch {
+	case failed && jobCtx.Err() != nil:
+		job.append(string(consts.CodeStreamEventCancelled), map[string]any{"jobId": job.id})
+		job.finish(consts.CodeJobStatusCancelled)
+	case failed:
+		job.finish(consts.CodeJobStatusFailed)
+	default:
+		job.append("message", map[string]any{"type": "end", "content": ""})
+		job.finish(consts.CodeJobStatusSucceeded)
+	}
+}
+
+// SubscribeCodeJob 订阅任务事件
+func (s *AICodeServiceImpl) SubscribeCodeJob(ctx context.Context, jobId string,
+	lastEventId int64) (<-chan vo.CodeJobEvent, error) {
+	job, err := s.getOwnedJob(ctx, jobId)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, _, ok := job.eventsAfter(lastEventId); !ok {
+		return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError, "部分事件已过期，无法续传")
+	}
+	out := make(chan vo.CodeJobEvent, 32)
+	go func() {
+		defer close(out)
+		last := lastEventId
+		for {
+			events, done, wait, ok := job.eventsAfter(last)
+			if !ok {
+				// 订阅者过慢，未读取的事件已被丢弃
+				return
+			}
+			for _, event := range events {
+				select {
+				case out <- event:
+					last = event.ID
+				case <-ctx.Done():
+					return
+				}
+			}
+			if done {
+				return
+			}
+			select {
+			case <-wait:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// CancelCodeJob 取消运行中的任务
+func (s *AICodeServiceImpl) CancelCodeJob(ctx context.Context, jobId string) (bool, error) {
+	job, err := s.getOwnedJob(ctx, jobId)
+	if err != nil {
+		return false, err
+	}
+	if job.getStatus() != consts.CodeJobStatusRunning {
+		return false, exception.NewBusinessErrorWithMessage(exception.ParamsError, "任务已结束")
+	}
+	job.cancel()
+	return true, nil
+}
+
+// getOwnedJob 查询当前登录用户的任务，管理员可访问全部任务
+func (s *AICodeServiceImpl) getOwnedJob(ctx context.Context, jobId string) (*codeJob, error) {
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	job, ok := s.jobs.get(jobId)
+	if !ok || (job.userId != loginUser.ID && !isAdmin(loginUser)) {
+		return nil, exception.NewBusinessErrorWithMessage(exception.NotFoundError, "任务不存在或已过期")
+	}
+	return job, nil
+}
@@ -1,16 +1,19 @@
 package impl
 
 import (
-	"aicode/ai/chatmodel"
 	"aicode/config"
 	"aicode/consts"
 	"aicode/file"
+	"aicode/internal/model/entity"
 	"aicode/internal/model/vo"
 	"aicode/internal/service"
+	applog "aicode/log"
 	"context"
+	"fmt"
 	"io"
-	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cloudwego/eino/schema"
 	"github.com/sirupsen/logrus"
@@ -18,128 +21,421 @@ import (
 
 // AICodeServiceImpl ai代码服务实现
 type AICodeServiceImpl struct {
+	appService              service.AppService
+	chatHistoryService      service.ChatHistoryService
+	tokenUsageService       service.TokenUsageService
+	appVersionService       service.AppVersionService
+	promptTemplateService   service.PromptTemplateService
+	promptExperimentService service.PromptExperimentService
+	// jobs 进行中与近期结束的流式生成任务
+	jobs *codeJobHub
 }
 
 // NewAICodeService 创建ai代码服务实例
-func NewAICodeService() service.AICodeService {
-	return &AICodeServiceImpl{}
+func NewAICodeService(appService service.AppService,
+	chatHistoryService service.ChatHistoryService,
+	tokenUsageService service.TokenUsageService,
+	appVersionService service.AppVersionService,
+	promptTemplateService service.PromptTemplateService,
+	promptExperimentService service.PromptExperimentService) service.AICodeService {
+	return &AICodeServiceImpl{
+		appService:              appService,
+		chatHistoryService:      chatHistoryService,
+		tokenUsageService:       tokenUsageService,
+		appVersionService:       appVersionService,
+		promptTemplateService:   promptTemplateService,
+		promptExperimentService: promptExperimentService,
+		jobs:                    newCodeJobHub(),
+	}
 }
 
+// CodeGenerateStream 以后台任务启动流式代码生成
 func (s *AICodeServiceImpl) CodeGenerateStream(ctx context.Context,
-	params *vo.AICodeRequest, ch chan<- vo.CodeStreamResult) error {
-	// 构建消息列表
-	messages := dealCodeMessages(ctx, params.GenType, params.Question, params.History)
+	params *vo.AICodeRequest) (*vo.CodeJobVO, error) {
+	return s.startCodeJob(ctx, params)
+}
+
+// codeGenerateStream 启动流式代码生成，结果逐块写入 ch，返回实际响应的模型名；
+// ch 由任务在后台持续读取，ctx 被取消时上游模型调用随之中止
+func (s *AICodeServiceImpl) codeGenerateStream(ctx context.Context,
+	params *vo.AICodeRequest, ch chan<- vo.CodeStreamResult) (string, error) {
+	// 校验应用存在且归属于当前登录用户
+	appEntity, err := s.appService.GetOwnedApp(ctx, params.AppId)
+	if err != nil {
+		return "", err
+	}
+	appId := strconv.FormatInt(params.AppId, 10)
 
-	// 获取模型实例
-	chat, _ := chatmodel.GetChatModel(ctx, string(params.Model))
-	// 调用模型，获取 stream
-	_, stream, err := chatmodel.AutoChat(ctx,
-		chat, messages, consts.ChatRespTypeStream)
+	// 校验模型权限
+	if err := checkChatModelAccess(ctx, string(params.Model)); err != nil {
+		return "", err
+	}
+	if params.GenType == consts.CodeGenarateTypeAgent {
+		if err := checkToolCallSupport(string(params.Model)); err != nil {
+			return "", err
+		}
+	}
+	// 校验附带的图片，仅支持图片输入的模型可用
+	images, err := decodeCodeImages(params.Images)
+	if err != nil {
+		return "", err
+	}
+	if len(images) > 0 {
+		if err := checkVisionSupport(string(params.Model)); err != nil {
+			return "", err
+		}
+	}
+	// 校验 token 配额
+	if err := s.tokenUsageService.CheckQuota(ctx); err != nil {
+		return "", err
+	}
+	// 构建消息列表
+	messages, err := s.prepareCodeMessages(ctx, appEntity, params, images)
 	if err != nil {
-		return err
+		return "", err
+	}
+	// 工具调用模式：模型通过工具直接读写应用目录
+	if params.GenType == consts.CodeGenarateTypeAgent {
+		if err := s.agentGenerateStream(ctx, params, messages, ch); err != nil {
+			s.recordExperimentFailure(ctx, params)
+			return "", err
+		}
+		return string(params.Model), nil
+	}
+	// 调用模型（带重试与降级），获取 stream
+	result, err := resilientChat(ctx, string(params.Model), messages, consts.ChatRespTypeStream)
+	if err != nil {
+		s.recordExperimentFailure(ctx, params)
+		return "", err
+	}
+	// 复制 stream，异步记录 token 用量
+	streams := result.Stream.Copy(2)
+	stream := streams[0]
+	go s.tokenUsageService.RecordStreamUsage(context.WithoutCancel(ctx), params.AppId,
+		result.Model, consts.UsageBizTypeCode, messages, streams[1])
+
+	// 增量修改模式：输出为补丁，结束后整体应用
+	if params.GenType == consts.CodeGenarateTypeEdit {
+		go s.editGenerateStream(ctx, params, result.Model, messages, stream, ch)
+		return result.Model, nil
 	}
 
-	// 异步读取 stream，逐块写入 channel；全量内容收集完毕后写入文件
+	// 异步读取 stream，增量解析出的文件一旦完整即写入磁盘，并以文件事件推送给客户端；
+	// 输出被截断时请求模型续写，续写内容接着送入同一个解析器
 	go func() {
 		defer close(ch)
-		defer recover()
+		defer s.recoverStreamPanic(ctx, params, ch)
 
 		var buf strings.Builder
-		for {
-			msg, err := stream.Recv()
-			if err != nil {
-				if err == io.EOF {
-					// stream 正常结束，将全量内容写入文件
-					if storeErr := file.StoreByGenType(ctx,
-						params.GenType, params.AppId, buf.String()); storeErr != nil {
-						logrus.Errorf("写入文件失败: %v", storeErr)
-						ch <- vo.CodeStreamResult{Err: storeErr}
-					}
-					return
-				}
-				// stream 读取出错
+		parser := file.NewStreamFileParser()
+		written := make([]string, 0, 8)
+		for attempt := 0; ; attempt++ {
+			var err error
+			if written, err = s.consumeCodeStream(appId, stream, parser, &buf, written, ch); err != nil {
+				// stream 读取出错，已完整输出的文件保留
+				s.recordExperimentFailure(ctx, params)
 				ch <- vo.CodeStreamResult{Err: err}
 				return
 			}
-
-			if msg != nil && msg.Content != "" {
-				buf.WriteString(msg.Content)
-				ch <- vo.CodeStreamResult{Content: msg.Content}
+			if ctx.Err() != nil {
+				// 任务已取消，不再续写与落盘
+				s.recordExperimentFailure(ctx, params)
+				ch <- vo.CodeStreamResult{Err: ctx.Err()}
+				return
+			}
+			if !parser.Truncated() || attempt >= maxCodeContinuations {
+				break
 			}
+			logrus.Warnf("代码生成输出被截断，请求模型续写, appId=%d, 第 %d 次", params.AppId, attempt+1)
+			s.promptExperimentService.RecordRetry(ctx, params.ExperimentRecordID)
+			if stream, err = s.continueStream(ctx, params.AppId, result.Model, messages, buf.String()); err != nil {
+				logrus.Errorf("请求模型续写失败: %v", err)
+				break
+			}
+		}
+
+		// 续写失败或续写次数用尽后输出仍被截断：保存模型回答，已完整输出的文件保留，
+		// 不清理旧文件、不创建版本
+		truncatedErr := parser.TruncatedError()
+		written = s.dispatchFileEvents(appId, parser.Close(), written, ch)
+		s.saveAnswer(ctx, params.AppId, buf.String())
+		if truncatedErr != nil {
+			logrus.Errorf("代码生成输出被截断且续写未完成, appId=%d: %v", params.AppId, truncatedErr)
+			s.recordExperimentFailure(ctx, params)
+			ch <- vo.CodeStreamResult{Err: truncatedErr}
+			return
 		}
+		// stream 正常结束，落盘剩余文件
+		if storeErr := s.finishStreamStore(ctx, params.GenType, appId,
+			buf.String(), written); storeErr != nil {
+			logrus.Errorf("写入文件失败: %v", storeErr)
+			s.recordExperimentFailure(ctx, params)
+			ch <- vo.CodeStreamResult{Err: storeErr}
+			return
+		}
+		s.createVersion(ctx, params, result.Model)
 	}()
 
-	return nil
+	return result.Model, nil
 }
 
 func (s *AICodeServiceImpl) CodeGenerate(ctx context.Context,
-	params *vo.AICodeRequest) (string, error) {
-	// 构建消息列表
-	messages := dealCodeMessages(ctx, params.GenType, params.Question, params.History)
+	params *vo.AICodeRequest) (*vo.AICodeResponse, error) {
+	// 校验应用存在且归属于当前登录用户
+	appEntity, err := s.appService.GetOwnedApp(ctx, params.AppId)
+	if err != nil {
+		return nil, err
+	}
+	appId := strconv.FormatInt(params.AppId, 10)
 
-	// 获取模型实例
-	chat, _ := chatmodel.GetChatModel(ctx, string(params.Model))
-	// 调用模型
-	message, _, err := chatmodel.AutoChat(ctx,
-		chat, messages, consts.ChatRespTypeGenerate)
+	// 校验模型权限
+	if err := checkChatModelAccess(ctx, string(params.Model)); err != nil {
+		return nil, err
+	}
+	if params.GenType == consts.CodeGenarateTypeAgent {
+		if err := checkToolCallSupport(string(params.Model)); err != nil {
+			return nil, err
+		}
+	}
+	// 校验附带的图片，仅支持图片输入的模型可用
+	images, err := decodeCodeImages(params.Images)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if len(images) > 0 {
+		if err := checkVisionSupport(string(params.Model)); err != nil {
+			return nil, err
+		}
+	}
+	// 校验 token 配额
+	if err := s.tokenUsageService.CheckQuota(ctx); err != nil {
+		return nil, err
 	}
-	// 文件存储
-	err = file.StoreByGenType(ctx,
-		params.GenType, params.AppId, message.Content)
+	// 构建消息列表
+	messages, err := s.prepareCodeMessages(ctx, appEntity, params, images)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	// 工具调用模式：模型通过工具直接读写应用目录
+	if params.GenType == consts.CodeGenarateTypeAgent {
+		return s.agentGenerate(ctx, params, messages)
 	}
-	return message.Content, nil
-}
-
-func dealCodeMessages(ctx context.Context,
-	genType consts.CodeGenarateType, question string,
-	history []vo.AICodeMessage) []*schema.Message {
-	messages := make([]*schema.Message, 0)
-	// 添加历史对话
-	if len(history) > 0 {
-		for _, msg := range history {
-			switch msg.Role {
-			case consts.ChatRoleSystem:
-				messages = append(messages,
-					schema.SystemMessage(msg.Content))
-			case consts.ChatRoleUser:
-				messages = append(messages,
-					schema.UserMessage(msg.Content))
-			case consts.ChatRoleAssistant:
-				messages = append(messages,
-					schema.AssistantMessage(msg.Content, []schema.ToolCall{}))
+	// 调用模型（带重试与降级）
+	result, err := resilientChat(ctx, string(params.Model), messages, consts.ChatRespTypeGenerate)
+	if err != nil {
+		s.recordExperimentFailure(ctx, params)
+		return nil, err
+	}
+	s.tokenUsageService.RecordUsage(ctx, params.AppId, result.Model, consts.UsageBizTypeCode, messages, result.Message)
+	content := result.Message.Content
+	// 文件存储，输出被截断时请求模型续写后重新解析
+	err = file.StoreByGenType(ctx, params.GenType, appId, content)
+	for attempt := 0; attempt < maxCodeContinuations && isTruncatedOutput(err); attempt++ {
+		logrus.Warnf("代码生成输出被截断，请求模型续写, appId=%d, 第 %d 次: %v", params.AppId, attempt+1, err)
+		s.promptExperimentService.RecordRetry(ctx, params.ExperimentRecordID)
+		continued, contErr := s.continueGenerate(ctx, params.AppId, result.Model, messages, content)
+		if contErr != nil {
+			logrus.Errorf("请求模型续写失败: %v", contErr)
+			break
+		}
+		content += continued
+		err = file.StoreByGenType(ctx, params.GenType, appId, content)
+	}
+	s.saveAnswer(ctx, params.AppId, content)
+	if err != nil {
+		s.recordExperimentFailure(ctx, params)
+		return nil, err
+	}
+	s.createVersion(ctx, params, result.Model)
+	return &vo.AICodeResponse{
+		Content: content,
+		Model:   result.Model,
+	}, nil
+}
+
+// consumeCodeStream 读取一段 stream 直至结束，内容送入解析器并推送解析事件，返回已写入的文件列表
+func (s *AICodeServiceImpl) consumeCodeStream(appId string, stream *schema.StreamReader[*schema.Message],
+	parser *file.StreamFileParser, buf *strings.Builder, written []string,
+	ch chan<- vo.CodeStreamResult) ([]string, error) {
+	defer stream.Close()
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+		if msg != nil && msg.Content != "" {
+			buf.WriteString(msg.Content)
+			written = s.dispatchFileEvents(appId, parser.Feed(msg.Content), written, ch)
+		}
+	}
+}
+
+// dispatchFileEvents 将增量解析事件推送给客户端，文件完整时写入应用目录，返回已写入的文件列表
+func (s *AICodeServiceImpl) dispatchFileEvents(appId string, events []file.StreamEvent,
+	written []string, ch chan<- vo.CodeStreamResult) []string {
+	for _, event := range events {
+		switch event.Type {
+		case file.StreamEventText:
+			ch <- vo.CodeStreamResult{Content: event.Content}
+		case file.StreamEventFileStarted:
+			ch <- vo.CodeStreamResult{Event: consts.CodeStreamEventFileStarted,
+				Payload: vo.FileEvent{Path: event.Path}}
+		case file.StreamEventFileChunk:
+			ch <- vo.CodeStreamResult{Event: consts.CodeStreamEventFileChunk,
+				Payload: vo.FileEvent{Path: event.Path, Content: event.Content}}
+		case file.StreamEventFileCompleted:
+			if err := file.WriteAppFile(appId, event.Path, event.Content); err != nil {
+				logrus.Warnf("写入生成文件失败，已跳过: %v", err)
+				continue
 			}
+			written = append(written, event.Path)
+			ch <- vo.CodeStreamResult{Event: consts.CodeStreamEventFileCompleted,
+				Payload: vo.FileEvent{Path: event.Path}}
 		}
-	} else {
-		// 添加默认系统提示词
-		systemPrompt := getSystemPrompt(genType)
-		messages = append(messages, schema.SystemMessage(systemPrompt))
 	}
+	return written
+}
+
+// finishStreamStore 流式输出结束后的收尾：未解析出任何文件时按生成类型整体解析存储；
+// 工程模式下清理上次生成但本次未输出的文件
+func (s *AICodeServiceImpl) finishStreamStore(ctx context.Context, genType consts.CodeGenarateType,
+	appId, content string, written []string) error {
+	if len(written) == 0 {
+		return file.StoreByGenType(ctx, genType, appId, content)
+	}
+	if genType == consts.CodeGenarateTypeVueProject {
+		return file.RemoveStaleFiles(appId, written)
+	}
+	return nil
+}
+
+// createVersion 代码成功落盘后为应用目录创建版本快照，并记录提示词实验的成功结果；
+// 创建版本失败时记录实验失败结果，不影响生成结果
+func (s *AICodeServiceImpl) createVersion(ctx context.Context, params *vo.AICodeRequest, model string) {
+	version, err := s.appVersionService.CreateVersion(ctx, params.AppId, params.Question, model, params.GenType)
+	if err != nil {
+		// 没有版本的记录无法被标记为回滚，计为失败以免影响实验统计
+		logrus.Errorf("创建应用版本失败, appId=%d: %v", params.AppId, err)
+		s.recordExperimentFailure(ctx, params)
+		return
+	}
+	logrus.Infof("创建应用版本成功, appId=%d, version=%d", params.AppId, version)
+	s.promptExperimentService.RecordOutcome(ctx, params.ExperimentRecordID, true, version)
+}
+
+// recoverStreamPanic 恢复异步生成 goroutine 中的 panic，记录日志与实验失败结果并向 channel 推送错误；
+// 须在 close(ch) 之后直接 defer 调用
+func (s *AICodeServiceImpl) recoverStreamPanic(ctx context.Context, params *vo.AICodeRequest,
+	ch chan<- vo.CodeStreamResult) {
+	if r := recover(); r != nil {
+		logrus.WithField("stack", applog.WithStack(r)).Errorf("生成代码异常, appId=%d: %v", params.AppId, r)
+		s.recordExperimentFailure(ctx, params)
+		ch <- vo.CodeStreamResult{Err: fmt.Errorf("生成代码异常: %v", r)}
+	}
+}
+
+// recordExperimentFailure 记录提示词实验的失败结果，未参与实验时不做任何处理
+func (s *AICodeServiceImpl) recordExperimentFailure(ctx context.Context, params *vo.AICodeRequest) {
+	s.promptExperimentService.RecordOutcome(ctx, params.ExperimentRecordID, false, 0)
+}
+
+// prepareCodeMessages 加载应用的对话历史并保存本轮用户提问，返回发送给模型的消息列表
+func (s *AICodeServiceImpl) prepareCodeMessages(ctx context.Context, appEntity *entity.App,
+	params *vo.AICodeRequest, images []codeImage) ([]*schema.Message, error) {
+	systemPrompt, err := s.renderSystemPrompt(ctx, appEntity, params)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := s.buildCodeMessages(ctx, systemPrompt, params, images)
+	if err != nil {
+		s.recordExperimentFailure(ctx, params)
+		return nil, err
+	}
+	return messages, nil
+}
+
+// buildCodeMessages 以渲染好的系统提示词构建消息列表，附带的图片保存到应用目录后随本轮提问发送
+func (s *AICodeServiceImpl) buildCodeMessages(ctx context.Context, systemPrompt string,
+	params *vo.AICodeRequest, images []codeImage) ([]*schema.Message, error) {
+	// 增量修改模式：将应用当前代码随提问发送给模型，历史中只保存用户原始提问
+	question := params.Question
+	if params.GenType == consts.CodeGenarateTypeEdit {
+		var err error
+		if question, err = buildEditQuestion(strconv.FormatInt(params.AppId, 10), params.Question); err != nil {
+			return nil, err
+		}
+	}
+	maxTurns := config.GetConfig().AI.GetHistoryMaxTurns()
+	history, err := s.chatHistoryService.LoadHistoryMessages(ctx, params.AppId, "", maxTurns*2)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) > 0 {
+		paths, err := saveCodeImages(params.AppId, images)
+		if err != nil {
+			return nil, err
+		}
+		logrus.Infof("已保存代码生成请求的图片, appId=%d, files=%v", params.AppId, paths)
+	}
+	if err := s.chatHistoryService.AddChatMessage(ctx, params.AppId, "",
+		consts.ChatRoleUser, params.Question); err != nil {
+		return nil, err
+	}
+	return dealCodeMessages(systemPrompt, codeQuestionMessage(question, images), history), nil
+}
+
+// saveAnswer 保存模型回答到应用的对话历史，失败仅记录日志不影响生成结果
+func (s *AICodeServiceImpl) saveAnswer(ctx context.Context, appId int64, content string) {
+	if err := s.chatHistoryService.AddChatMessage(ctx, appId, "",
+		consts.ChatRoleAssistant, content); err != nil {
+		logrus.Errorf("保存对话历史失败: %v", err)
+	}
+}
+
+// dealCodeMessages 按 系统提示词、历史对话、当前问题 的顺序构建消息列表
+func dealCodeMessages(systemPrompt string, question *schema.Message, history []*schema.Message) []*schema.Message {
+	messages := make([]*schema.Message, 0, len(history)+2)
+	// 添加系统提示词
+	messages = append(messages, schema.SystemMessage(systemPrompt))
+	// 添加服务端保存的历史对话
+	messages = append(messages, history...)
 
 	// 添加当前问题
-	messages = append(messages, schema.UserMessage(question))
+	messages = append(messages, question)
 	return messages
 }
 
-func getSystemPrompt(genType consts.CodeGenarateType) string {
-	dirPath := ""
-	cfg := config.GetConfig()
-	switch genType {
-	case consts.CodeGenarateTypeSingle:
-		dirPath = cfg.AI.SystemPromptDir.SingalGenerate
-	case consts.CodeGenarateTypeMulti:
-		dirPath = cfg.AI.SystemPromptDir.MultiGenerate
-	default:
-		return ""
+// renderSystemPrompt 使用与生成类型同名的提示词模板渲染系统提示词，模板缺失时返回错误；
+// 模板有进行中的实验时使用分配到的版本，并将实验记录id写入 params
+func (s *AICodeServiceImpl) renderSystemPrompt(ctx context.Context, appEntity *entity.App,
+	params *vo.AICodeRequest) (string, error) {
+	vars := &vo.PromptVariables{
+		GenType: string(params.GenType),
+		Model:   string(params.Model),
+		AppId:   appEntity.ID,
+		AppName: appEntity.AppName,
+		Date:    time.Now().Format(time.DateOnly),
+	}
+	if loginUser, err := getLoginUserFromCtx(ctx); err == nil {
+		vars.UserName = loginUser.UserName
+	}
+	name := string(params.GenType)
+	assignment, err := s.promptExperimentService.Assign(ctx, name, params.AppId, string(params.Model))
+	if err != nil {
+		// 实验分组失败不影响生成，使用生效版本
+		logrus.Errorf("分配提示词实验分组失败, name=%s: %v", name, err)
+	}
+	if assignment == nil {
+		return s.promptTemplateService.Render(ctx, name, vars)
 	}
-	bytes, err := os.ReadFile(dirPath)
+	params.ExperimentRecordID = assignment.RecordID
+	prompt, err := s.promptTemplateService.RenderVersion(ctx, name, assignment.Version, vars)
 	if err != nil {
-		logrus.Errorf("读取系统提示词文件失败: %s", err.Error())
-		return ""
+		s.recordExperimentFailure(ctx, params)
+		return "", err
 	}
-	content := string(bytes)
-	return content
+	return prompt, nil
 }
@@ -0,0 +1,163 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"aicode/constant"
+	"aicode/internal/exception"
+	"aicode/internal/mapper"
+	"aicode/internal/model/dto/apikey"
+	"aicode/internal/model/entity"
+	"aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"gorm.io/gorm"
+)
+
+// ApiKeyServiceImpl 个人 API Key 服务实现
+type ApiKeyServiceImpl struct {
+	apiKeyMapper *mapper.ApiKeyMapper
+}
+
+// NewApiKeyService 创建个人 API Key 服务实例
+func NewApiKeyService(apiKeyMapper *mapper.ApiKeyMapper) service.ApiKeyService {
+	return &ApiKeyServiceImpl{
+		apiKeyMapper: apiKeyMapper,
+	}
+}
+
+// CreateApiKey 创建 API Key
+func (s *ApiKeyServiceImpl) CreateApiKey(ctx context.Context, req *apikey.ApiKeyCreateRequest) (*vo.ApiKeyCreatedVO, error) {
+	if req == nil || strings.TrimSpace(req.Name) == "" {
+		return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError, "名称不能为空")
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scopes, err := normalizeApiKeyScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.apiKeyMapper.CountByUserId(loginUser.ID)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询 API Key 失败")
+	}
+	if count >= constant.ApiKeyMaxPerUser {
+		return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError,
+			fmt.Sprintf("最多只能持有 %d 个 API Key，请先吊销不再使用的", constant.ApiKeyMaxPerUser))
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "生成 API Key 失败")
+	}
+	key := constant.ApiKeyPrefix + hex.EncodeToString(b)
+	apiKey := &entity.ApiKey{
+		UserID:    loginUser.ID,
+		Name:      strings.TrimSpace(req.Name),
+		KeyPrefix: key[:len(constant.ApiKeyPrefix)+8],
+		KeyHash:   hashApiKey(key),
+		Scopes:    scopes,
+	}
+	if req.ExpireDays > 0 {
+		expireTime := time.Now().AddDate(0, 0, req.ExpireDays)
+		apiKey.ExpireTime = &expireTime
+	}
+	if err := s.apiKeyMapper.Save(apiKey); err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.OperationError, "创建 API Key 失败，数据库错误")
+	}
+	return &vo.ApiKeyCreatedVO{ApiKeyVO: getApiKeyVO(apiKey), Key: key}, nil
+}
+
+// ListApiKeys 按创建时间倒序查询当前用户未吊销的 API Key
+func (s *ApiKeyServiceImpl) ListApiKeys(ctx context.Context) ([]vo.ApiKeyVO, error) {
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	apiKeys, err := s.apiKeyMapper.ListByUserId(loginUser.ID)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询 API Key 失败")
+	}
+	apiKeyVOList := make([]vo.ApiKeyVO, 0, len(apiKeys))
+	for i := range apiKeys {
+		apiKeyVOList = append(apiKeyVOList, getApiKeyVO(&apiKeys[i]))
+	}
+	return apiKeyVOList, nil
+}
+
+// RevokeApiKey 吊销 API Key
+func (s *ApiKeyServiceImpl) RevokeApiKey(ctx context.Context, id int64) (bool, error) {
+	if id <= 0 {
+		return false, exception.NewBusinessErrorFromCode(exception.ParamsError)
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return false, err
+	}
+	apiKey, err := s.apiKeyMapper.GetById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, exception.NewBusinessErrorWithMessage(exception.NotFoundError, "API Key 不存在")
+		}
+		return false, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询 API Key 失败")
+	}
+	if apiKey.UserID != loginUser.ID {
+		return false, exception.NewBusinessErrorWithMessage(exception.NotFoundError, "API Key 不存在")
+	}
+	if apiKey.Revoked == 1 {
+		return false, exception.NewBusinessErrorWithMessage(exception.ParamsError, "API Key 已吊销")
+	}
+	if err := s.apiKeyMapper.Revoke(id); err != nil {
+		return false, exception.NewBusinessErrorWithMessage(exception.OperationError, "吊销 API Key 失败，数据库错误")
+	}
+	return true, nil
+}
+
+// normalizeApiKeyScopes 校验并去重授权范围：* 或可授权的路由组名，路由组名可加 :read 后缀
+func normalizeApiKeyScopes(scopes []string) ([]string, error) {
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		group := strings.TrimSuffix(scope, constant.ApiKeyScopeReadSuffix)
+		if scope != constant.ApiKeyScopeAll && !slices.Contains(constant.ApiKeyScopeGroups, group) {
+			return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError,
+				fmt.Sprintf("不支持的授权范围: %s", scope))
+		}
+		if !slices.Contains(result, scope) {
+			result = append(result, scope)
+		}
+	}
+	if len(result) == 0 {
+		return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError, "授权范围不能为空")
+	}
+	return result, nil
+}
+
+// hashApiKey 计算 API Key 的 SHA-256 哈希，密钥为 256 位随机数，无需加盐与慢哈希
+func hashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// getApiKeyVO 实体转换为 API Key 信息
+func getApiKeyVO(apiKey *entity.ApiKey) vo.ApiKeyVO {
+	return vo.ApiKeyVO{
+		ID:           apiKey.ID,
+		Name:         apiKey.Name,
+		KeyPrefix:    apiKey.KeyPrefix,
+		Scopes:       apiKey.Scopes,
+		ExpireTime:   apiKey.ExpireTime,
+		LastUsedTime: apiKey.LastUsedTime,
+		CreateTime:   apiKey.CreateTime,
+	}
+}
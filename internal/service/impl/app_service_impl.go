@@ -0,0 +1,414 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"aicode/config"
+	"aicode/consts"
+	"aicode/file"
+	"aicode/internal/exception"
+	"aicode/internal/mapper"
+	"aicode/internal/model/dto/app"
+	"aicode/internal/model/entity"
+	"aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// appNameMaxRunes 未指定应用名称时，从初始化 prompt 截取的最大字符数
+const appNameMaxRunes = 12
+
+// deployKeyLength 部署标识长度
+const deployKeyLength = 6
+
+// deployKeyAlphabet 部署标识字符集
+const deployKeyAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// appSortFields 允许排序的字段，避免排序字段直接拼接进 SQL
+var appSortFields = map[string]string{
+	"id":         "id",
+	"appName":    "app_name",
+	"priority":   "priority",
+	"createTime": "create_time",
+	"updateTime": "update_time",
+}
+
+// AppServiceImpl 应用服务实现
+type AppServiceImpl struct {
+	appMapper         *mapper.AppMapper
+	chatHistoryMapper *mapper.ChatHistoryMapper
+	appVersionMapper  *mapper.AppVersionMapper
+	userService       service.UserService
+}
+
+// NewAppService 创建应用服务实例
+func NewAppService(appMapper *mapper.AppMapper, chatHistoryMapper *mapper.ChatHistoryMapper,
+	appVersionMapper *mapper.AppVersionMapper, userService service.UserService) service.AppService {
+	return &AppServiceImpl{
+		appMapper:         appMapper,
+		chatHistoryMapper: chatHistoryMapper,
+		appVersionMapper:  appVersionMapper,
+		userService:       userService,
+	}
+}
+
+// AddApp 创建应用
+func (s *AppServiceImpl) AddApp(ctx context.Context, req *app.AppAddRequest) (int64, error) {
+	if req == nil || strings.TrimSpace(req.InitPrompt) == "" {
+		return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "初始化 prompt 不能为空")
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	codeGenType := req.CodeGenType
+	if codeGenType == "" {
+		codeGenType = string(consts.CodeGenarateTypeMulti)
+	}
+	if !consts.IsValidCodeGenarateType(consts.CodeGenarateType(codeGenType)) {
+		return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "不支持的代码生成类型")
+	}
+
+	appName := strings.TrimSpace(req.AppName)
+	if appName == "" {
+		runes := []rune(strings.TrimSpace(req.InitPrompt))
+		if len(runes) > appNameMaxRunes {
+			runes = runes[:appNameMaxRunes]
+		}
+		appName = string(runes)
+	}
+
+	newApp := &entity.App{
+		AppName:     appName,
+		Cover:       req.Cover,
+		InitPrompt:  req.InitPrompt,
+		CodeGenType: codeGenType,
+		UserID:      loginUser.ID,
+		EditTime:    time.Now(),
+	}
+	if err := s.appMapper.Save(newApp); err != nil {
+		return 0, exception.NewBusinessErrorWithMessage(exception.OperationError, "创建应用失败，数据库错误")
+	}
+	return newApp.ID, nil
+}
+
+// UpdateApp 更新应用
+func (s *AppServiceImpl) UpdateApp(ctx context.Context, req *app.AppUpdateRequest) (bool, error) {
+	if req == nil || req.ID <= 0 {
+		return false, exception.NewBusinessErrorFromCode(exception.ParamsError)
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return false, err
+	}
+	if _, err := s.getAccessibleApp(loginUser, req.ID); err != nil {
+		return false, err
+	}
+
+	updateApp := &entity.App{
+		ID:       req.ID,
+		AppName:  req.AppName,
+		Cover:    req.Cover,
+		EditTime: time.Now(),
+	}
+	if req.Priority != nil {
+		if !isAdmin(loginUser) {
+			return false, exception.NewBusinessErrorWithMessage(exception.NoAuthError, "仅管理员可修改应用优先级")
+		}
+		updateApp.Priority = *req.Priority
+	}
+
+	if err := s.appMapper.UpdateById(updateApp); err != nil {
+		return false, exception.NewBusinessErrorFromCode(exception.OperationError)
+	}
+	return true, nil
+}
+
+// DeleteApp 删除应用
+func (s *AppServiceImpl) DeleteApp(ctx context.Context, id int64) (bool, error) {
+	if id <= 0 {
+		return false, exception.NewBusinessErrorFromCode(exception.ParamsError)
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return false, err
+	}
+	appEntity, err := s.getAccessibleApp(loginUser, id)
+	if err != nil {
+		return false, err
+	}
+	if err := s.appMapper.DeleteById(id); err != nil {
+		return false, exception.NewBusinessErrorFromCode(exception.OperationError)
+	}
+	// 下线已部署的站点
+	if err := file.RemoveDeploy(appEntity.DeployKey); err != nil {
+		logrus.Errorf("删除应用部署目录失败, appId=%d: %v", id, err)
+	}
+	// 同步删除应用下的对话历史
+	if err := s.chatHistoryMapper.DeleteByAppId(id); err != nil {
+		logrus.Errorf("删除应用对话历史失败, appId=%d: %v", id, err)
+	}
+	// 同步删除应用的代码版本与快照
+	if err := s.appVersionMapper.DeleteByAppId(id); err != nil {
+		logrus.Errorf("删除应用版本记录失败, appId=%d: %v", id, err)
+	}
+	if err := file.RemoveAppVersions(strconv.FormatInt(id, 10)); err != nil {
+		logrus.Errorf("删除应用版本快照失败, appId=%d: %v", id, err)
+	}
+	// 删除应用目录中生成的代码与上传的图片
+	if err := file.RemoveAppDir(strconv.FormatInt(id, 10)); err != nil {
+		logrus.Errorf("删除应用目录失败, appId=%d: %v", id, err)
+	}
+	return true, nil
+}
+
+// GetAppVO 获取应用详情
+func (s *AppServiceImpl) GetAppVO(ctx context.Context, id int64) (*vo.AppVO, error) {
+	if id <= 0 {
+		return nil, exception.NewBusinessErrorFromCode(exception.ParamsError)
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	appEntity, err := s.getAccessibleApp(loginUser, id)
+	if err != nil {
+		return nil, err
+	}
+	appVO := s.getAppVO(appEntity)
+	if owner, err := s.userService.GetById(appEntity.UserID); err == nil {
+		appVO.User = s.userService.GetUserVO(owner)
+	}
+	return appVO, nil
+}
+
+// ListAppVOByPage 分页获取应用列表
+func (s *AppServiceImpl) ListAppVOByPage(ctx context.Context, req *app.AppQueryRequest) ([]vo.AppVO, int64, error) {
+	if req == nil {
+		return nil, 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "请求参数为空")
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 构建查询条件
+	query := s.appMapper.DB.Model(&entity.App{}).Where("is_delete = 0")
+
+	// 普通用户只能查看自己的应用，管理员可按用户筛选
+	if !isAdmin(loginUser) {
+		query = query.Where("user_id = ?", loginUser.ID)
+	} else if req.UserID != nil {
+		query = query.Where("user_id = ?", *req.UserID)
+	}
+	if req.ID != nil {
+		query = query.Where("id = ?", *req.ID)
+	}
+	if req.AppName != "" {
+		query = query.Where("app_name LIKE ?", "%"+req.AppName+"%")
+	}
+	if req.CodeGenType != "" {
+		query = query.Where("code_gen_type = ?", req.CodeGenType)
+	}
+
+	// 排序
+	if column, ok := appSortFields[req.SortField]; ok {
+		order := "DESC"
+		if req.SortOrder == "ascend" {
+			order = "ASC"
+		}
+		query = query.Order(fmt.Sprintf("%s %s", column, order))
+	} else {
+		query = query.Order("priority DESC, create_time DESC")
+	}
+
+	// 计算分页
+	pageNum := req.PageNum
+	pageSize := req.PageSize
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	offset := (pageNum - 1) * pageSize
+
+	apps, total, err := s.appMapper.Page(offset, pageSize, query)
+	if err != nil {
+		return nil, 0, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询应用失败")
+	}
+
+	appVOList := make([]vo.AppVO, 0, len(apps))
+	for i := range apps {
+		appVOList = append(appVOList, *s.getAppVO(&apps[i]))
+	}
+	return appVOList, total, nil
+}
+
+// DeployApp 部署应用
+func (s *AppServiceImpl) DeployApp(ctx context.Context, id int64) (string, error) {
+	appEntity, err := s.GetOwnedApp(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	appId := strconv.FormatInt(appEntity.ID, 10)
+	if !file.AppCodeExists(appId) {
+		return "", exception.NewBusinessErrorWithMessage(exception.ParamsError, "应用代码不存在，请先生成代码")
+	}
+
+	deployKey := appEntity.DeployKey
+	if deployKey == "" {
+		if deployKey, err = s.generateDeployKey(); err != nil {
+			logrus.Errorf("生成部署标识失败, appId=%d: %v", id, err)
+			return "", exception.NewBusinessErrorWithMessage(exception.SystemError, "生成部署标识失败")
+		}
+	}
+	if err := file.DeployApp(appId, deployKey); err != nil {
+		logrus.Errorf("部署应用失败, appId=%d: %v", id, err)
+		return "", exception.NewBusinessErrorWithMessage(exception.OperationError, "部署失败")
+	}
+
+	now := time.Now()
+	if err := s.appMapper.UpdateById(&entity.App{
+		ID:           appEntity.ID,
+		DeployKey:    deployKey,
+		DeployedTime: &now,
+	}); err != nil {
+		return "", exception.NewBusinessErrorWithMessage(exception.OperationError, "更新部署信息失败")
+	}
+	return buildDeployURL(deployKey), nil
+}
+
+// DownloadApp 校验应用及版本，返回 zip 文件名与打包函数
+func (s *AppServiceImpl) DownloadApp(ctx context.Context, id int64,
+	version int) (string, func(w io.Writer) error, error) {
+	if version < 0 {
+		return "", nil, exception.NewBusinessErrorWithMessage(exception.ParamsError, "版本号非法")
+	}
+	appEntity, err := s.GetOwnedApp(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+	appId := strconv.FormatInt(appEntity.ID, 10)
+	fileName := fmt.Sprintf("app-%d.zip", appEntity.ID)
+	if version > 0 {
+		if _, err := s.appVersionMapper.GetByAppIdAndVersion(appEntity.ID, version); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", nil, exception.NewBusinessErrorWithMessage(exception.NotFoundError,
+					fmt.Sprintf("版本 %d 不存在", version))
+			}
+			return "", nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询版本失败")
+		}
+		if !file.AppVersionExists(appId, version) {
+			return "", nil, exception.NewBusinessErrorWithMessage(exception.NotFoundError, "版本快照文件不存在")
+		}
+		fileName = fmt.Sprintf("app-%d-v%d.zip", appEntity.ID, version)
+	} else if !file.AppCodeExists(appId) {
+		return "", nil, exception.NewBusinessErrorWithMessage(exception.ParamsError, "应用代码不存在，请先生成代码")
+	}
+	return fileName, func(w io.Writer) error {
+		return file.WriteAppZip(w, appId, version)
+	}, nil
+}
+
+// GetOwnedApp 获取当前登录用户名下的应用
+func (s *AppServiceImpl) GetOwnedApp(ctx context.Context, id int64) (*entity.App, error) {
+	if id <= 0 {
+		return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError, "应用 id 非法")
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	appEntity, err := s.getAppById(id)
+	if err != nil {
+		return nil, err
+	}
+	if appEntity.UserID != loginUser.ID {
+		return nil, exception.NewBusinessErrorWithMessage(exception.NoAuthError, "无权限操作该应用")
+	}
+	return appEntity, nil
+}
+
+// getAccessibleApp 获取应用并校验访问权限：本人或管理员
+func (s *AppServiceImpl) getAccessibleApp(loginUser *entity.User, id int64) (*entity.App, error) {
+	appEntity, err := s.getAppById(id)
+	if err != nil {
+		return nil, err
+	}
+	if appEntity.UserID != loginUser.ID && !isAdmin(loginUser) {
+		return nil, exception.NewBusinessErrorWithMessage(exception.NoAuthError, "无权限操作该应用")
+	}
+	return appEntity, nil
+}
+
+// generateDeployKey 生成未被占用的随机部署标识
+func (s *AppServiceImpl) generateDeployKey() (string, error) {
+	for i := 0; i < 5; i++ {
+		key := make([]byte, deployKeyLength)
+		for j := range key {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(deployKeyAlphabet))))
+			if err != nil {
+				return "", err
+			}
+			key[j] = deployKeyAlphabet[n.Int64()]
+		}
+		exists, err := s.appMapper.ExistsByDeployKey(string(key))
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return string(key), nil
+		}
+	}
+	return "", errors.New("部署标识冲突次数过多")
+}
+
+// buildDeployURL 构造部署访问地址
+func buildDeployURL(deployKey string) string {
+	return fmt.Sprintf("%s/%s/", config.GetConfig().GetDeployBaseURL(), deployKey)
+}
+
+// getAppById 根据ID查询应用，不存在时返回 NotFoundError
+func (s *AppServiceImpl) getAppById(id int64) (*entity.App, error) {
+	appEntity, err := s.appMapper.GetById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exception.NewBusinessErrorWithMessage(exception.NotFoundError, "应用不存在")
+		}
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询应用失败")
+	}
+	return appEntity, nil
+}
+
+// getAppVO 实体转换为应用信息
+func (s *AppServiceImpl) getAppVO(appEntity *entity.App) *vo.AppVO {
+	appVO := &vo.AppVO{
+		ID:           appEntity.ID,
+		AppName:      appEntity.AppName,
+		Cover:        appEntity.Cover,
+		InitPrompt:   appEntity.InitPrompt,
+		CodeGenType:  appEntity.CodeGenType,
+		Priority:     appEntity.Priority,
+		DeployKey:    appEntity.DeployKey,
+		DeployedTime: appEntity.DeployedTime,
+		UserID:       appEntity.UserID,
+		CreateTime:   appEntity.CreateTime,
+		UpdateTime:   appEntity.UpdateTime,
+	}
+	if appEntity.DeployKey != "" {
+		appVO.DeployURL = buildDeployURL(appEntity.DeployKey)
+	}
+	return appVO
+}
@@ -0,0 +1,190 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"aicode/consts"
+	"aicode/file"
+	"aicode/internal/exception"
+	"aicode/internal/mapper"
+	"aicode/internal/model/entity"
+	"aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// AppVersionServiceImpl 应用代码版本服务实现
+type AppVersionServiceImpl struct {
+	appVersionMapper        *mapper.AppVersionMapper
+	appService              service.AppService
+	promptExperimentService service.PromptExperimentService
+	// appLocks 按应用串行化版本号分配、快照与回滚，value 为 *sync.Mutex
+	appLocks sync.Map
+}
+
+// NewAppVersionService 创建应用代码版本服务实例
+func NewAppVersionService(appVersionMapper *mapper.AppVersionMapper,
+	appService service.AppService,
+	promptExperimentService service.PromptExperimentService) service.AppVersionService {
+	return &AppVersionServiceImpl{
+		appVersionMapper:        appVersionMapper,
+		appService:              appService,
+		promptExperimentService: promptExperimentService,
+	}
+}
+
+// CreateVersion 为应用目录当前内容创建新的版本快照
+func (s *AppVersionServiceImpl) CreateVersion(ctx context.Context, appId int64, prompt, model string,
+	genType consts.CodeGenarateType) (int, error) {
+	appEntity, err := s.appService.GetOwnedApp(ctx, appId)
+	if err != nil {
+		return 0, err
+	}
+	unlock := s.lockApp(appId)
+	defer unlock()
+	return s.createVersion(appEntity, prompt, model, string(genType))
+}
+
+// ListVersions 按版本号倒序列出应用的全部版本
+func (s *AppVersionServiceImpl) ListVersions(ctx context.Context, appId int64) ([]vo.AppVersionVO, error) {
+	if _, err := s.appService.GetOwnedApp(ctx, appId); err != nil {
+		return nil, err
+	}
+	versions, err := s.appVersionMapper.ListByAppId(appId)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询版本列表失败")
+	}
+	versionVOList := make([]vo.AppVersionVO, 0, len(versions))
+	for i := range versions {
+		versionVOList = append(versionVOList, getAppVersionVO(&versions[i]))
+	}
+	return versionVOList, nil
+}
+
+// DiffVersions 比较应用的两个版本
+func (s *AppVersionServiceImpl) DiffVersions(ctx context.Context, appId int64,
+	from, to int) (*vo.AppVersionDiffVO, error) {
+	if from <= 0 || to <= 0 {
+		return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError, "版本号非法")
+	}
+	if _, err := s.appService.GetOwnedApp(ctx, appId); err != nil {
+		return nil, err
+	}
+	for _, version := range []int{from, to} {
+		if _, err := s.getVersion(appId, version); err != nil {
+			return nil, err
+		}
+	}
+	diffs, err := file.DiffAppVersions(strconv.FormatInt(appId, 10), from, to)
+	if err != nil {
+		logrus.Errorf("比较应用版本失败, appId=%d, v%d..v%d: %v", appId, from, to, err)
+		return nil, exception.NewBusinessErrorWithMessage(exception.OperationError, "比较版本失败")
+	}
+	diffVO := &vo.AppVersionDiffVO{
+		From:  from,
+		To:    to,
+		Files: make([]vo.AppVersionFileDiffVO, 0, len(diffs)),
+	}
+	for _, diff := range diffs {
+		diffVO.Files = append(diffVO.Files, vo.AppVersionFileDiffVO{
+			Path:   diff.Path,
+			Status: diff.Status,
+			Binary: diff.Binary,
+			Diff:   diff.Diff,
+		})
+	}
+	return diffVO, nil
+}
+
+// Rollback 将应用目录恢复为指定版本，并记录为一个新版本
+func (s *AppVersionServiceImpl) Rollback(ctx context.Context, appId int64, version int) (int, error) {
+	if version <= 0 {
+		return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "版本号非法")
+	}
+	appEntity, err := s.appService.GetOwnedApp(ctx, appId)
+	if err != nil {
+		return 0, err
+	}
+	unlock := s.lockApp(appId)
+	defer unlock()
+
+	target, err := s.getVersion(appId, version)
+	if err != nil {
+		return 0, err
+	}
+	if err := file.RestoreAppVersion(strconv.FormatInt(appId, 10), version); err != nil {
+		logrus.Errorf("回滚应用版本失败, appId=%d, version=%d: %v", appId, version, err)
+		return 0, exception.NewBusinessErrorWithMessage(exception.OperationError, "回滚失败")
+	}
+	// 被回滚掉的版本计入提示词实验的回滚信号
+	s.promptExperimentService.RecordRollback(ctx, appId, version)
+	return s.createVersion(appEntity, fmt.Sprintf("回滚到版本 %d", version), target.Model, target.GenType)
+}
+
+// createVersion 分配新版本号、保存目录快照并记录版本信息，调用方须持有应用锁
+func (s *AppVersionServiceImpl) createVersion(appEntity *entity.App, prompt, model, genType string) (int, error) {
+	appId := strconv.FormatInt(appEntity.ID, 10)
+	if !file.AppCodeExists(appId) {
+		return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "应用代码不存在，请先生成代码")
+	}
+	maxVersion, err := s.appVersionMapper.GetMaxVersion(appEntity.ID)
+	if err != nil {
+		return 0, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询版本号失败")
+	}
+	version := maxVersion + 1
+	if err := file.SnapshotApp(appId, version); err != nil {
+		logrus.Errorf("保存应用版本快照失败, appId=%d, version=%d: %v", appEntity.ID, version, err)
+		return 0, exception.NewBusinessErrorWithMessage(exception.OperationError, "保存版本快照失败")
+	}
+	if err := s.appVersionMapper.Save(&entity.AppVersion{
+		AppID:   appEntity.ID,
+		Version: version,
+		Prompt:  prompt,
+		Model:   model,
+		GenType: genType,
+		UserID:  appEntity.UserID,
+	}); err != nil {
+		_ = file.RemoveAppVersion(appId, version)
+		return 0, exception.NewBusinessErrorWithMessage(exception.OperationError, "保存版本记录失败，数据库错误")
+	}
+	return version, nil
+}
+
+// getVersion 查询应用的指定版本，不存在时返回 NotFoundError
+func (s *AppVersionServiceImpl) getVersion(appId int64, version int) (*entity.AppVersion, error) {
+	appVersion, err := s.appVersionMapper.GetByAppIdAndVersion(appId, version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exception.NewBusinessErrorWithMessage(exception.NotFoundError,
+				fmt.Sprintf("版本 %d 不存在", version))
+		}
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询版本失败")
+	}
+	return appVersion, nil
+}
+
+// lockApp 获取应用的版本锁，返回解锁函数
+func (s *AppVersionServiceImpl) lockApp(appId int64) func() {
+	value, _ := s.appLocks.LoadOrStore(appId, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// getAppVersionVO 实体转换为版本信息
+func getAppVersionVO(appVersion *entity.AppVersion) vo.AppVersionVO {
+	return vo.AppVersionVO{
+		Version:    appVersion.Version,
+		Prompt:     appVersion.Prompt,
+		Model:      appVersion.Model,
+		GenType:    appVersion.GenType,
+		UserID:     appVersion.UserID,
+		CreateTime: appVersion.CreateTime,
+	}
+}
@@ -0,0 +1,284 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"aicode/config"
+	"aicode/constant"
+	"aicode/internal/exception"
+	"aicode/internal/mapper"
+	"aicode/internal/model/entity"
+	"aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// apiKeyTouchInterval API Key 最近使用时间的最小更新间隔，避免每次请求都写库
+const apiKeyTouchInterval = time.Minute
+
+// authClaims 令牌声明，sid 为令牌会话id，jti 为令牌id
+type authClaims struct {
+	TokenType string `json:"typ"`
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// AuthTokenServiceImpl 令牌认证服务实现
+type AuthTokenServiceImpl struct {
+	authTokenMapper *mapper.AuthTokenMapper
+	apiKeyMapper    *mapper.ApiKeyMapper
+	userMapper      *mapper.UserMapper
+}
+
+// NewAuthTokenService 创建令牌认证服务实例
+func NewAuthTokenService(authTokenMapper *mapper.AuthTokenMapper, apiKeyMapper *mapper.ApiKeyMapper,
+	userMapper *mapper.UserMapper) service.AuthTokenService {
+	return &AuthTokenServiceImpl{
+		authTokenMapper: authTokenMapper,
+		apiKeyMapper:    apiKeyMapper,
+		userMapper:      userMapper,
+	}
+}
+
+// IssueTokens 为用户创建令牌会话并签发令牌
+func (s *AuthTokenServiceImpl) IssueTokens(ctx context.Context, userId int64) (*vo.AuthTokenVO, error) {
+	sessionId, err := randomTokenId()
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "生成令牌失败")
+	}
+	refreshJti, err := randomTokenId()
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "生成令牌失败")
+	}
+	now := time.Now()
+	authToken := &entity.AuthToken{
+		UserID:     userId,
+		SessionID:  sessionId,
+		RefreshJTI: refreshJti,
+		ExpireTime: now.Add(config.GetConfig().Security.JWT.GetRefreshTTL()),
+	}
+	if err := s.authTokenMapper.Save(authToken); err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.OperationError, "保存令牌会话失败，数据库错误")
+	}
+	return s.signTokens(authToken, now)
+}
+
+// RefreshTokens 轮换 refresh 令牌并签发新的令牌
+func (s *AuthTokenServiceImpl) RefreshTokens(ctx context.Context, refreshToken string) (*vo.AuthTokenVO, error) {
+	claims, err := parseAuthToken(refreshToken, constant.TokenTypeRefresh)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.NotLoginError, "refresh 令牌无效或已过期")
+	}
+	authToken, err := s.getActiveSession(claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if claims.ID != authToken.RefreshJTI {
+		// 已轮换的 refresh 令牌被再次使用，令牌可能已泄露
+		logrus.Warnf("refresh 令牌被重复使用，吊销令牌会话, userId=%d, sessionId=%s", authToken.UserID, authToken.SessionID)
+		if err := s.authTokenMapper.Revoke(authToken.SessionID); err != nil {
+			logrus.Errorf("吊销令牌会话失败, sessionId=%s: %v", authToken.SessionID, err)
+		}
+		return nil, exception.NewBusinessErrorWithMessage(exception.NotLoginError, "refresh 令牌已失效，请重新登录")
+	}
+	if _, err := s.userMapper.GetById(authToken.UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exception.NewBusinessErrorFromCode(exception.NotLoginError)
+		}
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询用户失败")
+	}
+
+	newJti, err := randomTokenId()
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "生成令牌失败")
+	}
+	rotated, err := s.authTokenMapper.RotateRefresh(authToken.SessionID, claims.ID, newJti)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.OperationError, "刷新令牌失败，数据库错误")
+	}
+	if !rotated {
+		// 并发刷新时已被其他请求轮换
+		return nil, exception.NewBusinessErrorWithMessage(exception.NotLoginError, "refresh 令牌已失效，请重新登录")
+	}
+	authToken.RefreshJTI = newJti
+	return s.signTokens(authToken, time.Now())
+}
+
+// RevokeToken 吊销 refresh 令牌所属的令牌会话
+func (s *AuthTokenServiceImpl) RevokeToken(ctx context.Context, refreshToken string) (bool, error) {
+	claims, err := parseAuthToken(refreshToken, constant.TokenTypeRefresh)
+	if err != nil {
+		return false, exception.NewBusinessErrorWithMessage(exception.ParamsError, "refresh 令牌无效或已过期")
+	}
+	if err := s.authTokenMapper.Revoke(claims.SessionID); err != nil {
+		return false, exception.NewBusinessErrorWithMessage(exception.OperationError, "吊销令牌失败，数据库错误")
+	}
+	return true, nil
+}
+
+// AuthenticateBearer 解析 access 令牌或个人 API Key
+func (s *AuthTokenServiceImpl) AuthenticateBearer(ctx context.Context, token string) (*entity.User, []string, error) {
+	if strings.HasPrefix(token, constant.ApiKeyPrefix) {
+		return s.authenticateApiKey(token)
+	}
+	claims, err := parseAuthToken(token, constant.TokenTypeAccess)
+	if err != nil {
+		return nil, nil, exception.NewBusinessErrorWithMessage(exception.NotLoginError, "access 令牌无效或已过期")
+	}
+	authToken, err := s.getActiveSession(claims.SessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if claims.Subject != strconv.FormatInt(authToken.UserID, 10) {
+		return nil, nil, exception.NewBusinessErrorWithMessage(exception.NotLoginError, "access 令牌无效或已过期")
+	}
+	user, err := s.getUser(authToken.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, nil, nil
+}
+
+// AuthenticateSession 按 session 中保存的用户 id 加载登录用户
+func (s *AuthTokenServiceImpl) AuthenticateSession(ctx context.Context, userId int64) (*entity.User, error) {
+	return s.getUser(userId)
+}
+
+// authenticateApiKey 按密钥哈希查询 API Key，返回所属用户与授权范围
+func (s *AuthTokenServiceImpl) authenticateApiKey(key string) (*entity.User, []string, error) {
+	apiKey, err := s.apiKeyMapper.GetByHash(hashApiKey(key))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, exception.NewBusinessErrorWithMessage(exception.NotLoginError, "API Key 无效")
+		}
+		return nil, nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询 API Key 失败")
+	}
+	now := time.Now()
+	if apiKey.Revoked == 1 || (apiKey.ExpireTime != nil && now.After(*apiKey.ExpireTime)) {
+		return nil, nil, exception.NewBusinessErrorWithMessage(exception.NotLoginError, "API Key 已吊销或已过期")
+	}
+	user, err := s.getUser(apiKey.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if apiKey.LastUsedTime == nil || now.Sub(*apiKey.LastUsedTime) > apiKeyTouchInterval {
+		if err := s.apiKeyMapper.TouchLastUsed(apiKey.ID, now); err != nil {
+			logrus.Errorf("更新 API Key 使用时间失败, apiKeyId=%d: %v", apiKey.ID, err)
+		}
+	}
+	return user, apiKey.Scopes, nil
+}
+
+// getActiveSession 查询未吊销且未过期的令牌会话
+func (s *AuthTokenServiceImpl) getActiveSession(sessionId string) (*entity.AuthToken, error) {
+	authToken, err := s.authTokenMapper.GetBySessionId(sessionId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exception.NewBusinessErrorWithMessage(exception.NotLoginError, "登录已失效，请重新登录")
+		}
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询令牌会话失败")
+	}
+	if authToken.Revoked == 1 || time.Now().After(authToken.ExpireTime) {
+		return nil, exception.NewBusinessErrorWithMessage(exception.NotLoginError, "登录已失效，请重新登录")
+	}
+	return authToken, nil
+}
+
+// getUser 查询凭证所属的用户，用户已删除时视为未登录；返回的用户会写入请求上下文，因此清空密码哈希
+func (s *AuthTokenServiceImpl) getUser(userId int64) (*entity.User, error) {
+	user, err := s.userMapper.GetById(userId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exception.NewBusinessErrorFromCode(exception.NotLoginError)
+		}
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询用户失败")
+	}
+	user.UserPassword = ""
+	return user, nil
+}
+
+// signTokens 为令牌会话签发 access 令牌与当前的 refresh 令牌，access 令牌不晚于会话过期
+func (s *AuthTokenServiceImpl) signTokens(authToken *entity.AuthToken, now time.Time) (*vo.AuthTokenVO, error) {
+	jwtCfg := &config.GetConfig().Security.JWT
+	accessJti, err := randomTokenId()
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "生成令牌失败")
+	}
+	accessExpire := now.Add(jwtCfg.GetAccessTTL())
+	if accessExpire.After(authToken.ExpireTime) {
+		accessExpire = authToken.ExpireTime
+	}
+	subject := strconv.FormatInt(authToken.UserID, 10)
+	accessToken, err := signAuthToken(constant.TokenTypeAccess, authToken.SessionID, subject, accessJti, now, accessExpire)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "签发令牌失败")
+	}
+	refreshToken, err := signAuthToken(constant.TokenTypeRefresh, authToken.SessionID, subject, authToken.RefreshJTI,
+		now, authToken.ExpireTime)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "签发令牌失败")
+	}
+	return &vo.AuthTokenVO{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(accessExpire.Sub(now).Seconds()),
+		RefreshExpiresAt: authToken.ExpireTime,
+	}, nil
+}
+
+// signAuthToken 使用 HS256 签发令牌
+func signAuthToken(tokenType, sessionId, subject, jti string, issuedAt, expireAt time.Time) (string, error) {
+	cfg := config.GetConfig()
+	claims := authClaims{
+		TokenType: tokenType,
+		SessionID: sessionId,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    cfg.Security.JWT.GetIssuer(),
+			Subject:   subject,
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expireAt),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).
+		SignedString([]byte(cfg.Security.JWT.GetSecret(cfg.Server.SessionSecret)))
+}
+
+// parseAuthToken 校验令牌的签名、签发者、有效期与类型
+func parseAuthToken(token, tokenType string) (*authClaims, error) {
+	cfg := config.GetConfig()
+	claims := &authClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return []byte(cfg.Security.JWT.GetSecret(cfg.Server.SessionSecret)), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(cfg.Security.JWT.GetIssuer()),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != tokenType || claims.SessionID == "" {
+		return nil, errors.New("令牌类型不匹配")
+	}
+	return claims, nil
+}
+
+// randomTokenId 生成 128 位随机id
+func randomTokenId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
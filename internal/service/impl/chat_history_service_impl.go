@@ -0,0 +1,159 @@
+package impl
+
+import (
+	"context"
+	"strings"
+
+	"aicode/consts"
+	"aicode/internal/exception"
+	"aicode/internal/mapper"
+	"aicode/internal/model/dto/chathistory"
+	"aicode/internal/model/entity"
+	"aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"github.com/cloudwego/eino/schema"
+	"gorm.io/gorm"
+)
+
+// chatHistoryMaxPageSize 游标分页单页最大条数
+const chatHistoryMaxPageSize = 50
+
+// ChatHistoryServiceImpl 对话历史服务实现
+type ChatHistoryServiceImpl struct {
+	chatHistoryMapper *mapper.ChatHistoryMapper
+	appService        service.AppService
+}
+
+// NewChatHistoryService 创建对话历史服务实例
+func NewChatHistoryService(chatHistoryMapper *mapper.ChatHistoryMapper,
+	appService service.AppService) service.ChatHistoryService {
+	return &ChatHistoryServiceImpl{
+		chatHistoryMapper: chatHistoryMapper,
+		appService:        appService,
+	}
+}
+
+// AddChatMessage 保存一条对话消息
+func (s *ChatHistoryServiceImpl) AddChatMessage(ctx context.Context, appId int64, conversationId string,
+	role consts.ChatRole, message string) error {
+	if role != consts.ChatRoleUser && role != consts.ChatRoleAssistant {
+		return exception.NewBusinessErrorWithMessage(exception.ParamsError, "不支持的消息类型")
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	history := &entity.ChatHistory{
+		Message:        message,
+		MessageType:    string(role),
+		AppID:          appId,
+		ConversationID: conversationId,
+		UserID:         loginUser.ID,
+	}
+	if err := s.chatHistoryMapper.Save(history); err != nil {
+		return exception.NewBusinessErrorWithMessage(exception.OperationError, "保存对话历史失败")
+	}
+	return nil
+}
+
+// LoadHistoryMessages 加载最近的对话消息
+func (s *ChatHistoryServiceImpl) LoadHistoryMessages(ctx context.Context, appId int64, conversationId string,
+	maxCount int) ([]*schema.Message, error) {
+	if maxCount <= 0 {
+		return []*schema.Message{}, nil
+	}
+	query, err := s.buildScopeQuery(ctx, appId, conversationId)
+	if err != nil {
+		return nil, err
+	}
+	histories, err := s.chatHistoryMapper.ListBeforeId(query, 0, maxCount)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询对话历史失败")
+	}
+
+	// 查询结果按 id 倒序，反向遍历还原为时间正序
+	messages := make([]*schema.Message, 0, len(histories))
+	for i := len(histories) - 1; i >= 0; i-- {
+		switch consts.ChatRole(histories[i].MessageType) {
+		case consts.ChatRoleUser:
+			messages = append(messages, schema.UserMessage(histories[i].Message))
+		case consts.ChatRoleAssistant:
+			messages = append(messages, schema.AssistantMessage(histories[i].Message, nil))
+		}
+	}
+	return messages, nil
+}
+
+// ListByCursor 游标分页查询对话历史
+func (s *ChatHistoryServiceImpl) ListByCursor(ctx context.Context,
+	req *chathistory.ChatHistoryQueryRequest) (*vo.ChatHistoryCursorPageVO, error) {
+	if req == nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError, "请求参数为空")
+	}
+	// 应用对话需校验应用归属
+	if req.AppID > 0 {
+		if _, err := s.appService.GetOwnedApp(ctx, req.AppID); err != nil {
+			return nil, err
+		}
+	}
+	query, err := s.buildScopeQuery(ctx, req.AppID, req.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > chatHistoryMaxPageSize {
+		pageSize = chatHistoryMaxPageSize
+	}
+
+	// 多查一条用于判断是否还有更早的消息
+	histories, err := s.chatHistoryMapper.ListBeforeId(query, req.Cursor, pageSize+1)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询对话历史失败")
+	}
+	hasMore := len(histories) > pageSize
+	if hasMore {
+		histories = histories[:pageSize]
+	}
+
+	page := &vo.ChatHistoryCursorPageVO{
+		Records: make([]vo.ChatHistoryVO, 0, len(histories)),
+		HasMore: hasMore,
+	}
+	for _, history := range histories {
+		page.Records = append(page.Records, vo.ChatHistoryVO{
+			ID:             history.ID,
+			Message:        history.Message,
+			MessageType:    history.MessageType,
+			AppID:          history.AppID,
+			ConversationID: history.ConversationID,
+			CreateTime:     history.CreateTime,
+		})
+	}
+	if len(histories) > 0 {
+		page.NextCursor = histories[len(histories)-1].ID
+	}
+	return page, nil
+}
+
+// buildScopeQuery 构造对话归档范围的查询条件
+func (s *ChatHistoryServiceImpl) buildScopeQuery(ctx context.Context,
+	appId int64, conversationId string) (*gorm.DB, error) {
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := s.chatHistoryMapper.DB.Model(&entity.ChatHistory{}).Where("is_delete = 0")
+	if appId > 0 {
+		return query.Where("app_id = ?", appId), nil
+	}
+	if strings.TrimSpace(conversationId) == "" {
+		return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError, "appId 与 conversationId 不能同时为空")
+	}
+	return query.Where("user_id = ? AND conversation_id = ? AND app_id = 0",
+		loginUser.ID, conversationId), nil
+}
@@ -0,0 +1,58 @@
+package impl
+
+import (
+	"context"
+
+	"aicode/ai/chatmodel"
+	"aicode/consts"
+	"aicode/internal/exception"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// checkChatModelAccess 校验模型已注册且当前登录用户的角色允许使用该模型
+func checkChatModelAccess(ctx context.Context, name string) error {
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	meta, ok := chatmodel.GetChatModelMeta(name)
+	if !ok {
+		return exception.NewBusinessErrorWithMessage(exception.ParamsError, "不支持的模型类型: "+name)
+	}
+	if !meta.AllowRole(loginUser.UserRole) {
+		return exception.NewBusinessErrorWithMessage(exception.NoAuthError, "当前用户无权使用该模型: "+name)
+	}
+	return nil
+}
+
+// checkToolCallSupport 校验模型支持工具调用，须在 checkChatModelAccess 之后调用
+func checkToolCallSupport(name string) error {
+	meta, _ := chatmodel.GetChatModelMeta(name)
+	if !meta.SupportToolCall {
+		return exception.NewBusinessErrorWithMessage(exception.ParamsError, "该模型不支持工具调用: "+name)
+	}
+	return nil
+}
+
+// allowLoginUserRole 返回降级链过滤函数，仅保留当前用户角色可用的模型
+func allowLoginUserRole(ctx context.Context) (func(meta chatmodel.ChatModelMeta) bool, error) {
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return func(meta chatmodel.ChatModelMeta) bool {
+		return meta.AllowRole(loginUser.UserRole)
+	}, nil
+}
+
+// resilientChat 带重试与降级地调用模型，降级链上仅保留当前用户角色可用的模型
+// 调用前需先通过 checkChatModelAccess 校验请求的模型
+func resilientChat(ctx context.Context, name string, messages []*schema.Message,
+	respType consts.ChatRespType) (*chatmodel.ChatResult, error) {
+	allow, err := allowLoginUserRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return chatmodel.ResilientChat(ctx, name, messages, respType, allow)
+}
@@ -0,0 +1,446 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"aicode/config"
+	"aicode/constant"
+	"aicode/consts"
+	"aicode/internal/exception"
+	"aicode/internal/mapper"
+	"aicode/internal/model/entity"
+	"aicode/internal/model/vo"
+	"aicode/internal/service"
+	applog "aicode/log"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// codeGenJobListLimit 任务列表最多返回的条数
+	codeGenJobListLimit = 50
+	// codeGenJobErrorMaxRunes 失败原因的最大字符数，与 error_msg 列长度一致
+	codeGenJobErrorMaxRunes = 1024
+	// codeGenJobHeartbeatInterval 刷新执行中任务心跳的间隔
+	codeGenJobHeartbeatInterval = 30 * time.Second
+	// codeGenJobStaleAfter 心跳超过该时间未刷新的执行中任务视为执行实例已退出
+	codeGenJobStaleAfter = 3 * codeGenJobHeartbeatInterval
+	// codeGenJobPollInterval 调度器定期从数据库认领排队任务的间隔，用于接手其他实例提交或遗留的任务
+	codeGenJobPollInterval = 5 * time.Second
+)
+
+// runningCodeGenJob 本实例正在执行的任务
+type runningCodeGenJob struct {
+	model     string
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// CodeGenJobServiceImpl 异步代码生成任务服务实现
+// 排队中的任务只保存在数据库中，各实例的调度器按提交顺序以 MarkRunning 原子认领后在本实例执行，
+// 并发上限按实例计算；执行中的任务记录所在实例并定期刷新心跳，心跳超时的任务由任一实例标记为失败；
+// 取消其他实例执行的任务时记录取消请求，由执行实例轮询后取消
+type CodeGenJobServiceImpl struct {
+	codeGenJobMapper *mapper.CodeGenJobMapper
+	userMapper       *mapper.UserMapper
+	appService       service.AppService
+	aiCodeService    service.AICodeService
+
+	mu           sync.Mutex
+	running      map[int64]*runningCodeGenJob
+	modelRunning map[string]int
+	// wake 提交、结束或取消任务时通知调度器
+	wake chan struct{}
+	// submitMu 使排队上限的校验与任务的保存在本实例内原子执行
+	submitMu  sync.Mutex
+	startOnce sync.Once
+	// instanceId 本实例的标识，写入本实例执行的任务
+	instanceId string
+}
+
+// NewCodeGenJobService 创建异步代码生成任务服务实例
+func NewCodeGenJobService(codeGenJobMapper *mapper.CodeGenJobMapper, userMapper *mapper.UserMapper,
+	appService service.AppService, aiCodeService service.AICodeService) service.CodeGenJobService {
+	return &CodeGenJobServiceImpl{
+		codeGenJobMapper: codeGenJobMapper,
+		userMapper:       userMapper,
+		appService:       appService,
+		aiCodeService:    aiCodeService,
+		running:          make(map[int64]*runningCodeGenJob),
+		modelRunning:     make(map[string]int),
+		wake:             make(chan struct{}, 1),
+		instanceId:       newCodeGenJobInstanceId(),
+	}
+}
+
+// newCodeGenJobInstanceId 生成本进程的实例标识：主机名-进程号-随机串
+func newCodeGenJobInstanceId() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return truncateRunes(fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), hex.EncodeToString(suffix)), 61)
+}
+
+// Start 清理中断的任务并启动调度器与心跳
+func (s *CodeGenJobServiceImpl) Start() {
+	s.startOnce.Do(func() {
+		s.failStaleJobs()
+		go s.schedule()
+		go s.heartbeat()
+		s.notify()
+	})
+}
+
+// schedule 收到通知或定期轮询时调度排队中的任务，轮询时同时处理其他实例发来的取消请求
+func (s *CodeGenJobServiceImpl) schedule() {
+	ticker := time.NewTicker(codeGenJobPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.wake:
+		case <-ticker.C:
+			s.applyCancelRequests()
+		}
+		s.dispatch()
+	}
+}
+
+// applyCancelRequests 取消本实例执行中且已被请求取消的任务
+func (s *CodeGenJobServiceImpl) applyCancelRequests() {
+	ids, err := s.codeGenJobMapper.ListCancelRequested(s.instanceId)
+	if err != nil {
+		logrus.Errorf("查询代码生成任务取消请求失败: %v", err)
+		return
+	}
+	for _, id := range ids {
+		s.cancelRunning(id)
+	}
+}
+
+// heartbeat 定期刷新本实例执行中任务的心跳，并清理心跳超时的任务
+func (s *CodeGenJobServiceImpl) heartbeat() {
+	ticker := time.NewTicker(codeGenJobHeartbeatInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := s.codeGenJobMapper.Heartbeat(s.instanceId); err != nil {
+			logrus.Errorf("刷新代码生成任务心跳失败: %v", err)
+		}
+		s.failStaleJobs()
+	}
+}
+
+// failStaleJobs 将心跳超时（执行实例已退出或重启）的执行中任务标记为失败
+func (s *CodeGenJobServiceImpl) failStaleJobs() {
+	n, err := s.codeGenJobMapper.FailStale(time.Now().Add(-codeGenJobStaleAfter), "服务实例退出，任务执行中断")
+	if err != nil {
+		logrus.Errorf("清理中断的代码生成任务失败: %v", err)
+	} else if n > 0 {
+		logrus.Warnf("已将 %d 个中断的代码生成任务标记为失败", n)
+	}
+}
+
+// SubmitJob 校验后提交任务
+func (s *CodeGenJobServiceImpl) SubmitJob(ctx context.Context, params *vo.AICodeRequest) (int64, error) {
+	if params == nil {
+		return 0, exception.NewBusinessErrorFromCode(exception.ParamsError)
+	}
+	if len(params.Images) > 0 {
+		return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "异步任务暂不支持图片，请使用流式生成接口")
+	}
+	if !consts.IsValidCodeGenarateType(params.GenType) && params.GenType != consts.CodeGenarateTypeEdit {
+		return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "不支持的代码生成类型")
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := s.appService.GetOwnedApp(ctx, params.AppId); err != nil {
+		return 0, err
+	}
+	if err := checkChatModelAccess(ctx, string(params.Model)); err != nil {
+		return 0, err
+	}
+	job := &entity.CodeGenJob{
+		AppID:    params.AppId,
+		UserID:   loginUser.ID,
+		Model:    string(params.Model),
+		GenType:  string(params.GenType),
+		Question: params.Question,
+		Status:   string(consts.CodeJobStatusQueued),
+	}
+	if err := s.enqueue(job); err != nil {
+		return 0, err
+	}
+	s.notify()
+	return job.ID, nil
+}
+
+// enqueue 排队未满时保存任务
+func (s *CodeGenJobServiceImpl) enqueue(job *entity.CodeGenJob) error {
+	s.submitMu.Lock()
+	defer s.submitMu.Unlock()
+	queued, err := s.codeGenJobMapper.CountQueued()
+	if err != nil {
+		return exception.NewBusinessErrorWithMessage(exception.SystemError, "提交任务失败，数据库错误")
+	}
+	if queued >= int64(config.GetConfig().AI.JobQueue.GetQueueSize()) {
+		return exception.NewBusinessErrorWithMessage(exception.OperationError, "任务队列已满，请稍后再试")
+	}
+	if err := s.codeGenJobMapper.Save(job); err != nil {
+		return exception.NewBusinessErrorWithMessage(exception.OperationError, "提交任务失败，数据库错误")
+	}
+	return nil
+}
+
+// GetJob 查询任务状态与结果
+func (s *CodeGenJobServiceImpl) GetJob(ctx context.Context, id int64) (*vo.CodeGenJobVO, error) {
+	job, err := s.getOwnedJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	jobVO := s.getCodeGenJobVO(job)
+	jobVO.Content = job.Content
+	return &jobVO, nil
+}
+
+// ListJobs 按提交时间倒序查询当前用户在应用下最近的任务
+func (s *CodeGenJobServiceImpl) ListJobs(ctx context.Context, appId int64) ([]vo.CodeGenJobVO, error) {
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := s.codeGenJobMapper.ListByUserAndApp(loginUser.ID, appId, codeGenJobListLimit)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询任务列表失败")
+	}
+	jobVOList := make([]vo.CodeGenJobVO, 0, len(jobs))
+	for i := range jobs {
+		jobVOList = append(jobVOList, s.getCodeGenJobVO(&jobs[i]))
+	}
+	return jobVOList, nil
+}
+
+// CancelJob 取消排队中或执行中的任务，其他实例执行的任务记录取消请求后返回，由执行实例异步取消
+func (s *CodeGenJobServiceImpl) CancelJob(ctx context.Context, id int64) (bool, error) {
+	job, err := s.getOwnedJob(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if job.Status == string(consts.CodeJobStatusQueued) {
+		cancelled, err := s.codeGenJobMapper.CancelQueued(id)
+		if err != nil {
+			return false, exception.NewBusinessErrorWithMessage(exception.OperationError, "取消任务失败，数据库错误")
+		}
+		if cancelled {
+			return true, nil
+		}
+		// 取消前任务已开始执行
+	}
+
+	if s.cancelRunning(id) {
+		return true, nil
+	}
+	requested, err := s.codeGenJobMapper.RequestCancel(id)
+	if err != nil {
+		return false, exception.NewBusinessErrorWithMessage(exception.OperationError, "取消任务失败，数据库错误")
+	}
+	if !requested {
+		return false, exception.NewBusinessErrorWithMessage(exception.ParamsError, "任务已结束")
+	}
+	return true, nil
+}
+
+// cancelRunning 取消本实例执行中的任务，任务不在本实例执行时返回 false
+func (s *CodeGenJobServiceImpl) cancelRunning(id int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	running, ok := s.running[id]
+	if ok {
+		running.cancelled = true
+		running.cancel()
+	}
+	return ok
+}
+
+// dispatch 按提交顺序认领并启动未超出本实例全局与单模型并发上限的排队任务；
+// 只在调度 goroutine 中调用，执行中的任务数在认领期间只会减少
+func (s *CodeGenJobServiceImpl) dispatch() {
+	queueCfg := &config.GetConfig().AI.JobQueue
+	if !s.hasIdleWorker(queueCfg.GetWorkers()) {
+		return
+	}
+	queued, err := s.codeGenJobMapper.ListQueued()
+	if err != nil {
+		logrus.Errorf("查询排队中的代码生成任务失败: %v", err)
+		return
+	}
+	for i := range queued {
+		job := &queued[i]
+		s.mu.Lock()
+		full := len(s.running) >= queueCfg.GetWorkers()
+		modelFull := s.modelRunning[job.Model] >= queueCfg.GetModelConcurrency(job.Model)
+		s.mu.Unlock()
+		if full {
+			return
+		}
+		if modelFull {
+			continue
+		}
+		started, err := s.codeGenJobMapper.MarkRunning(job.ID, s.instanceId)
+		if err != nil {
+			logrus.Errorf("标记代码生成任务开始失败, jobId=%d: %v", job.ID, err)
+			continue
+		}
+		if !started {
+			// 任务已被其他实例认领或已取消
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), queueCfg.GetTimeout())
+		running := &runningCodeGenJob{model: job.Model, cancel: cancel}
+		s.mu.Lock()
+		s.running[job.ID] = running
+		s.modelRunning[job.Model]++
+		s.mu.Unlock()
+		go s.execute(ctx, job, running)
+	}
+}
+
+// hasIdleWorker 本实例执行中的任务数是否未达到上限
+func (s *CodeGenJobServiceImpl) hasIdleWorker(workers int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.running) < workers
+}
+
+// execute 以提交用户的身份执行已认领的任务并记录结果，结束后释放并发名额
+func (s *CodeGenJobServiceImpl) execute(ctx context.Context, job *entity.CodeGenJob, running *runningCodeGenJob) {
+	defer func() {
+		running.cancel()
+		s.mu.Lock()
+		delete(s.running, job.ID)
+		s.modelRunning[running.model]--
+		s.mu.Unlock()
+		s.notify()
+	}()
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.WithField("stack", applog.WithStack(r)).Errorf("执行代码生成任务异常, jobId=%d: %v", job.ID, r)
+			s.finish(job.ID, consts.CodeJobStatusFailed, "", "", "任务执行异常")
+		}
+	}()
+
+	user, err := s.userMapper.GetById(job.UserID)
+	if err != nil {
+		s.finish(job.ID, consts.CodeJobStatusFailed, "", "", "提交任务的用户不存在")
+		return
+	}
+	params := &vo.AICodeRequest{
+		AppId:    job.AppID,
+		Model:    consts.ChatModelType(job.Model),
+		GenType:  consts.CodeGenarateType(job.GenType),
+		Question: job.Question,
+	}
+	resp, err := s.aiCodeService.CodeGenerate(context.WithValue(ctx, constant.UserLoginState, user), params)
+	if err == nil {
+		s.finish(job.ID, consts.CodeJobStatusSucceeded, resp.Model, resp.Content, "")
+		return
+	}
+
+	s.mu.Lock()
+	cancelled := running.cancelled
+	s.mu.Unlock()
+	switch {
+	case cancelled:
+		s.finish(job.ID, consts.CodeJobStatusCancelled, "", "", "任务已取消")
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		s.finish(job.ID, consts.CodeJobStatusFailed, "", "", "任务执行超时")
+	default:
+		errorMsg := err.Error()
+		var bizErr *exception.BusinessError
+		if errors.As(err, &bizErr) {
+			errorMsg = bizErr.Message()
+		}
+		s.finish(job.ID, consts.CodeJobStatusFailed, "", "", truncateRunes(errorMsg, codeGenJobErrorMaxRunes))
+	}
+}
+
+// finish 记录任务结果，失败仅记录日志
+func (s *CodeGenJobServiceImpl) finish(id int64, status consts.CodeJobStatus, resultModel, content, errorMsg string) {
+	if err := s.codeGenJobMapper.Finish(id, status, resultModel, content, errorMsg); err != nil {
+		logrus.Errorf("记录代码生成任务结果失败, jobId=%d, status=%s: %v", id, status, err)
+		return
+	}
+	logrus.Infof("代码生成任务结束, jobId=%d, status=%s", id, status)
+}
+
+// notify 唤醒调度器，已有未处理的通知时不重复发送
+func (s *CodeGenJobServiceImpl) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// queuePosition 返回任务在全部排队任务中的位置，从 1 开始，查询失败时返回 0
+func (s *CodeGenJobServiceImpl) queuePosition(id int64) int {
+	before, err := s.codeGenJobMapper.CountQueuedBefore(id)
+	if err != nil {
+		logrus.Errorf("查询代码生成任务排队位置失败, jobId=%d: %v", id, err)
+		return 0
+	}
+	return int(before) + 1
+}
+
+// getOwnedJob 查询当前登录用户的任务，管理员可访问全部任务
+func (s *CodeGenJobServiceImpl) getOwnedJob(ctx context.Context, id int64) (*entity.CodeGenJob, error) {
+	if id <= 0 {
+		return nil, exception.NewBusinessErrorFromCode(exception.ParamsError)
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	job, err := s.codeGenJobMapper.GetById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exception.NewBusinessErrorWithMessage(exception.NotFoundError, "任务不存在")
+		}
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询任务失败")
+	}
+	if job.UserID != loginUser.ID && !isAdmin(loginUser) {
+		return nil, exception.NewBusinessErrorWithMessage(exception.NotFoundError, "任务不存在")
+	}
+	return job, nil
+}
+
+// getCodeGenJobVO 实体转换为任务信息，不包含输出内容
+func (s *CodeGenJobServiceImpl) getCodeGenJobVO(job *entity.CodeGenJob) vo.CodeGenJobVO {
+	jobVO := vo.CodeGenJobVO{
+		ID:          job.ID,
+		AppID:       job.AppID,
+		Model:       job.Model,
+		GenType:     job.GenType,
+		Question:    job.Question,
+		Status:      job.Status,
+		ResultModel: job.ResultModel,
+		ErrorMsg:    job.ErrorMsg,
+		StartTime:   job.StartTime,
+		FinishTime:  job.FinishTime,
+		CreateTime:  job.CreateTime,
+	}
+	if job.Status == string(consts.CodeJobStatusQueued) {
+		jobVO.QueuePosition = s.queuePosition(job.ID)
+	}
+	return jobVO
+}
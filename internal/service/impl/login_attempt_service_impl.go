@@ -0,0 +1,257 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"aicode/config"
+	"aicode/constant"
+	"aicode/internal/exception"
+	"aicode/internal/mapper"
+	"aicode/internal/model/entity"
+	"aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// loginAttemptListLimit 管理员查询登录记录的最大条数
+const loginAttemptListLimit = 100
+
+// 登录失败计数的 key 前缀
+const (
+	loginCounterAccountPrefix = "account:"
+	loginCounterIPPrefix      = "ip:"
+)
+
+// LoginAttemptServiceImpl 登录防暴力破解服务实现，失败次数保存在 security.login.store 配置的计数存储中，
+// login_attempt 表只用于审计
+type LoginAttemptServiceImpl struct {
+	loginCounterMapper mapper.LoginCounterMapper
+	loginAttemptMapper *mapper.LoginAttemptMapper
+	userMapper         *mapper.UserMapper
+}
+
+// NewLoginAttemptService 创建登录防暴力破解服务实例
+func NewLoginAttemptService(loginCounterMapper mapper.LoginCounterMapper, loginAttemptMapper *mapper.LoginAttemptMapper,
+	userMapper *mapper.UserMapper) service.LoginAttemptService {
+	return &LoginAttemptServiceImpl{
+		loginCounterMapper: loginCounterMapper,
+		loginAttemptMapper: loginAttemptMapper,
+		userMapper:         userMapper,
+	}
+}
+
+// CheckLogin 校验账号与 IP 当前是否允许尝试登录，允许时原子地计入本次尝试；并发请求按计数依次判断，
+// 不会同时通过校验。计数存储出错时拒绝登录，避免通过制造并发冲突或存储异常绕过限制
+func (s *LoginAttemptServiceImpl) CheckLogin(ctx context.Context, userAccount, clientIP, userAgent string) (int64, error) {
+	loginCfg := &config.GetConfig().Security.Login
+
+	// 1. 同一 IP 在时间窗口内失败次数过多时拒绝，限制针对大量账号的撞库
+	if clientIP != "" {
+		err := s.loginCounterMapper.Update(ctx, loginCounterIPPrefix+clientIP, loginCfg.GetWindow(),
+			func(counter *entity.LoginCounter) error {
+				now := time.Now()
+				if counter.Count >= int64(loginCfg.GetIPMaxFailures()) {
+					retryAfter := counter.LastTime.Add(loginCfg.GetWindow()).Sub(now)
+					return exception.NewBusinessErrorWithMessage(exception.TooManyRequest,
+						fmt.Sprintf("登录失败次数过多，请 %d 分钟后重试", ceilMinutes(retryAfter)))
+				}
+				counter.Count++
+				counter.LastTime = now
+				return nil
+			})
+		if err != nil {
+			if bizErr, ok := err.(*exception.BusinessError); ok {
+				s.save(userAccount, 0, clientIP, userAgent, constant.LoginAttemptBlocked, "ip_throttled")
+				return 0, bizErr
+			}
+			logrus.Errorf("计入 IP 登录尝试失败, ip=%s: %v", clientIP, err)
+			return 0, loginCounterError()
+		}
+	}
+
+	// 2. 账号连续失败次数达到阈值时锁定，未达到时需等待逐次翻倍的时间；
+	//    超过时间窗口没有新的尝试时失败次数清零，计数在锁定期间保留
+	var failures int64
+	var reason string
+	ttl := max(loginCfg.GetWindow(), loginCfg.GetLockDuration())
+	err := s.loginCounterMapper.Update(ctx, loginCounterAccountPrefix+userAccount, ttl,
+		func(counter *entity.LoginCounter) error {
+			now := time.Now()
+			switch {
+			case counter.Count >= int64(loginCfg.GetMaxFailures()):
+				if lockedUntil := counter.LastTime.Add(loginCfg.GetLockDuration()); now.Before(lockedUntil) {
+					reason = "locked"
+					return accountLockedError(lockedUntil.Sub(now))
+				}
+				counter.Count = 0
+			case now.Sub(counter.LastTime) >= loginCfg.GetWindow():
+				counter.Count = 0
+			}
+			if counter.Count > 0 {
+				if retryAfter := counter.LastTime.Add(loginCfg.GetDelay(int(counter.Count))).Sub(now); retryAfter > 0 {
+					reason = "throttled"
+					return exception.NewBusinessErrorWithMessage(exception.TooManyRequest,
+						fmt.Sprintf("登录失败次数过多，请 %d 秒后重试", int(math.Ceil(retryAfter.Seconds()))))
+				}
+			}
+			counter.Count++
+			counter.LastTime = now
+			failures = counter.Count
+			return nil
+		})
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); ok {
+			s.save(userAccount, 0, clientIP, userAgent, constant.LoginAttemptBlocked, reason)
+			return 0, bizErr
+		}
+		logrus.Errorf("计入账号登录尝试失败, account=%s: %v", userAccount, err)
+		return 0, loginCounterError()
+	}
+	return failures, nil
+}
+
+// RecordSuccess 记录登录成功，清零账号的失败次数，并从 IP 的失败次数中扣除本次尝试
+func (s *LoginAttemptServiceImpl) RecordSuccess(ctx context.Context, userAccount string, userId int64,
+	clientIP, userAgent string) {
+	s.save(userAccount, userId, clientIP, userAgent, constant.LoginAttemptSuccess, "")
+	if err := s.loginCounterMapper.Delete(ctx, loginCounterAccountPrefix+userAccount); err != nil {
+		logrus.Errorf("清零账号登录失败次数失败, account=%s: %v", userAccount, err)
+	}
+	if clientIP == "" {
+		return
+	}
+	err := s.loginCounterMapper.Update(ctx, loginCounterIPPrefix+clientIP, config.GetConfig().Security.Login.GetWindow(),
+		func(counter *entity.LoginCounter) error {
+			if counter.Count > 0 {
+				counter.Count--
+			}
+			return nil
+		})
+	if err != nil {
+		logrus.Errorf("扣除 IP 登录失败次数失败, ip=%s: %v", clientIP, err)
+	}
+}
+
+// RecordFailure 记录登录失败，本次尝试计入后账号达到锁定阈值时返回 AccountLocked
+func (s *LoginAttemptServiceImpl) RecordFailure(_ context.Context, userAccount string, userId int64,
+	clientIP, userAgent, reason string, failures int64) error {
+	s.save(userAccount, userId, clientIP, userAgent, constant.LoginAttemptFailed, reason)
+
+	loginCfg := &config.GetConfig().Security.Login
+	if failures >= int64(loginCfg.GetMaxFailures()) {
+		if failures == int64(loginCfg.GetMaxFailures()) {
+			logrus.Warnf("账号连续登录失败 %d 次，已锁定, account=%s, ip=%s", failures, userAccount, clientIP)
+		}
+		return accountLockedError(loginCfg.GetLockDuration())
+	}
+	return nil
+}
+
+// UnlockUser 解锁用户账号
+func (s *LoginAttemptServiceImpl) UnlockUser(ctx context.Context, userId int64) (bool, error) {
+	if userId <= 0 {
+		return false, exception.NewBusinessErrorFromCode(exception.ParamsError)
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return false, err
+	}
+	user, err := s.getUser(userId)
+	if err != nil {
+		return false, err
+	}
+	if err := s.loginCounterMapper.Delete(ctx, loginCounterAccountPrefix+user.UserAccount); err != nil {
+		logrus.Errorf("清零账号登录失败次数失败, account=%s: %v", user.UserAccount, err)
+		return false, exception.NewBusinessErrorWithMessage(exception.OperationError, "解锁账号失败")
+	}
+	err = s.loginAttemptMapper.Save(&entity.LoginAttempt{
+		UserAccount: user.UserAccount,
+		UserID:      user.ID,
+		Result:      constant.LoginAttemptUnlocked,
+		OperatorID:  loginUser.ID,
+	})
+	if err != nil {
+		return false, exception.NewBusinessErrorWithMessage(exception.OperationError, "解锁账号失败，数据库错误")
+	}
+	logrus.Infof("管理员解锁账号, userId=%d, operatorId=%d", user.ID, loginUser.ID)
+	return true, nil
+}
+
+// ListLoginAttempts 查询用户最近的登录记录
+func (s *LoginAttemptServiceImpl) ListLoginAttempts(_ context.Context, userId int64) ([]vo.LoginAttemptVO, error) {
+	if userId <= 0 {
+		return nil, exception.NewBusinessErrorFromCode(exception.ParamsError)
+	}
+	user, err := s.getUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.loginAttemptMapper.ListByAccount(user.UserAccount, loginAttemptListLimit)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询登录记录失败")
+	}
+	attemptVOList := make([]vo.LoginAttemptVO, 0, len(attempts))
+	for _, attempt := range attempts {
+		attemptVOList = append(attemptVOList, vo.LoginAttemptVO{
+			ID:          attempt.ID,
+			UserAccount: attempt.UserAccount,
+			ClientIP:    attempt.ClientIP,
+			UserAgent:   attempt.UserAgent,
+			Result:      attempt.Result,
+			Reason:      attempt.Reason,
+			OperatorID:  attempt.OperatorID,
+			CreateTime:  attempt.CreateTime,
+		})
+	}
+	return attemptVOList, nil
+}
+
+// getUser 查询用户，不存在时返回 NotFoundError
+func (s *LoginAttemptServiceImpl) getUser(userId int64) (*entity.User, error) {
+	user, err := s.userMapper.GetById(userId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exception.NewBusinessErrorFromCode(exception.NotFoundError)
+		}
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询用户失败")
+	}
+	return user, nil
+}
+
+// save 保存登录记录，超长字段截断（含省略号）到列宽以内；保存失败只记录日志，不影响登录流程
+func (s *LoginAttemptServiceImpl) save(userAccount string, userId int64, clientIP, userAgent string,
+	result int, reason string) {
+	err := s.loginAttemptMapper.Save(&entity.LoginAttempt{
+		UserAccount: truncateRunes(userAccount, 253),
+		UserID:      userId,
+		ClientIP:    truncateRunes(clientIP, 61),
+		UserAgent:   truncateRunes(userAgent, 509),
+		Result:      result,
+		Reason:      reason,
+	})
+	if err != nil {
+		logrus.Errorf("保存登录记录失败, account=%s: %v", userAccount, err)
+	}
+}
+
+// accountLockedError 返回账号锁定错误，提示剩余锁定时间
+func accountLockedError(remaining time.Duration) error {
+	return exception.NewBusinessErrorWithMessage(exception.AccountLocked,
+		fmt.Sprintf("登录失败次数过多，账号已锁定，请 %d 分钟后重试或联系管理员解锁", ceilMinutes(remaining)))
+}
+
+// loginCounterError 返回计数存储出错时的登录错误
+func loginCounterError() error {
+	return exception.NewBusinessErrorWithMessage(exception.SystemError, "登录校验失败，请稍后重试")
+}
+
+// ceilMinutes 将时长向上取整为分钟数
+func ceilMinutes(d time.Duration) int {
+	return int(math.Ceil(d.Minutes()))
+}
@@ -0,0 +1,24 @@
+package impl
+
+import (
+	"context"
+
+	"aicode/constant"
+	"aicode/internal/exception"
+	"aicode/internal/model/entity"
+	"aicode/internal/model/enums"
+)
+
+// getLoginUserFromCtx 从 request context 中读取 AuthMiddleware 写入的登录用户
+func getLoginUserFromCtx(ctx context.Context) (*entity.User, error) {
+	loginUser, ok := ctx.Value(constant.UserLoginState).(*entity.User)
+	if !ok || loginUser == nil || loginUser.ID == 0 {
+		return nil, exception.NewBusinessErrorFromCode(exception.NotLoginError)
+	}
+	return loginUser, nil
+}
+
+// isAdmin 判断用户是否为管理员
+func isAdmin(user *entity.User) bool {
+	return user != nil && user.UserRole == enums.ADMIN.Value()
+}
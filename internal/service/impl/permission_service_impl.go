@@ -0,0 +1,70 @@
+package impl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"aicode/internal/mapper"
+	"aicode/internal/service"
+)
+
+// permissionCacheTTL 角色权限缓存的有效期，修改角色权限后最迟在该时间后生效
+const permissionCacheTTL = time.Minute
+
+// PermissionServiceImpl 角色权限服务实现，角色权限关联整体缓存在内存中
+type PermissionServiceImpl struct {
+	rolePermissionMapper *mapper.RolePermissionMapper
+
+	mu        sync.RWMutex
+	roles     map[string]map[string]struct{}
+	expiresAt time.Time
+}
+
+// NewPermissionService 创建角色权限服务实例
+func NewPermissionService(rolePermissionMapper *mapper.RolePermissionMapper) service.PermissionService {
+	return &PermissionServiceImpl{
+		rolePermissionMapper: rolePermissionMapper,
+	}
+}
+
+// HasPermission 判断角色是否拥有权限
+func (s *PermissionServiceImpl) HasPermission(_ context.Context, role, permission string) (bool, error) {
+	roles, err := s.loadRoles()
+	if err != nil {
+		return false, err
+	}
+	_, ok := roles[role][permission]
+	return ok, nil
+}
+
+// loadRoles 返回角色到权限集合的映射，缓存过期时重新加载
+func (s *PermissionServiceImpl) loadRoles() (map[string]map[string]struct{}, error) {
+	s.mu.RLock()
+	if s.roles != nil && time.Now().Before(s.expiresAt) {
+		roles := s.roles
+		s.mu.RUnlock()
+		return roles, nil
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.roles != nil && time.Now().Before(s.expiresAt) {
+		return s.roles, nil
+	}
+	rolePermissions, err := s.rolePermissionMapper.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[string]map[string]struct{})
+	for _, rolePermission := range rolePermissions {
+		if roles[rolePermission.RoleKey] == nil {
+			roles[rolePermission.RoleKey] = make(map[string]struct{})
+		}
+		roles[rolePermission.RoleKey][rolePermission.PermissionKey] = struct{}{}
+	}
+	s.roles = roles
+	s.expiresAt = time.Now().Add(permissionCacheTTL)
+	return roles, nil
+}
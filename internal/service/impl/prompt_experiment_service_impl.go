@@ -0,0 +1,272 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+
+	"aicode/constant"
+	"aicode/internal/exception"
+	"aicode/internal/mapper"
+	"aicode/internal/model/dto/promptexperiment"
+	"aicode/internal/model/entity"
+	"aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// PromptExperimentServiceImpl 提示词实验服务实现
+type PromptExperimentServiceImpl struct {
+	promptExperimentMapper *mapper.PromptExperimentMapper
+	promptTemplateService  service.PromptTemplateService
+}
+
+// NewPromptExperimentService 创建提示词实验服务实例
+func NewPromptExperimentService(promptExperimentMapper *mapper.PromptExperimentMapper,
+	promptTemplateService service.PromptTemplateService) service.PromptExperimentService {
+	return &PromptExperimentServiceImpl{
+		promptExperimentMapper: promptExperimentMapper,
+		promptTemplateService:  promptTemplateService,
+	}
+}
+
+// CreateExperiment 创建并启动实验
+func (s *PromptExperimentServiceImpl) CreateExperiment(ctx context.Context,
+	req *promptexperiment.PromptExperimentCreateRequest) (int64, error) {
+	if req == nil || len(req.Variants) < 2 {
+		return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "实验至少需要两个分组")
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	variants := make([]entity.PromptExperimentVariant, 0, len(req.Variants))
+	seen := make(map[int]bool, len(req.Variants))
+	for _, variant := range req.Variants {
+		if variant.Version <= 0 || variant.Weight <= 0 {
+			return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "分组的版本号与权重必须为正数")
+		}
+		if seen[variant.Version] {
+			return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError,
+				fmt.Sprintf("模板版本 %d 重复", variant.Version))
+		}
+		seen[variant.Version] = true
+		// 校验版本存在
+		if _, err := s.promptTemplateService.GetTemplate(ctx, req.TemplateName, variant.Version); err != nil {
+			return 0, err
+		}
+		variants = append(variants, entity.PromptExperimentVariant{Version: variant.Version, Weight: variant.Weight})
+	}
+	if _, err := s.promptExperimentMapper.GetRunningByTemplateName(req.TemplateName); err == nil {
+		return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "该模板已有进行中的实验，请先停止")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询实验失败")
+	}
+
+	experiment := &entity.PromptExperiment{
+		TemplateName: req.TemplateName,
+		Variants:     variants,
+		Description:  req.Description,
+		Status:       constant.PromptExperimentRunning,
+		UserID:       loginUser.ID,
+	}
+	if err := s.promptExperimentMapper.Save(experiment); err != nil {
+		return 0, exception.NewBusinessErrorWithMessage(exception.OperationError, "创建实验失败，数据库错误")
+	}
+	return experiment.ID, nil
+}
+
+// StopExperiment 停止实验
+func (s *PromptExperimentServiceImpl) StopExperiment(ctx context.Context, id int64) (bool, error) {
+	experiment, err := s.getExperiment(id)
+	if err != nil {
+		return false, err
+	}
+	if experiment.Status != constant.PromptExperimentRunning {
+		return false, exception.NewBusinessErrorWithMessage(exception.ParamsError, "实验已停止")
+	}
+	if err := s.promptExperimentMapper.Stop(id); err != nil {
+		return false, exception.NewBusinessErrorWithMessage(exception.OperationError, "停止实验失败，数据库错误")
+	}
+	return true, nil
+}
+
+// ListExperiments 按创建时间倒序查询全部实验
+func (s *PromptExperimentServiceImpl) ListExperiments(ctx context.Context) ([]vo.PromptExperimentVO, error) {
+	experiments, err := s.promptExperimentMapper.List()
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询实验列表失败")
+	}
+	experimentVOList := make([]vo.PromptExperimentVO, 0, len(experiments))
+	for i := range experiments {
+		experimentVOList = append(experimentVOList, getPromptExperimentVO(&experiments[i]))
+	}
+	return experimentVOList, nil
+}
+
+// GetStats 查询实验按分组汇总的结果
+func (s *PromptExperimentServiceImpl) GetStats(ctx context.Context, id int64) (*vo.PromptExperimentStatsVO, error) {
+	experiment, err := s.getExperiment(id)
+	if err != nil {
+		return nil, err
+	}
+	sums, err := s.promptExperimentMapper.SumByVersion(id)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询实验结果失败")
+	}
+	sumByVersion := make(map[int]mapper.ExperimentVariantSum, len(sums))
+	for _, sum := range sums {
+		sumByVersion[sum.TemplateVersion] = sum
+	}
+
+	statsVO := &vo.PromptExperimentStatsVO{
+		Experiment: getPromptExperimentVO(experiment),
+		Variants:   make([]vo.PromptVariantStatsVO, 0, len(experiment.Variants)),
+	}
+	for _, variant := range experiment.Variants {
+		sum := sumByVersion[variant.Version]
+		variantStats := vo.PromptVariantStatsVO{
+			Version:    variant.Version,
+			Weight:     variant.Weight,
+			Total:      sum.Total,
+			Success:    sum.Success,
+			Failed:     sum.Failed,
+			Retries:    sum.Retries,
+			RolledBack: sum.RolledBack,
+		}
+		if finished := sum.Success + sum.Failed; finished > 0 {
+			variantStats.SuccessRate = float64(sum.Success) / float64(finished)
+		}
+		if sum.Total > 0 {
+			variantStats.AvgRetries = float64(sum.Retries) / float64(sum.Total)
+		}
+		if sum.Success > 0 {
+			variantStats.RollbackRate = float64(sum.RolledBack) / float64(sum.Success)
+		}
+		statsVO.Variants = append(statsVO.Variants, variantStats)
+	}
+	return statsVO, nil
+}
+
+// Assign 按登录用户 id 分配实验分组并创建实验记录
+func (s *PromptExperimentServiceImpl) Assign(ctx context.Context, name string, appId int64,
+	model string) (*vo.PromptAssignment, error) {
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	experiment, err := s.promptExperimentMapper.GetRunningByTemplateName(name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询实验失败")
+	}
+	version := pickExperimentVariant(experiment.Variants, experiment.ID, loginUser.ID)
+	if version == 0 {
+		return nil, nil
+	}
+	record := &entity.PromptExperimentRecord{
+		ExperimentID:    experiment.ID,
+		TemplateVersion: version,
+		AppID:           appId,
+		UserID:          loginUser.ID,
+		GenType:         name,
+		Model:           model,
+		Status:          constant.ExperimentRecordPending,
+	}
+	if err := s.promptExperimentMapper.SaveRecord(record); err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.OperationError, "保存实验记录失败，数据库错误")
+	}
+	return &vo.PromptAssignment{
+		ExperimentID: experiment.ID,
+		RecordID:     record.ID,
+		Version:      version,
+	}, nil
+}
+
+// RecordRetry 记录一次输出被截断后的续写
+func (s *PromptExperimentServiceImpl) RecordRetry(ctx context.Context, recordId int64) {
+	if recordId == 0 {
+		return
+	}
+	if err := s.promptExperimentMapper.IncrRecordRetries(recordId); err != nil {
+		logrus.Errorf("记录实验续写次数失败, recordId=%d: %v", recordId, err)
+	}
+}
+
+// RecordOutcome 记录生成结果
+func (s *PromptExperimentServiceImpl) RecordOutcome(ctx context.Context, recordId int64, success bool,
+	appVersion int) {
+	if recordId == 0 {
+		return
+	}
+	status := constant.ExperimentRecordFailed
+	if success {
+		status = constant.ExperimentRecordSuccess
+	}
+	if err := s.promptExperimentMapper.FinishRecord(recordId, status, appVersion); err != nil {
+		logrus.Errorf("记录实验结果失败, recordId=%d: %v", recordId, err)
+	}
+}
+
+// RecordRollback 将回滚目标之后版本对应的实验记录标记为被回滚
+func (s *PromptExperimentServiceImpl) RecordRollback(ctx context.Context, appId int64, version int) {
+	if err := s.promptExperimentMapper.MarkRolledBack(appId, version); err != nil {
+		logrus.Errorf("记录实验回滚失败, appId=%d, version=%d: %v", appId, version, err)
+	}
+}
+
+// getExperiment 查询实验，不存在时返回 NotFoundError
+func (s *PromptExperimentServiceImpl) getExperiment(id int64) (*entity.PromptExperiment, error) {
+	experiment, err := s.promptExperimentMapper.GetById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exception.NewBusinessErrorWithMessage(exception.NotFoundError, "实验不存在")
+		}
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询实验失败")
+	}
+	return experiment, nil
+}
+
+// pickExperimentVariant 以 实验id、用户id 的哈希值按权重选择分组，同一用户在同一实验中始终分到同一组；
+// 没有有效分组时返回 0
+func pickExperimentVariant(variants []entity.PromptExperimentVariant, experimentId, userId int64) int {
+	total := 0
+	for _, variant := range variants {
+		total += variant.Weight
+	}
+	if total <= 0 {
+		return 0
+	}
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%d:%d", experimentId, userId)
+	bucket := int(h.Sum32() % uint32(total))
+	for _, variant := range variants {
+		if bucket < variant.Weight {
+			return variant.Version
+		}
+		bucket -= variant.Weight
+	}
+	return variants[len(variants)-1].Version
+}
+
+// getPromptExperimentVO 实体转换为实验信息
+func getPromptExperimentVO(experiment *entity.PromptExperiment) vo.PromptExperimentVO {
+	variants := make([]vo.PromptExperimentVariantVO, 0, len(experiment.Variants))
+	for _, variant := range experiment.Variants {
+		variants = append(variants, vo.PromptExperimentVariantVO{Version: variant.Version, Weight: variant.Weight})
+	}
+	return vo.PromptExperimentVO{
+		ID:           experiment.ID,
+		TemplateName: experiment.TemplateName,
+		Variants:     variants,
+		Description:  experiment.Description,
+		Running:      experiment.Status == constant.PromptExperimentRunning,
+		UserID:       experiment.UserID,
+		CreateTime:   experiment.CreateTime,
+	}
+}
@@ -0,0 +1,382 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"aicode/config"
+	"aicode/consts"
+	"aicode/internal/exception"
+	"aicode/internal/mapper"
+	"aicode/internal/model/dto/prompttemplate"
+	"aicode/internal/model/entity"
+	"aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// promptCacheTTL 模板缓存有效期：本实例修改模板时立即失效，其他实例的修改在有效期后生效
+const promptCacheTTL = time.Minute
+
+// promptTemplateNameRe 模板名称只允许小写字母、数字与下划线
+var promptTemplateNameRe = regexp.MustCompile(`^[a-z0-9_]{1,64}$`)
+
+// cachedPromptTemplate 已解析的模板生效版本
+type cachedPromptTemplate struct {
+	version  int
+	tmpl     *template.Template
+	loadedAt time.Time
+}
+
+// PromptTemplateServiceImpl 提示词模板服务实现
+type PromptTemplateServiceImpl struct {
+	promptTemplateMapper *mapper.PromptTemplateMapper
+
+	mu    sync.RWMutex
+	cache map[string]*cachedPromptTemplate
+	// versionCache 按 名称、版本号 缓存已解析的指定版本，版本内容不会修改，只在模板删除时失效
+	versionCache map[string]map[int]*template.Template
+}
+
+// NewPromptTemplateService 创建提示词模板服务实例
+func NewPromptTemplateService(promptTemplateMapper *mapper.PromptTemplateMapper) service.PromptTemplateService {
+	return &PromptTemplateServiceImpl{
+		promptTemplateMapper: promptTemplateMapper,
+		cache:                make(map[string]*cachedPromptTemplate),
+		versionCache:         make(map[string]map[int]*template.Template),
+	}
+}
+
+// AddTemplate 创建模板
+func (s *PromptTemplateServiceImpl) AddTemplate(ctx context.Context,
+	req *prompttemplate.PromptTemplateAddRequest) (int, error) {
+	if req == nil {
+		return 0, exception.NewBusinessErrorFromCode(exception.ParamsError)
+	}
+	if _, err := s.promptTemplateMapper.GetActiveByName(req.Name); err == nil {
+		return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "模板已存在，请修改模板生成新版本")
+	}
+	return s.saveVersion(ctx, req.Name, req.Content, req.Description)
+}
+
+// UpdateTemplate 将修改保存为模板的新版本并立即生效
+func (s *PromptTemplateServiceImpl) UpdateTemplate(ctx context.Context,
+	req *prompttemplate.PromptTemplateUpdateRequest) (int, error) {
+	if req == nil {
+		return 0, exception.NewBusinessErrorFromCode(exception.ParamsError)
+	}
+	if _, err := s.getActive(req.Name); err != nil {
+		return 0, err
+	}
+	return s.saveVersion(ctx, req.Name, req.Content, req.Description)
+}
+
+// ActivateVersion 将模板的指定版本设为生效版本
+func (s *PromptTemplateServiceImpl) ActivateVersion(ctx context.Context, name string, version int) (bool, error) {
+	if _, err := s.getVersion(name, version); err != nil {
+		return false, err
+	}
+	if err := s.promptTemplateMapper.Activate(name, version); err != nil {
+		return false, exception.NewBusinessErrorWithMessage(exception.OperationError, "切换模板版本失败，数据库错误")
+	}
+	s.invalidate(name)
+	return true, nil
+}
+
+// DeleteTemplate 删除模板的全部版本
+func (s *PromptTemplateServiceImpl) DeleteTemplate(ctx context.Context, name string) (bool, error) {
+	if _, err := s.getActive(name); err != nil {
+		return false, err
+	}
+	if err := s.promptTemplateMapper.DeleteByName(name); err != nil {
+		return false, exception.NewBusinessErrorWithMessage(exception.OperationError, "删除模板失败，数据库错误")
+	}
+	s.invalidateAll(name)
+	return true, nil
+}
+
+// GetTemplate 查询模板的指定版本
+func (s *PromptTemplateServiceImpl) GetTemplate(ctx context.Context, name string,
+	version int) (*vo.PromptTemplateVO, error) {
+	var promptTemplate *entity.PromptTemplate
+	var err error
+	if version > 0 {
+		promptTemplate, err = s.getVersion(name, version)
+	} else {
+		promptTemplate, err = s.getActive(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	templateVO := getPromptTemplateVO(promptTemplate)
+	return &templateVO, nil
+}
+
+// ListTemplates 查询全部模板的生效版本
+func (s *PromptTemplateServiceImpl) ListTemplates(ctx context.Context) ([]vo.PromptTemplateVO, error) {
+	templates, err := s.promptTemplateMapper.ListActive()
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询模板列表失败")
+	}
+	return getPromptTemplateVOList(templates), nil
+}
+
+// ListVersions 按版本号倒序查询模板的全部版本
+func (s *PromptTemplateServiceImpl) ListVersions(ctx context.Context, name string) ([]vo.PromptTemplateVO, error) {
+	templates, err := s.promptTemplateMapper.ListVersionsByName(name)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询模板版本失败")
+	}
+	return getPromptTemplateVOList(templates), nil
+}
+
+// Render 使用模板的生效版本渲染提示词
+func (s *PromptTemplateServiceImpl) Render(ctx context.Context, name string,
+	vars *vo.PromptVariables) (string, error) {
+	cached, err := s.loadCached(name)
+	if err != nil {
+		return "", err
+	}
+	return renderPromptTemplate(cached.tmpl, name, cached.version, vars)
+}
+
+// RenderVersion 使用模板的指定版本渲染提示词
+func (s *PromptTemplateServiceImpl) RenderVersion(ctx context.Context, name string, version int,
+	vars *vo.PromptVariables) (string, error) {
+	s.mu.RLock()
+	tmpl, ok := s.versionCache[name][version]
+	s.mu.RUnlock()
+	if !ok {
+		promptTemplate, err := s.getVersion(name, version)
+		if err != nil {
+			return "", err
+		}
+		if tmpl, err = parsePromptTemplate(name, promptTemplate.Content); err != nil {
+			logrus.Errorf("解析提示词模板失败, name=%s, version=%d: %v", name, version, err)
+			return "", exception.NewBusinessErrorWithMessage(exception.SystemError,
+				fmt.Sprintf("提示词模板 %s 语法错误", name))
+		}
+		s.mu.Lock()
+		if s.versionCache[name] == nil {
+			s.versionCache[name] = make(map[int]*template.Template)
+		}
+		s.versionCache[name][version] = tmpl
+		s.mu.Unlock()
+	}
+	return renderPromptTemplate(tmpl, name, version, vars)
+}
+
+// saveVersion 校验模板语法后保存为新的生效版本
+func (s *PromptTemplateServiceImpl) saveVersion(ctx context.Context, name, content, description string) (int, error) {
+	if !promptTemplateNameRe.MatchString(name) {
+		return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "模板名称只能包含小写字母、数字与下划线")
+	}
+	if strings.TrimSpace(content) == "" {
+		return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "模板内容不能为空")
+	}
+	if _, err := parsePromptTemplate(name, content); err != nil {
+		return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "模板语法错误: "+err.Error())
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	maxVersion, err := s.promptTemplateMapper.GetMaxVersion(name)
+	if err != nil {
+		return 0, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询模板版本失败")
+	}
+	promptTemplate := &entity.PromptTemplate{
+		Name:        name,
+		Version:     maxVersion + 1,
+		Content:     content,
+		Description: description,
+		UserID:      loginUser.ID,
+	}
+	if err := s.promptTemplateMapper.SaveActive(promptTemplate); err != nil {
+		return 0, exception.NewBusinessErrorWithMessage(exception.OperationError, "保存模板失败，数据库错误")
+	}
+	s.invalidate(name)
+	return promptTemplate.Version, nil
+}
+
+// loadCached 读取模板的生效版本，缓存未命中或过期时从数据库加载并解析
+func (s *PromptTemplateServiceImpl) loadCached(name string) (*cachedPromptTemplate, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[name]
+	s.mu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < promptCacheTTL {
+		return cached, nil
+	}
+
+	promptTemplate, err := s.getActive(name)
+	if err != nil {
+		if bizErr, ok := err.(*exception.BusinessError); !ok || bizErr.Code() != exception.NotFoundError.Code() {
+			return nil, err
+		}
+		if promptTemplate, err = s.seedFromFile(name); err != nil {
+			return nil, err
+		}
+	}
+	tmpl, err := parsePromptTemplate(name, promptTemplate.Content)
+	if err != nil {
+		logrus.Errorf("解析提示词模板失败, name=%s, version=%d: %v", name, promptTemplate.Version, err)
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError,
+			fmt.Sprintf("提示词模板 %s 语法错误", name))
+	}
+	cached = &cachedPromptTemplate{version: promptTemplate.Version, tmpl: tmpl, loadedAt: time.Now()}
+	s.mu.Lock()
+	s.cache[name] = cached
+	s.mu.Unlock()
+	return cached, nil
+}
+
+// seedFromFile 从未创建过的代码生成模板，从 ai.system_prompt_dir 中配置的文件导入为版本 1；
+// 模板被删除过或没有对应文件时返回模板缺失异常
+func (s *PromptTemplateServiceImpl) seedFromFile(name string) (*entity.PromptTemplate, error) {
+	missing := exception.NewBusinessErrorWithMessage(exception.SystemError,
+		fmt.Sprintf("缺少系统提示词模板: %s", name))
+	maxVersion, err := s.promptTemplateMapper.GetMaxVersion(name)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询模板版本失败")
+	}
+	path := promptTemplateSeedFile(name)
+	if maxVersion > 0 || path == "" {
+		return nil, missing
+	}
+	content, err := os.ReadFile(path)
+	if err != nil || strings.TrimSpace(string(content)) == "" {
+		logrus.Errorf("读取系统提示词文件失败, name=%s, path=%s: %v", name, path, err)
+		return nil, missing
+	}
+	promptTemplate := &entity.PromptTemplate{
+		Name:        name,
+		Version:     1,
+		Content:     string(content),
+		Description: "从文件导入: " + path,
+	}
+	if err := s.promptTemplateMapper.SaveActive(promptTemplate); err != nil {
+		// 并发导入时唯一索引冲突，读取已导入的版本
+		if existing, getErr := s.promptTemplateMapper.GetActiveByName(name); getErr == nil {
+			return existing, nil
+		}
+		return nil, exception.NewBusinessErrorWithMessage(exception.OperationError, "导入提示词模板失败，数据库错误")
+	}
+	logrus.Infof("已从文件导入提示词模板, name=%s, path=%s", name, path)
+	return promptTemplate, nil
+}
+
+// getActive 查询模板的生效版本，不存在时返回 NotFoundError
+func (s *PromptTemplateServiceImpl) getActive(name string) (*entity.PromptTemplate, error) {
+	promptTemplate, err := s.promptTemplateMapper.GetActiveByName(name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exception.NewBusinessErrorWithMessage(exception.NotFoundError, "模板不存在")
+		}
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询模板失败")
+	}
+	return promptTemplate, nil
+}
+
+// getVersion 查询模板的指定版本，不存在时返回 NotFoundError
+func (s *PromptTemplateServiceImpl) getVersion(name string, version int) (*entity.PromptTemplate, error) {
+	promptTemplate, err := s.promptTemplateMapper.GetByNameAndVersion(name, version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exception.NewBusinessErrorWithMessage(exception.NotFoundError,
+				fmt.Sprintf("模板版本 %d 不存在", version))
+		}
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询模板失败")
+	}
+	return promptTemplate, nil
+}
+
+// invalidate 使模板缓存失效
+func (s *PromptTemplateServiceImpl) invalidate(name string) {
+	s.mu.Lock()
+	delete(s.cache, name)
+	s.mu.Unlock()
+}
+
+// invalidateAll 使模板生效版本与指定版本的缓存全部失效
+func (s *PromptTemplateServiceImpl) invalidateAll(name string) {
+	s.mu.Lock()
+	delete(s.cache, name)
+	delete(s.versionCache, name)
+	s.mu.Unlock()
+}
+
+// renderPromptTemplate 执行已解析的模板，渲染结果为空时视为错误
+func renderPromptTemplate(tmpl *template.Template, name string, version int,
+	vars *vo.PromptVariables) (string, error) {
+	if vars == nil {
+		vars = &vo.PromptVariables{}
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, vars); err != nil {
+		logrus.Errorf("渲染提示词模板失败, name=%s, version=%d: %v", name, version, err)
+		return "", exception.NewBusinessErrorWithMessage(exception.SystemError,
+			fmt.Sprintf("渲染提示词模板 %s 失败", name))
+	}
+	prompt := b.String()
+	if strings.TrimSpace(prompt) == "" {
+		return "", exception.NewBusinessErrorWithMessage(exception.SystemError,
+			fmt.Sprintf("提示词模板 %s 内容为空", name))
+	}
+	return prompt, nil
+}
+
+// parsePromptTemplate 解析模板，引用未定义的变量视为错误
+func parsePromptTemplate(name, content string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Parse(content)
+}
+
+// promptTemplateSeedFile 代码生成模板对应的初始提示词文件
+func promptTemplateSeedFile(name string) string {
+	dirs := config.GetConfig().AI.SystemPromptDir
+	switch consts.CodeGenarateType(name) {
+	case consts.CodeGenarateTypeSingle:
+		return dirs.SingalGenerate
+	case consts.CodeGenarateTypeMulti:
+		return dirs.MultiGenerate
+	case consts.CodeGenarateTypeVueProject:
+		return dirs.VueProject
+	case consts.CodeGenarateTypeAgent:
+		return dirs.Agent
+	case consts.CodeGenarateTypeEdit:
+		return dirs.Edit
+	default:
+		return ""
+	}
+}
+
+// getPromptTemplateVO 实体转换为模板信息
+func getPromptTemplateVO(promptTemplate *entity.PromptTemplate) vo.PromptTemplateVO {
+	return vo.PromptTemplateVO{
+		ID:          promptTemplate.ID,
+		Name:        promptTemplate.Name,
+		Version:     promptTemplate.Version,
+		Content:     promptTemplate.Content,
+		Description: promptTemplate.Description,
+		IsActive:    promptTemplate.IsActive == 1,
+		UserID:      promptTemplate.UserID,
+		CreateTime:  promptTemplate.CreateTime,
+	}
+}
+
+// getPromptTemplateVOList 批量转换模板信息
+func getPromptTemplateVOList(templates []entity.PromptTemplate) []vo.PromptTemplateVO {
+	templateVOList := make([]vo.PromptTemplateVO, 0, len(templates))
+	for i := range templates {
+		templateVOList = append(templateVOList, getPromptTemplateVO(&templates[i]))
+	}
+	return templateVOList
+}
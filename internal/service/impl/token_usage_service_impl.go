@@ -0,0 +1,221 @@
+package impl
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"aicode/config"
+	"aicode/consts"
+	"aicode/internal/exception"
+	"aicode/internal/mapper"
+	"aicode/internal/model/entity"
+	"aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/sirupsen/logrus"
+)
+
+// TokenUsageServiceImpl Token 用量服务实现
+type TokenUsageServiceImpl struct {
+	tokenUsageMapper *mapper.TokenUsageMapper
+}
+
+// NewTokenUsageService 创建Token 用量服务实例
+func NewTokenUsageService(tokenUsageMapper *mapper.TokenUsageMapper) service.TokenUsageService {
+	return &TokenUsageServiceImpl{
+		tokenUsageMapper: tokenUsageMapper,
+	}
+}
+
+// CheckQuota 校验当前登录用户的日/月 token 用量是否已超出配额
+func (s *TokenUsageServiceImpl) CheckQuota(ctx context.Context) error {
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	quota := config.GetConfig().AI.GetQuota(loginUser.UserRole)
+	dayStart, monthStart := usagePeriodStart(time.Now())
+	if quota.DailyTokens > 0 {
+		used, err := s.tokenUsageMapper.SumTotalByUserSince(loginUser.ID, dayStart)
+		if err != nil {
+			return exception.NewBusinessErrorWithMessage(exception.OperationError, "查询用量失败")
+		}
+		if used >= quota.DailyTokens {
+			return exception.NewBusinessErrorWithMessage(exception.QuotaExceeded, "今日 Token 用量已超出配额")
+		}
+	}
+	if quota.MonthlyTokens > 0 {
+		used, err := s.tokenUsageMapper.SumTotalByUserSince(loginUser.ID, monthStart)
+		if err != nil {
+			return exception.NewBusinessErrorWithMessage(exception.OperationError, "查询用量失败")
+		}
+		if used >= quota.MonthlyTokens {
+			return exception.NewBusinessErrorWithMessage(exception.QuotaExceeded, "本月 Token 用量已超出配额")
+		}
+	}
+	return nil
+}
+
+// RecordUsage 记录一次模型调用的 token 用量，模型未返回用量时按 prompt 与回答内容估算，
+// 失败仅记录日志不影响调用结果
+func (s *TokenUsageServiceImpl) RecordUsage(ctx context.Context, appId int64, model string,
+	bizType consts.UsageBizType, prompt []*schema.Message, msg *schema.Message) {
+	if msg == nil {
+		return
+	}
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		logrus.Errorf("记录 token 用量失败: %v", err)
+		return
+	}
+	var usage *schema.TokenUsage
+	if msg.ResponseMeta != nil && msg.ResponseMeta.Usage != nil {
+		usage = msg.ResponseMeta.Usage
+	} else {
+		usage = estimateUsage(prompt, msg)
+		logrus.Warnf("模型 %s 未返回 token 用量，按内容估算: prompt=%d, completion=%d",
+			model, usage.PromptTokens, usage.CompletionTokens)
+	}
+	record := &entity.TokenUsage{
+		UserID:           loginUser.ID,
+		AppID:            appId,
+		Model:            model,
+		BizType:          string(bizType),
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+	if record.TotalTokens == 0 {
+		record.TotalTokens = record.PromptTokens + record.CompletionTokens
+	}
+	if err := s.tokenUsageMapper.Save(record); err != nil {
+		logrus.Errorf("记录 token 用量失败: %v", err)
+	}
+}
+
+// RecordStreamUsage 读取流式回答并记录 token 用量
+// 流被取消、超时或断开时按已收到的内容记录；模型未返回用量时按 prompt 与回答内容估算
+func (s *TokenUsageServiceImpl) RecordStreamUsage(ctx context.Context, appId int64, model string,
+	bizType consts.UsageBizType, prompt []*schema.Message, stream *schema.StreamReader[*schema.Message]) {
+	defer stream.Close()
+
+	chunks := make([]*schema.Message, 0, 64)
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				logrus.Warnf("流式回答异常结束，按已收到的 %d 个分片记录 token 用量: %v", len(chunks), err)
+			}
+			break
+		}
+		if msg != nil {
+			chunks = append(chunks, msg)
+		}
+	}
+	if len(chunks) == 0 {
+		return
+	}
+	msg, err := schema.ConcatMessages(chunks)
+	if err != nil {
+		logrus.Errorf("合并流式回答失败，按分片内容估算 token 用量: %v", err)
+		msg = concatChunkContent(chunks)
+	}
+	s.RecordUsage(ctx, appId, model, bizType, prompt, msg)
+}
+
+// GetUsageSummary 获取当前登录用户今日、本月的用量与配额
+func (s *TokenUsageServiceImpl) GetUsageSummary(ctx context.Context) (*vo.TokenUsageSummaryVO, error) {
+	loginUser, err := getLoginUserFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	quota := config.GetConfig().AI.GetQuota(loginUser.UserRole)
+	dayStart, monthStart := usagePeriodStart(time.Now())
+
+	dailyUsed, err := s.tokenUsageMapper.SumTotalByUserSince(loginUser.ID, dayStart)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.OperationError, "查询用量失败")
+	}
+	sums, err := s.tokenUsageMapper.SumByModelSince(loginUser.ID, monthStart)
+	if err != nil {
+		return nil, exception.NewBusinessErrorWithMessage(exception.OperationError, "查询用量失败")
+	}
+
+	summary := &vo.TokenUsageSummaryVO{
+		DailyUsed:    dailyUsed,
+		DailyQuota:   quota.DailyTokens,
+		MonthlyQuota: quota.MonthlyTokens,
+		Models:       make([]vo.ModelUsageVO, 0, len(sums)),
+	}
+	for _, sum := range sums {
+		summary.MonthlyUsed += sum.TotalTokens
+		summary.Models = append(summary.Models, vo.ModelUsageVO{
+			Model:            sum.Model,
+			PromptTokens:     sum.PromptTokens,
+			CompletionTokens: sum.CompletionTokens,
+			TotalTokens:      sum.TotalTokens,
+		})
+	}
+	return summary, nil
+}
+
+// concatChunkContent 拼接分片的文本内容，用于无法合并分片时估算用量
+func concatChunkContent(chunks []*schema.Message) *schema.Message {
+	var content strings.Builder
+	for _, chunk := range chunks {
+		content.WriteString(chunk.Content)
+		content.WriteString(chunk.ReasoningContent)
+	}
+	return &schema.Message{Role: schema.Assistant, Content: content.String()}
+}
+
+// estimateUsage 按内容估算 token 用量
+func estimateUsage(prompt []*schema.Message, answer *schema.Message) *schema.TokenUsage {
+	usage := &schema.TokenUsage{}
+	for _, msg := range prompt {
+		usage.PromptTokens += estimateMessageTokens(msg)
+	}
+	usage.CompletionTokens = estimateMessageTokens(answer)
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	return usage
+}
+
+// estimateMessageTokens 估算单条消息的 token 数，包括文本、推理内容与工具调用参数
+func estimateMessageTokens(msg *schema.Message) int {
+	if msg == nil {
+		return 0
+	}
+	tokens := estimateTokens(msg.Content) + estimateTokens(msg.ReasoningContent)
+	for _, part := range msg.MultiContent {
+		tokens += estimateTokens(part.Text)
+	}
+	for _, call := range msg.ToolCalls {
+		tokens += estimateTokens(call.Function.Name) + estimateTokens(call.Function.Arguments)
+	}
+	return tokens
+}
+
+// estimateTokens 粗略估算文本的 token 数：中日韩字符每个约 1 个 token，其他字符约每 4 个计 1 个 token
+func estimateTokens(text string) int {
+	cjk, other := 0, 0
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+			unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// usagePeriodStart 返回 now 所在自然日与自然月的起始时间
+func usagePeriodStart(now time.Time) (time.Time, time.Time) {
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return dayStart, monthStart
+}
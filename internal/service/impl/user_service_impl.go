@@ -9,8 +9,8 @@ import (
 	"aicode/internal/model/enums"
 	"aicode/internal/model/vo"
 	"aicode/internal/service"
-	"crypto/md5"
-	"encoding/hex"
+	"aicode/security"
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -18,18 +18,24 @@ import (
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 // UserServiceImpl 用户服务实现
 type UserServiceImpl struct {
-	userMapper *mapper.UserMapper
+	userMapper          *mapper.UserMapper
+	passwordEncoder     *security.PasswordEncoder
+	loginAttemptService service.LoginAttemptService
 }
 
 // NewUserService 创建用户服务实例
-func NewUserService(userMapper *mapper.UserMapper) service.UserService {
+func NewUserService(userMapper *mapper.UserMapper, passwordEncoder *security.PasswordEncoder,
+	loginAttemptService service.LoginAttemptService) service.UserService {
 	return &UserServiceImpl{
-		userMapper: userMapper,
+		userMapper:          userMapper,
+		passwordEncoder:     passwordEncoder,
+		loginAttemptService: loginAttemptService,
 	}
 }
 
@@ -61,7 +67,13 @@ func (s *UserServiceImpl) UserRegister(userAccount, userPassword, checkPassword
 	}
 
 	// 3. 加密密码
-	encryptPassword := s.GetEncryptPassword(userPassword)
+	encryptPassword, err := s.GetEncryptPassword(userPassword)
+	if err != nil {
+		if errors.Is(err, security.ErrPasswordTooLong) {
+			return 0, exception.NewBusinessErrorWithMessage(exception.ParamsError, "密码长度过长")
+		}
+		return 0, exception.NewBusinessErrorWithMessage(exception.SystemError, "密码加密失败")
+	}
 
 	// 4. 创建用户，插入数据库
 	newUser := &entity.User{
@@ -106,33 +118,64 @@ func (s *UserServiceImpl) UserLogin(userAccount, userPassword string, c *gin.Con
 	if len(userAccount) < 4 {
 		return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError, "账号长度过短")
 	}
+	if len(userAccount) > 256 {
+		return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError, "账号长度过长")
+	}
 	if len(userPassword) < 8 {
 		return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError, "密码长度过短")
 	}
 
-	// 2. 加密
-	encryptPassword := s.GetEncryptPassword(userPassword)
+	// 2. 账号锁定或失败后等待时间未到时拒绝，不校验密码；允许时本次尝试先计入失败次数，登录成功后清零
+	// ClientIP 只采信 server.trusted_proxies 中代理转发的 X-Forwarded-For，客户端无法伪造 IP 绕过 IP 限制
+	clientIP, userAgent := c.ClientIP(), c.Request.UserAgent()
+	ctx := c.Request.Context()
+	failures, err := s.loginAttemptService.CheckLogin(ctx, userAccount, clientIP, userAgent)
+	if err != nil {
+		return nil, err
+	}
 
-	// 3. 查询用户是否存在
-	loginUser, err := s.userMapper.GetByAccountAndPassword(userAccount, encryptPassword)
+	// 3. 查询用户是否存在，账号不存在同样计入失败次数，避免通过锁定行为判断账号是否存在
+	loginUser, err := s.userMapper.GetByAccount(userAccount)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, exception.NewBusinessErrorWithMessage(exception.ParamsError, "用户不存在或密码错误")
+			return nil, s.loginFailed(ctx, userAccount, 0, clientIP, userAgent, "not_found", failures)
 		}
 		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询用户失败")
 	}
 
-	// 4. 将用户信息写入服务端 session
+	// 4. 校验密码，旧算法或旧参数生成的哈希在校验通过后重新生成
+	matched, needsRehash, err := s.passwordEncoder.Matches(userPassword, loginUser.UserPassword)
+	if err != nil {
+		logrus.Errorf("校验用户密码失败, userId=%d: %v", loginUser.ID, err)
+	}
+	if !matched {
+		return nil, s.loginFailed(ctx, userAccount, loginUser.ID, clientIP, userAgent, "password", failures)
+	}
+	if needsRehash {
+		s.rehashPassword(loginUser, userPassword)
+	}
+	s.loginAttemptService.RecordSuccess(ctx, userAccount, loginUser.ID, clientIP, userAgent)
+
+	// 5. 将用户 id 写入服务端 session，请求时由 AuthMiddleware 重新加载用户，角色变更即时生效
 	session := sessions.Default(c)
-	session.Set(constant.UserLoginState, loginUser)
+	session.Set(constant.UserLoginState, loginUser.ID)
 	if err := session.Save(); err != nil {
 		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "保存登录状态失败")
 	}
 
-	// 5. 返回脱敏的用户信息
+	// 6. 返回脱敏的用户信息
 	return s.GetLoginUserVO(loginUser), nil
 }
 
+// loginFailed 记录登录失败，本次失败导致账号锁定时返回锁定错误，否则返回统一的账号或密码错误
+func (s *UserServiceImpl) loginFailed(ctx context.Context, userAccount string, userId int64, clientIP, userAgent,
+	reason string, failures int64) error {
+	if err := s.loginAttemptService.RecordFailure(ctx, userAccount, userId, clientIP, userAgent, reason, failures); err != nil {
+		return err
+	}
+	return exception.NewBusinessErrorWithMessage(exception.ParamsError, "用户不存在或密码错误")
+}
+
 // GetLoginUser 获取当前登录用户
 func (s *UserServiceImpl) GetLoginUser(c *gin.Context) (*entity.User, error) {
 	// 先判断用户是否登录
@@ -213,7 +256,10 @@ func (s *UserServiceImpl) AddUser(req *user.UserAddRequest) (int64, error) {
 	}
 
 	// 默认密码
-	encryptPassword := s.GetEncryptPassword(constant.DefaultPassword)
+	encryptPassword, err := s.GetEncryptPassword(constant.DefaultPassword)
+	if err != nil {
+		return 0, exception.NewBusinessErrorWithMessage(exception.SystemError, "密码加密失败")
+	}
 
 	newUser := &entity.User{
 		UserAccount:  req.UserAccount,
@@ -225,7 +271,7 @@ func (s *UserServiceImpl) AddUser(req *user.UserAddRequest) (int64, error) {
 		EditTime:     time.Now(),
 	}
 
-	err := s.userMapper.Save(newUser)
+	err = s.userMapper.Save(newUser)
 	if err != nil {
 		return 0, exception.NewBusinessErrorFromCode(exception.OperationError)
 	}
@@ -338,10 +384,22 @@ func (s *UserServiceImpl) ListUserVOByPage(req *user.UserQueryRequest) ([]vo.Use
 	return userVOList, total, nil
 }
 
-// GetEncryptPassword 加密密码
-func (s *UserServiceImpl) GetEncryptPassword(userPassword string) string {
-	// 盐值，混淆密码
-	saltedPassword := userPassword + constant.PasswordSalt
-	hash := md5.Sum([]byte(saltedPassword))
-	return hex.EncodeToString(hash[:])
+// GetEncryptPassword 使用配置的哈希算法加密密码
+func (s *UserServiceImpl) GetEncryptPassword(userPassword string) (string, error) {
+	return s.passwordEncoder.Encode(userPassword)
+}
+
+// rehashPassword 使用当前算法重新生成密码哈希并保存，失败不影响本次登录
+func (s *UserServiceImpl) rehashPassword(user *entity.User, userPassword string) {
+	encryptPassword, err := s.GetEncryptPassword(userPassword)
+	if err != nil {
+		logrus.Errorf("重新生成密码哈希失败, userId=%d: %v", user.ID, err)
+		return
+	}
+	if err := s.userMapper.UpdatePassword(user.ID, encryptPassword); err != nil {
+		logrus.Errorf("保存升级后的密码哈希失败, userId=%d: %v", user.ID, err)
+		return
+	}
+	user.UserPassword = encryptPassword
+	logrus.Infof("用户密码哈希已升级, userId=%d, algorithm=%s", user.ID, security.DetectAlgorithm(encryptPassword))
 }
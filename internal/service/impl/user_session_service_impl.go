@@ -0,0 +1,84 @@
+package impl
+
+import (
+	"context"
+
+	"aicode/internal/exception"
+	"aicode/internal/mapper"
+	"aicode/internal/model/dto/usersession"
+	"aicode/internal/model/vo"
+	"aicode/internal/service"
+
+	"github.com/sirupsen/logrus"
+)
+
+// UserSessionServiceImpl 用户登录 session 管理服务实现
+type UserSessionServiceImpl struct {
+	sessionMapper   mapper.SessionMapper
+	authTokenMapper *mapper.AuthTokenMapper
+}
+
+// NewUserSessionService 创建用户登录 session 管理服务实例
+func NewUserSessionService(sessionMapper mapper.SessionMapper,
+	authTokenMapper *mapper.AuthTokenMapper) service.UserSessionService {
+	return &UserSessionServiceImpl{
+		sessionMapper:   sessionMapper,
+		authTokenMapper: authTokenMapper,
+	}
+}
+
+// ListUserSessions 查询用户未过期的登录 session
+func (s *UserSessionServiceImpl) ListUserSessions(ctx context.Context, userId int64) ([]vo.UserSessionVO, error) {
+	if userId <= 0 {
+		return nil, exception.NewBusinessErrorFromCode(exception.ParamsError)
+	}
+	records, err := s.sessionMapper.ListByUser(ctx, userId)
+	if err != nil {
+		logrus.Errorf("查询用户 session 失败, userId=%d: %v", userId, err)
+		return nil, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询用户 session 失败")
+	}
+	sessionVOList := make([]vo.UserSessionVO, 0, len(records))
+	for _, record := range records {
+		sessionVOList = append(sessionVOList, vo.UserSessionVO{
+			SessionID:      record.ID,
+			ClientIP:       record.ClientIP,
+			UserAgent:      record.UserAgent,
+			CreateTime:     record.CreateTime,
+			LastAccessTime: record.LastAccessTime,
+			ExpireTime:     record.ExpireTime,
+		})
+	}
+	return sessionVOList, nil
+}
+
+// ExpireUserSessions 强制失效用户的 session
+func (s *UserSessionServiceImpl) ExpireUserSessions(ctx context.Context,
+	req *usersession.UserSessionExpireRequest) (int64, error) {
+	if req == nil || req.UserID <= 0 {
+		return 0, exception.NewBusinessErrorFromCode(exception.ParamsError)
+	}
+	if req.SessionID != "" {
+		record, err := s.sessionMapper.Get(ctx, req.SessionID)
+		if err != nil {
+			return 0, exception.NewBusinessErrorWithMessage(exception.SystemError, "查询用户 session 失败")
+		}
+		if record == nil || record.UserID != req.UserID {
+			return 0, exception.NewBusinessErrorWithMessage(exception.NotFoundError, "session 不存在或已过期")
+		}
+		if err := s.sessionMapper.Delete(ctx, req.SessionID); err != nil {
+			return 0, exception.NewBusinessErrorWithMessage(exception.OperationError, "失效 session 失败")
+		}
+		return 1, nil
+	}
+
+	count, err := s.sessionMapper.DeleteByUser(ctx, req.UserID)
+	if err != nil {
+		logrus.Errorf("删除用户 session 失败, userId=%d: %v", req.UserID, err)
+		return 0, exception.NewBusinessErrorWithMessage(exception.OperationError, "失效 session 失败")
+	}
+	// 同时吊销令牌会话，避免通过 refresh 令牌继续访问
+	if err := s.authTokenMapper.RevokeByUserId(req.UserID); err != nil {
+		return count, exception.NewBusinessErrorWithMessage(exception.OperationError, "吊销令牌会话失败，数据库错误")
+	}
+	return count, nil
+}
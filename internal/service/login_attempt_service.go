@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	"aicode/internal/model/vo"
+)
+
+// LoginAttemptService 登录防暴力破解服务接口：按账号与 IP 原子地统计登录尝试次数，按次数要求等待或锁定账号，并记录登录审计
+type LoginAttemptService interface {
+	// CheckLogin 校验账号与 IP 当前是否允许尝试登录，允许时在校验密码前原子地将本次尝试计入账号与 IP 的失败次数，
+	// 返回计入后账号的失败次数；账号锁定时返回 AccountLocked，需要等待或 IP 失败次数过多时返回 TooManyRequest
+	CheckLogin(ctx context.Context, userAccount, clientIP, userAgent string) (int64, error)
+
+	// RecordSuccess 记录登录成功，账号的失败次数清零，本次尝试不再计入 IP 的失败次数
+	RecordSuccess(ctx context.Context, userAccount string, userId int64, clientIP, userAgent string)
+
+	// RecordFailure 记录登录失败，failures 为 CheckLogin 返回的失败次数，达到阈值时返回 AccountLocked，否则返回 nil
+	RecordFailure(ctx context.Context, userAccount string, userId int64, clientIP, userAgent, reason string,
+		failures int64) error
+
+	// UnlockUser 解锁用户账号，连续失败次数清零（管理员）
+	UnlockUser(ctx context.Context, userId int64) (bool, error)
+
+	// ListLoginAttempts 按时间倒序查询用户最近的登录记录（管理员）
+	ListLoginAttempts(ctx context.Context, userId int64) ([]vo.LoginAttemptVO, error)
+}
@@ -0,0 +1,9 @@
+package service
+
+import "context"
+
+// PermissionService 角色权限服务接口
+type PermissionService interface {
+	// HasPermission 判断角色是否拥有权限
+	HasPermission(ctx context.Context, role, permission string) (bool, error)
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+
+	"aicode/internal/model/dto/promptexperiment"
+	"aicode/internal/model/vo"
+)
+
+// PromptExperimentService 提示词实验服务接口
+// 对同一模板的多个版本按权重分流，记录每次生成的分组与结果信号（存储成功、续写次数、用户回滚）；
+// 管理类方法仅供管理员调用，记录类方法失败只记录日志，不影响代码生成
+type PromptExperimentService interface {
+	// CreateExperiment 创建并启动实验，同一模板同一时刻只能有一个进行中的实验，返回实验id
+	CreateExperiment(ctx context.Context, req *promptexperiment.PromptExperimentCreateRequest) (int64, error)
+
+	// StopExperiment 停止实验，停止后模板恢复使用生效版本
+	StopExperiment(ctx context.Context, id int64) (bool, error)
+
+	// ListExperiments 按创建时间倒序查询全部实验
+	ListExperiments(ctx context.Context) ([]vo.PromptExperimentVO, error)
+
+	// GetStats 查询实验按分组汇总的结果
+	GetStats(ctx context.Context, id int64) (*vo.PromptExperimentStatsVO, error)
+
+	// Assign 模板有进行中的实验时，按登录用户 id 分配分组并创建实验记录；没有实验时返回 nil
+	Assign(ctx context.Context, name string, appId int64, model string) (*vo.PromptAssignment, error)
+
+	// RecordRetry 记录一次输出被截断后的续写
+	RecordRetry(ctx context.Context, recordId int64)
+
+	// RecordOutcome 记录生成结果，appVersion 为生成成功后创建的应用版本号（未创建时为 0）
+	RecordOutcome(ctx context.Context, recordId int64, success bool, appVersion int)
+
+	// RecordRollback 应用回滚到 version 时，将其后版本对应的实验记录标记为被回滚
+	RecordRollback(ctx context.Context, appId int64, version int)
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"aicode/internal/model/dto/prompttemplate"
+	"aicode/internal/model/vo"
+)
+
+// PromptTemplateService 提示词模板服务接口
+// 模板以 Go text/template 语法编写，按名称读取生效版本并缓存；修改类方法仅供管理员调用
+type PromptTemplateService interface {
+	// AddTemplate 创建模板，名称已存在时返回业务异常，返回版本号
+	AddTemplate(ctx context.Context, req *prompttemplate.PromptTemplateAddRequest) (int, error)
+
+	// UpdateTemplate 将修改保存为模板的新版本并立即生效，返回新版本号
+	UpdateTemplate(ctx context.Context, req *prompttemplate.PromptTemplateUpdateRequest) (int, error)
+
+	// ActivateVersion 将模板的指定版本设为生效版本
+	ActivateVersion(ctx context.Context, name string, version int) (bool, error)
+
+	// DeleteTemplate 删除模板的全部版本，删除后使用该模板的生成请求将失败
+	DeleteTemplate(ctx context.Context, name string) (bool, error)
+
+	// GetTemplate 查询模板的指定版本，version 为 0 时查询生效版本
+	GetTemplate(ctx context.Context, name string, version int) (*vo.PromptTemplateVO, error)
+
+	// ListTemplates 查询全部模板的生效版本
+	ListTemplates(ctx context.Context) ([]vo.PromptTemplateVO, error)
+
+	// ListVersions 按版本号倒序查询模板的全部版本
+	ListVersions(ctx context.Context, name string) ([]vo.PromptTemplateVO, error)
+
+	// Render 使用模板的生效版本渲染提示词；模板不存在、为空或渲染失败时返回业务异常
+	Render(ctx context.Context, name string, vars *vo.PromptVariables) (string, error)
+
+	// RenderVersion 使用模板的指定版本渲染提示词，供提示词实验按分组渲染
+	RenderVersion(ctx context.Context, name string, version int, vars *vo.PromptVariables) (string, error)
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+
+	"aicode/consts"
+	"aicode/internal/model/vo"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// TokenUsageService Token 用量服务接口
+type TokenUsageService interface {
+	// CheckQuota 校验当前登录用户的日/月 token 用量是否已超出配额
+	CheckQuota(ctx context.Context) error
+
+	// RecordUsage 记录一次模型调用的 token 用量，模型未返回用量时按 prompt 与回答内容估算
+	RecordUsage(ctx context.Context, appId int64, model string,
+		bizType consts.UsageBizType, prompt []*schema.Message, msg *schema.Message)
+
+	// RecordStreamUsage 读取流式回答并记录 token 用量，流异常结束时按已收到的内容记录，
+	// 模型未返回用量时按 prompt 与回答内容估算；读取结束后关闭 stream
+	RecordStreamUsage(ctx context.Context, appId int64, model string,
+		bizType consts.UsageBizType, prompt []*schema.Message, stream *schema.StreamReader[*schema.Message])
+
+	// GetUsageSummary 获取当前登录用户的用量与配额
+	GetUsageSummary(ctx context.Context) (*vo.TokenUsageSummaryVO, error)
+}
@@ -46,6 +46,6 @@ type UserService interface {
 	// ListUserVOByPage 分页获取用户封装列表
 	ListUserVOByPage(req *user.UserQueryRequest) ([]vo.UserVO, int64, error)
 
-	// GetEncryptPassword 加密密码
-	GetEncryptPassword(userPassword string) string
+	// GetEncryptPassword 使用配置的哈希算法加密密码
+	GetEncryptPassword(userPassword string) (string, error)
 }
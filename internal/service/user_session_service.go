@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+
+	"aicode/internal/model/dto/usersession"
+	"aicode/internal/model/vo"
+)
+
+// UserSessionService 用户登录 session 管理服务接口，仅供管理员使用
+type UserSessionService interface {
+	// ListUserSessions 按最近访问时间倒序查询用户未过期的登录 session
+	ListUserSessions(ctx context.Context, userId int64) ([]vo.UserSessionVO, error)
+
+	// ExpireUserSessions 强制失效用户的指定 session，或全部 session 与令牌会话，返回失效的 session 数量
+	ExpireUserSessions(ctx context.Context, req *usersession.UserSessionExpireRequest) (int64, error)
+}
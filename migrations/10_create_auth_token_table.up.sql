@@ -0,0 +1,33 @@
+-- 令牌会话表：每次登录签发一组 access/refresh 令牌对应一条，刷新时轮换 refresh 令牌，吊销后会话内的全部令牌失效
+create table if not exists auth_token
+(
+    id             bigint auto_increment comment 'id' primary key,
+    user_id        bigint                                 not null comment '用户id',
+    session_id     varchar(64)                            not null comment '会话id，写入令牌的 sid 声明',
+    refresh_jti    varchar(64)                            not null comment '当前有效的 refresh 令牌 id，刷新时轮换',
+    expire_time    datetime                               not null comment '会话过期时间，即 refresh 令牌的过期时间',
+    revoked        tinyint      default 0                 not null comment '是否已吊销',
+    last_used_time datetime                               null comment '最近一次刷新时间',
+    create_time    datetime     default CURRENT_TIMESTAMP not null comment '创建时间',
+    update_time    datetime     default CURRENT_TIMESTAMP not null on update CURRENT_TIMESTAMP comment '更新时间',
+    UNIQUE KEY uk_session_id (session_id),
+    INDEX idx_user_id (user_id)
+) comment '令牌会话' collate = utf8mb4_unicode_ci;
+
+-- 个人 API Key 表：只保存密钥的 SHA-256 哈希，明文仅在创建时返回一次
+create table if not exists api_key
+(
+    id             bigint auto_increment comment 'id' primary key,
+    user_id        bigint                                 not null comment '所属用户id',
+    name           varchar(64)                            not null comment '名称',
+    key_prefix     varchar(16)                            not null comment '密钥前缀，用于展示与辨认',
+    key_hash       char(64)                               not null comment '密钥的 SHA-256 哈希（十六进制）',
+    scopes         varchar(1024)                          not null comment '授权范围（JSON 数组）',
+    expire_time    datetime                               null comment '过期时间，为空表示永不过期',
+    last_used_time datetime                               null comment '最近一次使用时间',
+    revoked        tinyint      default 0                 not null comment '是否已吊销',
+    create_time    datetime     default CURRENT_TIMESTAMP not null comment '创建时间',
+    update_time    datetime     default CURRENT_TIMESTAMP not null on update CURRENT_TIMESTAMP comment '更新时间',
+    UNIQUE KEY uk_key_hash (key_hash),
+    INDEX idx_user_id (user_id)
+) comment '个人 API Key' collate = utf8mb4_unicode_ci;
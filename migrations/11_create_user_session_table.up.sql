@@ -0,0 +1,14 @@
+-- 服务端 session 表，server.session.store 为 mysql 时使用
+create table if not exists user_session
+(
+    id               varchar(64)                            not null comment 'session id' primary key,
+    user_id          bigint       default 0                 not null comment '登录用户id，未登录为 0',
+    data             blob                                   null comment 'gob 编码的 session 值',
+    client_ip        varchar(64)  default ''                not null comment '客户端 IP',
+    user_agent       varchar(512) default ''                not null comment '客户端 User-Agent',
+    create_time      datetime                               not null comment '创建时间',
+    last_access_time datetime                               not null comment '最近访问时间',
+    expire_time      datetime                               not null comment '过期时间，取创建时间加最长有效期与最近访问时间加空闲超时中较早者',
+    INDEX idx_user_id (user_id),
+    INDEX idx_expire_time (expire_time)
+) comment '服务端 session' collate = utf8mb4_unicode_ci;
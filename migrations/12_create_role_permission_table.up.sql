@@ -0,0 +1,46 @@
+-- 角色表：role_key 与 user.user_role 对应
+create table if not exists role
+(
+    id          bigint auto_increment comment 'id' primary key,
+    role_key    varchar(64)                            not null comment '角色标识，与 user.user_role 对应',
+    role_name   varchar(128)                           not null comment '角色名称',
+    description varchar(512) default ''                not null comment '角色说明',
+    create_time datetime     default CURRENT_TIMESTAMP not null comment '创建时间',
+    update_time datetime     default CURRENT_TIMESTAMP not null on update CURRENT_TIMESTAMP comment '更新时间',
+    UNIQUE KEY uk_role_key (role_key)
+) comment '角色' collate = utf8mb4_unicode_ci;
+
+-- 权限表：permission_key 与路由注册时声明的权限标识对应
+create table if not exists permission
+(
+    id              bigint auto_increment comment 'id' primary key,
+    permission_key  varchar(64)                            not null comment '权限标识',
+    permission_name varchar(128)                           not null comment '权限名称',
+    description     varchar(512) default ''                not null comment '权限说明',
+    create_time     datetime     default CURRENT_TIMESTAMP not null comment '创建时间',
+    update_time     datetime     default CURRENT_TIMESTAMP not null on update CURRENT_TIMESTAMP comment '更新时间',
+    UNIQUE KEY uk_permission_key (permission_key)
+) comment '权限' collate = utf8mb4_unicode_ci;
+
+-- 角色权限关联表
+create table if not exists role_permission
+(
+    id             bigint auto_increment comment 'id' primary key,
+    role_key       varchar(64)                        not null comment '角色标识',
+    permission_key varchar(64)                        not null comment '权限标识',
+    create_time    datetime default CURRENT_TIMESTAMP not null comment '创建时间',
+    UNIQUE KEY uk_role_permission (role_key, permission_key)
+) comment '角色权限关联' collate = utf8mb4_unicode_ci;
+
+-- 内置角色与权限，管理员默认拥有全部内置权限
+insert ignore into role (role_key, role_name, description)
+values ('user', '用户', '普通用户'),
+       ('admin', '管理员', '管理员，可访问全部管理接口');
+
+insert ignore into permission (permission_key, permission_name, description)
+values ('session:manage', '登录 session 管理', '查询并强制失效用户的登录 session'),
+       ('prompt:manage', '提示词管理', '管理系统提示词模板与提示词实验');
+
+insert ignore into role_permission (role_key, permission_key)
+values ('admin', 'session:manage'),
+       ('admin', 'prompt:manage');
@@ -0,0 +1,15 @@
+-- 登录记录表：记录每次登录尝试与管理员解锁操作，用于统计连续失败次数与审计
+create table if not exists login_attempt
+(
+    id           bigint auto_increment comment 'id' primary key,
+    user_account varchar(256)                           not null comment '登录时提交的账号，账号不存在时同样记录',
+    user_id      bigint       default 0                 not null comment '账号对应的用户id，账号不存在时为 0',
+    client_ip    varchar(64)  default ''                not null comment '客户端 IP',
+    user_agent   varchar(512) default ''                not null comment '客户端 User-Agent',
+    result       tinyint                                not null comment '结果（0-失败，1-成功，2-被拒绝，3-管理员解锁）',
+    reason       varchar(128) default ''                not null comment '失败或拒绝原因',
+    operator_id  bigint       default 0                 not null comment '解锁操作的管理员id',
+    create_time  datetime     default CURRENT_TIMESTAMP not null comment '创建时间',
+    INDEX idx_account_time (user_account, create_time),
+    INDEX idx_ip_time (client_ip, create_time)
+) comment '登录记录' collate = utf8mb4_unicode_ci;
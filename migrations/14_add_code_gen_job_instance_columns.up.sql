@@ -0,0 +1,5 @@
+-- 异步代码生成任务的执行实例：执行中的任务由所在实例定期刷新心跳，心跳超时的任务视为实例已退出
+alter table code_gen_job
+    add column instance_id    varchar(64) default '' not null comment '执行任务的服务实例id' after status,
+    add column heartbeat_time datetime               null comment '执行实例最近一次心跳时间' after start_time,
+    add index idx_status_heartbeat (status, heartbeat_time);
@@ -0,0 +1,9 @@
+-- 登录失败计数表：按账号与客户端 IP 原子计数，security.login.store 为 mysql 时使用
+create table if not exists login_counter
+(
+    counter_key varchar(300)           not null comment '计数 key：account:{账号} 或 ip:{客户端 IP}' primary key,
+    count       bigint       default 0 not null comment '时间窗口内已计入、尚未登录成功的尝试次数',
+    last_time   datetime(3)            not null comment '最近一次计入的尝试时间',
+    expire_time datetime(3)            not null comment '过期时间，过期后计数视为 0',
+    INDEX idx_expire_time (expire_time)
+) comment '登录失败计数' collate = utf8mb4_unicode_ci;
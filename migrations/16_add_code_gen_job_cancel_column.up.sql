@@ -0,0 +1,3 @@
+-- 异步代码生成任务的取消请求：任务在其他实例执行时记录取消请求，由执行实例轮询后取消
+alter table code_gen_job
+    add column cancel_requested tinyint(1) default 0 not null comment '是否已请求取消执行中的任务' after instance_id;
@@ -0,0 +1,17 @@
+-- 应用表
+create table if not exists app
+(
+    id            bigint auto_increment comment 'id' primary key,
+    app_name      varchar(256)                           null comment '应用名称',
+    cover         varchar(512)                           null comment '应用封面',
+    init_prompt   text                                   null comment '应用初始化的 prompt',
+    code_gen_type varchar(64)                            null comment '代码生成类型：single/multi',
+    priority      int          default 0                 not null comment '优先级',
+    user_id       bigint                                 not null comment '创建用户id',
+    edit_time     datetime     default CURRENT_TIMESTAMP not null comment '编辑时间',
+    create_time   datetime     default CURRENT_TIMESTAMP not null comment '创建时间',
+    update_time   datetime     default CURRENT_TIMESTAMP not null on update CURRENT_TIMESTAMP comment '更新时间',
+    is_delete     tinyint      default 0                 not null comment '是否删除',
+    INDEX idx_app_name (app_name),
+    INDEX idx_user_id (user_id)
+) comment '应用' collate = utf8mb4_unicode_ci;
@@ -0,0 +1,15 @@
+-- 对话历史表
+create table if not exists chat_history
+(
+    id              bigint auto_increment comment 'id' primary key,
+    message         mediumtext                             not null comment '消息内容',
+    message_type    varchar(32)                            not null comment '消息类型：user/assistant',
+    app_id          bigint       default 0                 not null comment '应用id（代码生成对话）',
+    conversation_id varchar(64)  default ''                not null comment '会话id（普通聊天对话）',
+    user_id         bigint                                 not null comment '创建用户id',
+    create_time     datetime     default CURRENT_TIMESTAMP not null comment '创建时间',
+    update_time     datetime     default CURRENT_TIMESTAMP not null on update CURRENT_TIMESTAMP comment '更新时间',
+    is_delete       tinyint      default 0                 not null comment '是否删除',
+    INDEX idx_app_id (app_id, id),
+    INDEX idx_user_conversation (user_id, conversation_id, id)
+) comment '对话历史' collate = utf8mb4_unicode_ci;
@@ -0,0 +1,15 @@
+-- Token 用量表
+create table if not exists token_usage
+(
+    id                bigint auto_increment comment 'id' primary key,
+    user_id           bigint                                 not null comment '用户id',
+    app_id            bigint       default 0                 not null comment '应用id（普通聊天为 0）',
+    model             varchar(128)                           not null comment '实际响应的模型名',
+    biz_type          varchar(32)                            not null comment '业务类型：chat/code',
+    prompt_tokens     int          default 0                 not null comment '输入 token 数',
+    completion_tokens int          default 0                 not null comment '输出 token 数',
+    total_tokens      int          default 0                 not null comment '总 token 数',
+    create_time       datetime     default CURRENT_TIMESTAMP not null comment '创建时间',
+    INDEX idx_user_time (user_id, create_time),
+    INDEX idx_app_id (app_id)
+) comment 'Token 用量' collate = utf8mb4_unicode_ci;
@@ -0,0 +1,5 @@
+-- 应用部署信息
+alter table app
+    add column deploy_key    varchar(64) null comment '部署标识' after priority,
+    add column deployed_time datetime    null comment '部署时间' after deploy_key,
+    add unique index uk_deploy_key (deploy_key);
@@ -0,0 +1,13 @@
+-- 应用代码版本表
+create table if not exists app_version
+(
+    id          bigint auto_increment comment 'id' primary key,
+    app_id      bigint                                 not null comment '应用id',
+    version     int                                    not null comment '版本号，按应用从 1 递增',
+    prompt      text                                   null comment '生成该版本的用户 prompt',
+    model       varchar(128) default ''                not null comment '实际响应的模型名',
+    gen_type    varchar(64)  default ''                not null comment '代码生成类型',
+    user_id     bigint                                 not null comment '创建用户id',
+    create_time datetime     default CURRENT_TIMESTAMP not null comment '创建时间',
+    UNIQUE KEY uk_app_version (app_id, version)
+) comment '应用代码版本' collate = utf8mb4_unicode_ci;
@@ -0,0 +1,16 @@
+-- 提示词模板表，同名模板的每次修改保存为一个新版本，同一时刻只有一个生效版本
+create table if not exists prompt_template
+(
+    id          bigint auto_increment comment 'id' primary key,
+    name        varchar(64)                            not null comment '模板名称，代码生成模板与生成类型同名',
+    version     int                                    not null comment '版本号，按模板名称从 1 递增',
+    content     mediumtext                             not null comment '模板内容（Go text/template 语法）',
+    description varchar(512) default ''                not null comment '版本说明',
+    is_active   tinyint      default 0                 not null comment '是否为生效版本',
+    user_id     bigint       default 0                 not null comment '创建用户id（从文件导入时为 0）',
+    create_time datetime     default CURRENT_TIMESTAMP not null comment '创建时间',
+    update_time datetime     default CURRENT_TIMESTAMP not null on update CURRENT_TIMESTAMP comment '更新时间',
+    is_delete   tinyint      default 0                 not null comment '是否删除',
+    UNIQUE KEY uk_name_version (name, version),
+    INDEX idx_name_active (name, is_active)
+) comment '提示词模板' collate = utf8mb4_unicode_ci;
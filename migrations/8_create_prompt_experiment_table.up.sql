@@ -0,0 +1,33 @@
+-- 提示词实验表：对同一模板的多个版本按权重分流，同一模板同一时刻只有一个进行中的实验
+create table if not exists prompt_experiment
+(
+    id            bigint auto_increment comment 'id' primary key,
+    template_name varchar(64)                            not null comment '参与实验的模板名称',
+    variants      varchar(1024)                          not null comment '实验分组（JSON 数组，每组为模板版本号与分流权重）',
+    description   varchar(512) default ''                not null comment '实验说明',
+    status        tinyint      default 1                 not null comment '状态（1-进行中，0-已停止）',
+    user_id       bigint                                 not null comment '创建用户id',
+    create_time   datetime     default CURRENT_TIMESTAMP not null comment '创建时间',
+    update_time   datetime     default CURRENT_TIMESTAMP not null on update CURRENT_TIMESTAMP comment '更新时间',
+    INDEX idx_template_status (template_name, status)
+) comment '提示词实验' collate = utf8mb4_unicode_ci;
+
+-- 提示词实验记录表：每次参与实验的代码生成记录一条，保存分组与结果信号
+create table if not exists prompt_experiment_record
+(
+    id               bigint auto_increment comment 'id' primary key,
+    experiment_id    bigint                                 not null comment '实验id',
+    template_version int                                    not null comment '分配到的模板版本号',
+    app_id           bigint                                 not null comment '应用id',
+    user_id          bigint                                 not null comment '用户id',
+    gen_type         varchar(64)  default ''                not null comment '代码生成类型',
+    model            varchar(128) default ''                not null comment '请求的模型名',
+    status           tinyint      default 0                 not null comment '存储结果（0-进行中，1-成功，2-失败）',
+    retries          int          default 0                 not null comment '输出被截断后请求续写的次数',
+    app_version      int          default 0                 not null comment '生成成功后创建的应用版本号',
+    rolled_back      tinyint      default 0                 not null comment '生成的版本是否被用户回滚',
+    create_time      datetime     default CURRENT_TIMESTAMP not null comment '创建时间',
+    update_time      datetime     default CURRENT_TIMESTAMP not null on update CURRENT_TIMESTAMP comment '更新时间',
+    INDEX idx_experiment_version (experiment_id, template_version),
+    INDEX idx_app_version (app_id, app_version)
+) comment '提示词实验记录' collate = utf8mb4_unicode_ci;
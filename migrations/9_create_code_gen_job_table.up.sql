@@ -0,0 +1,20 @@
+-- 异步代码生成任务表
+create table if not exists code_gen_job
+(
+    id           bigint auto_increment comment 'id' primary key,
+    app_id       bigint                                 not null comment '应用id',
+    user_id      bigint                                 not null comment '提交用户id',
+    model        varchar(128)                           not null comment '请求的模型名',
+    gen_type     varchar(64)                            not null comment '代码生成类型',
+    question     text                                   not null comment '用户提问',
+    status       varchar(16)  default 'queued'          not null comment '状态：queued/running/succeeded/failed/cancelled',
+    result_model varchar(128) default ''                not null comment '实际响应的模型名',
+    content      mediumtext                             null comment '模型输出的原始内容',
+    error_msg    varchar(1024) default ''               not null comment '失败原因',
+    start_time   datetime                               null comment '开始执行时间',
+    finish_time  datetime                               null comment '结束时间',
+    create_time  datetime     default CURRENT_TIMESTAMP not null comment '提交时间',
+    update_time  datetime     default CURRENT_TIMESTAMP not null on update CURRENT_TIMESTAMP comment '更新时间',
+    INDEX idx_user_app (user_id, app_id),
+    INDEX idx_status (status)
+) comment '异步代码生成任务' collate = utf8mb4_unicode_ci;
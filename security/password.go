@@ -0,0 +1,257 @@
+package security
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 支持的密码哈希算法
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+	// AlgorithmLegacyMD5 旧版加盐 MD5，只用于校验存量密码，不再用于生成
+	AlgorithmLegacyMD5 = "md5"
+)
+
+// ErrPasswordTooLong 密码超过 bcrypt 支持的 72 字节
+var ErrPasswordTooLong = bcrypt.ErrPasswordTooLong
+
+// PasswordHasher 密码哈希器，生成的哈希串自带算法与参数，校验时无需额外信息
+type PasswordHasher interface {
+	// Algorithm 返回算法名
+	Algorithm() string
+
+	// Hash 生成密码哈希
+	Hash(password string) (string, error)
+
+	// Verify 校验密码与哈希是否匹配，哈希格式不属于本算法时返回错误
+	Verify(password, encoded string) (bool, error)
+
+	// NeedsRehash 判断哈希是否由本算法以当前参数生成，参数调整后旧哈希需要重新生成
+	NeedsRehash(encoded string) bool
+}
+
+// BcryptHasher bcrypt 密码哈希器
+type BcryptHasher struct {
+	Cost int
+}
+
+// NewBcryptHasher 创建 bcrypt 密码哈希器，cost 不在有效范围内时使用默认值
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{Cost: cost}
+}
+
+// Algorithm 返回算法名
+func (h *BcryptHasher) Algorithm() string {
+	return AlgorithmBcrypt
+}
+
+// Hash 生成密码哈希
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Verify 校验密码与哈希是否匹配
+func (h *BcryptHasher) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// NeedsRehash 判断哈希的 cost 是否与当前配置一致
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != h.Cost
+}
+
+// Argon2idHasher argon2id 密码哈希器，哈希串为 PHC 格式：
+// $argon2id$v=19$m={内存KiB},t={迭代次数},p={并行度}${盐}${哈希}，盐与哈希为无填充的 base64
+type Argon2idHasher struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// NewArgon2idHasher 创建 argon2id 密码哈希器，为 0 的参数使用 RFC 9106 推荐的默认值
+func NewArgon2idHasher(memory, iterations uint32, parallelism uint8) *Argon2idHasher {
+	if memory == 0 {
+		memory = 64 * 1024
+	}
+	if iterations == 0 {
+		iterations = 3
+	}
+	if parallelism == 0 {
+		parallelism = 2
+	}
+	return &Argon2idHasher{
+		Memory:      memory,
+		Iterations:  iterations,
+		Parallelism: parallelism,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// Algorithm 返回算法名
+func (h *Argon2idHasher) Algorithm() string {
+	return AlgorithmArgon2id
+}
+
+// Hash 生成密码哈希
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, h.Memory, h.Iterations,
+		h.Parallelism, base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify 以哈希串中记录的参数重新计算并比较
+func (h *Argon2idHasher) Verify(password, encoded string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	actual := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism,
+		uint32(len(key)))
+	return subtle.ConstantTimeCompare(actual, key) == 1, nil
+}
+
+// NeedsRehash 判断哈希的参数是否与当前配置一致
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return params.Memory != h.Memory || params.Iterations != h.Iterations ||
+		params.Parallelism != h.Parallelism || uint32(len(salt)) != h.SaltLength ||
+		uint32(len(key)) != h.KeyLength
+}
+
+// decodeArgon2id 解析 PHC 格式的 argon2id 哈希串
+func decodeArgon2id(encoded string) (*Argon2idHasher, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != AlgorithmArgon2id {
+		return nil, nil, nil, errors.New("不是 argon2id 哈希")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("不支持的 argon2 版本: %s", parts[2])
+	}
+	params := &Argon2idHasher{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations,
+		&params.Parallelism); err != nil {
+		return nil, nil, nil, fmt.Errorf("argon2id 参数格式错误: %w", err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("argon2id 盐格式错误: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, errors.New("argon2id 哈希格式错误")
+	}
+	return params, salt, key, nil
+}
+
+// PasswordEncoder 密码编码器：新密码使用配置的哈希器生成，校验时按哈希串格式识别算法，
+// 兼容其他受支持算法与旧版加盐 MD5 生成的存量哈希
+type PasswordEncoder struct {
+	current PasswordHasher
+	hashers map[string]PasswordHasher
+	// legacySalt 旧版 MD5 的盐值
+	legacySalt string
+}
+
+// NewPasswordEncoder 创建密码编码器，algorithm 为空时使用 bcrypt
+func NewPasswordEncoder(algorithm string, bcryptHasher *BcryptHasher, argon2idHasher *Argon2idHasher,
+	legacySalt string) (*PasswordEncoder, error) {
+	hashers := map[string]PasswordHasher{
+		AlgorithmBcrypt:   bcryptHasher,
+		AlgorithmArgon2id: argon2idHasher,
+	}
+	if algorithm == "" {
+		algorithm = AlgorithmBcrypt
+	}
+	current, ok := hashers[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("不支持的密码哈希算法: %s", algorithm)
+	}
+	return &PasswordEncoder{current: current, hashers: hashers, legacySalt: legacySalt}, nil
+}
+
+// Encode 使用当前算法生成密码哈希
+func (e *PasswordEncoder) Encode(password string) (string, error) {
+	return e.current.Hash(password)
+}
+
+// Matches 校验密码，needsRehash 表示密码正确但哈希不是当前算法与参数生成的，应使用 Encode 重新生成并保存
+func (e *PasswordEncoder) Matches(password, encoded string) (ok bool, needsRehash bool, err error) {
+	algorithm := DetectAlgorithm(encoded)
+	if algorithm == AlgorithmLegacyMD5 {
+		return e.matchesLegacyMD5(password, encoded), true, nil
+	}
+	hasher, found := e.hashers[algorithm]
+	if !found {
+		return false, false, errors.New("无法识别的密码哈希格式")
+	}
+	ok, err = hasher.Verify(password, encoded)
+	if err != nil || !ok {
+		return false, false, err
+	}
+	return true, hasher != e.current || hasher.NeedsRehash(encoded), nil
+}
+
+// matchesLegacyMD5 校验旧版加盐 MD5 哈希
+func (e *PasswordEncoder) matchesLegacyMD5(password, encoded string) bool {
+	hash := md5.Sum([]byte(password + e.legacySalt))
+	expected := hex.EncodeToString(hash[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(encoded))) == 1
+}
+
+// DetectAlgorithm 按哈希串格式识别算法，无法识别时返回空串
+func DetectAlgorithm(encoded string) string {
+	switch {
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"),
+		strings.HasPrefix(encoded, "$2y$"):
+		return AlgorithmBcrypt
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return AlgorithmArgon2id
+	case len(encoded) == hex.EncodedLen(md5.Size) && isHex(encoded):
+		return AlgorithmLegacyMD5
+	default:
+		return ""
+	}
+}
+
+// isHex 判断字符串是否只包含十六进制字符
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,216 @@
+package aicode_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"aicode/ai/chatmodel"
+	"aicode/config"
+	"aicode/constant"
+	"aicode/consts"
+	"aicode/internal/model/entity"
+	"aicode/internal/model/vo"
+	"aicode/internal/service"
+	"aicode/internal/service/impl"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// truncatedModel 测试使用的模型注册名
+const truncatedModel = "truncated-model"
+
+// newTruncatedModelServer 启动 openai 兼容的流式接口：首次回答输出一个完整文件后在第二个文件中途结束，
+// 续写请求同样在代码块中途结束，模型始终无法输出完整结果
+func newTruncatedModelServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		content := "<div>\n"
+		if calls == 1 {
+			content = "```js src/main.js\nconsole.log(1)\n```\n```vue src/App.vue\n<template>\n"
+		}
+		mu.Unlock()
+		chunk, _ := json.Marshal(map[string]any{
+			"id":      "chatcmpl-test",
+			"object":  "chat.completion.chunk",
+			"created": time.Now().Unix(),
+			"model":   truncatedModel,
+			"choices": []map[string]any{{
+				"index": 0,
+				"delta": map[string]any{"role": "assistant", "content": content},
+			}},
+		})
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprintf(w, "data: %s\n\ndata: [DONE]\n\n", chunk)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// setup 加载测试配置并注册指向测试接口的模型，返回应用目录
+func setup(t *testing.T, baseURL string) string {
+	t.Helper()
+	base := t.TempDir()
+	cfgPath := filepath.Join(base, "config.yml")
+	cfg := "file:\n  store_base_path: " + base + "\n" +
+		"ai:\n  providers:\n" +
+		"    - name: " + truncatedModel + "\n" +
+		"      type: openai\n" +
+		"      enabled: true\n" +
+		"      api_key: test\n" +
+		"      model: " + truncatedModel + "\n" +
+		"      base_url: " + baseURL + "\n"
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0644); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+	config.LoadConfig(cfgPath)
+	if _, err := chatmodel.InitChatModel(config.GetConfig()); err != nil {
+		t.Fatalf("注册模型失败: %v", err)
+	}
+	return filepath.Join(base, "app", "1")
+}
+
+type fakeAppService struct {
+	service.AppService
+}
+
+func (fakeAppService) GetOwnedApp(_ context.Context, id int64) (*entity.App, error) {
+	return &entity.App{ID: id, AppName: "test"}, nil
+}
+
+type fakeChatHistoryService struct {
+	service.ChatHistoryService
+}
+
+func (fakeChatHistoryService) AddChatMessage(context.Context, int64, string, consts.ChatRole, string) error {
+	return nil
+}
+
+func (fakeChatHistoryService) LoadHistoryMessages(context.Context, int64, string, int) ([]*schema.Message, error) {
+	return nil, nil
+}
+
+type fakeTokenUsageService struct {
+	service.TokenUsageService
+}
+
+func (fakeTokenUsageService) CheckQuota(context.Context) error {
+	return nil
+}
+
+func (fakeTokenUsageService) RecordStreamUsage(_ context.Context, _ int64, _ string,
+	_ consts.UsageBizType, _ []*schema.Message, stream *schema.StreamReader[*schema.Message]) {
+	stream.Close()
+}
+
+type fakePromptTemplateService struct {
+	service.PromptTemplateService
+}
+
+func (fakePromptTemplateService) Render(context.Context, string, *vo.PromptVariables) (string, error) {
+	return "system", nil
+}
+
+// recorder 记录版本创建与实验结果
+type recorder struct {
+	service.AppVersionService
+	service.PromptExperimentService
+
+	mu       sync.Mutex
+	versions int
+	outcomes []bool
+}
+
+func (r *recorder) CreateVersion(context.Context, int64, string, string, consts.CodeGenarateType) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.versions++
+	return r.versions, nil
+}
+
+func (r *recorder) Assign(context.Context, string, int64, string) (*vo.PromptAssignment, error) {
+	return nil, nil
+}
+
+func (r *recorder) RecordRetry(context.Context, int64) {}
+
+func (r *recorder) RecordOutcome(_ context.Context, _ int64, success bool, _ int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outcomes = append(r.outcomes, success)
+}
+
+// TestCodeStreamTruncatedNotSaved 续写次数用尽后输出仍被截断时任务失败，不清理旧文件、不创建版本
+func TestCodeStreamTruncatedNotSaved(t *testing.T) {
+	appDir := setup(t, newTruncatedModelServer(t).URL)
+	stale := filepath.Join(appDir, "src", "Old.vue")
+	if err := os.MkdirAll(filepath.Dir(stale), 0755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(stale, []byte("<template></template>"), 0644); err != nil {
+		t.Fatalf("写入旧文件失败: %v", err)
+	}
+
+	rec := &recorder{}
+	svc := impl.NewAICodeService(fakeAppService{}, fakeChatHistoryService{}, fakeTokenUsageService{},
+		rec, fakePromptTemplateService{}, rec)
+	ctx := context.WithValue(context.Background(), constant.UserLoginState,
+		&entity.User{ID: 1, UserRole: constant.UserRole})
+	job, err := svc.CodeGenerateStream(ctx, &vo.AICodeRequest{
+		AppId:    1,
+		Model:    truncatedModel,
+		GenType:  consts.CodeGenarateTypeVueProject,
+		Question: "生成一个页面",
+	})
+	if err != nil {
+		t.Fatalf("启动生成任务失败: %v", err)
+	}
+
+	events, err := svc.SubscribeCodeJob(ctx, job.JobID, 0)
+	if err != nil {
+		t.Fatalf("订阅任务失败: %v", err)
+	}
+	var last vo.CodeJobEvent
+	timeout := time.After(10 * time.Second)
+	for done := false; !done; {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				done = true
+				break
+			}
+			last = event
+		case <-timeout:
+			t.Fatal("等待任务结束超时")
+		}
+	}
+
+	if last.Event != "error" {
+		t.Fatalf("任务应以错误结束，最后的事件为 %s: %v", last.Event, last.Data)
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.versions != 0 {
+		t.Fatalf("输出被截断时不应创建版本，实际创建 %d 个", rec.versions)
+	}
+	if len(rec.outcomes) != 1 || rec.outcomes[0] {
+		t.Fatalf("应记录一次实验失败结果，实际 %v", rec.outcomes)
+	}
+	if _, err := os.Stat(stale); err != nil {
+		t.Fatalf("输出被截断时不应清理旧文件: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(appDir, "src", "main.js")); err != nil {
+		t.Fatalf("已完整输出的文件应保留: %v", err)
+	}
+}
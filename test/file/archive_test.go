@@ -0,0 +1,80 @@
+package file_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"aicode/file"
+)
+
+// TestWriteAppZip 打包当前代码与历史版本，隐藏文件不进入压缩包
+func TestWriteAppZip(t *testing.T) {
+	appDir := setupStore(t, "9")
+	if err := file.WriteAppFile("9", "index.html", "v1"); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	if err := file.WriteAppFile("9", "src/main.js", "main"); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	if err := file.SnapshotApp("9", 1); err != nil {
+		t.Fatalf("保存快照失败: %v", err)
+	}
+	if err := file.WriteAppFile("9", "index.html", "v2"); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(appDir, ".uploads"), 0755); err != nil {
+		t.Fatalf("创建隐藏目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(appDir, ".uploads", "a.png"), []byte("png"), 0644); err != nil {
+		t.Fatalf("写入隐藏文件失败: %v", err)
+	}
+
+	for version, wantIndex := range map[int]string{0: "v2", 1: "v1"} {
+		var buf bytes.Buffer
+		if err := file.WriteAppZip(&buf, "9", version); err != nil {
+			t.Fatalf("打包版本 %d 失败: %v", version, err)
+		}
+		files := readZip(t, buf.Bytes())
+		names := make([]string, 0, len(files))
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if strings.Join(names, ",") != "index.html,src/main.js" {
+			t.Errorf("版本 %d 压缩包文件 = %v", version, names)
+		}
+		if files["index.html"] != wantIndex {
+			t.Errorf("版本 %d index.html = %q, 期望 %q", version, files["index.html"], wantIndex)
+		}
+	}
+
+	if err := file.WriteAppZip(io.Discard, "9", 2); err == nil {
+		t.Errorf("不存在的版本应返回错误")
+	}
+}
+
+// readZip 读取压缩包中的全部文件
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("读取压缩包失败: %v", err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("读取压缩包条目失败: %v", err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+	return files
+}
@@ -0,0 +1,89 @@
+package file_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aicode/file"
+)
+
+const patchIndexHTML = `<html>
+<body>
+  <button class="btn">提交</button>
+  <p>footer</p>
+</body>
+</html>
+`
+
+// TestApplyEditOutputSearchReplace SEARCH/REPLACE 与 unified diff 混合输出，包含新建文件
+func TestApplyEditOutputSearchReplace(t *testing.T) {
+	appDir := setupStore(t, "11")
+	if err := file.WriteAppFile("11", "index.html", patchIndexHTML); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	if err := file.WriteAppFile("11", "style.css", ".btn {\n  color: red;\n}\n"); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+
+	output := "把按钮改成蓝色，并修改文案：\n\n" +
+		"```\nindex.html\n<<<<<<< SEARCH\n  <button class=\"btn\">提交</button>\n=======\n  <button class=\"btn\">发送</button>\n>>>>>>> REPLACE\n```\n\n" +
+		"```diff\n--- a/style.css\n+++ b/style.css\n@@ -1,3 +1,3 @@\n .btn {\n-  color: red;\n+  color: blue;\n }\n```\n\n" +
+		"src/extra.js\n<<<<<<< SEARCH\n=======\nconsole.log('extra')\n>>>>>>> REPLACE\n"
+
+	changed, err := file.ApplyEditOutput("11", output)
+	if err != nil {
+		t.Fatalf("应用修改失败: %v", err)
+	}
+	if strings.Join(changed, ",") != "index.html,style.css,src/extra.js" {
+		t.Errorf("修改的文件 = %v", changed)
+	}
+	assertFile(t, appDir, "index.html", strings.Replace(patchIndexHTML, "提交", "发送", 1))
+	assertFile(t, appDir, "style.css", ".btn {\n  color: blue;\n}\n")
+	assertFile(t, appDir, "src/extra.js", "console.log('extra')\n")
+}
+
+// TestApplyEditPatchesAtomic 任一修改无法匹配时拒绝全部修改，文件保持不变
+func TestApplyEditPatchesAtomic(t *testing.T) {
+	appDir := setupStore(t, "12")
+	if err := file.WriteAppFile("12", "index.html", patchIndexHTML); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+
+	output := "index.html\n<<<<<<< SEARCH\n  <p>footer</p>\n=======\n  <p>页脚</p>\n>>>>>>> REPLACE\n" +
+		"index.html\n<<<<<<< SEARCH\n  <div>不存在的内容</div>\n=======\n  <div>x</div>\n>>>>>>> REPLACE\n"
+	if _, err := file.ApplyEditOutput("12", output); err == nil {
+		t.Fatalf("不匹配的修改应返回错误")
+	}
+	assertFile(t, appDir, "index.html", patchIndexHTML)
+
+	// 缩进不同但内容一致时宽松匹配
+	loose := "index.html\n<<<<<<< SEARCH\n    <p>footer</p>\n=======\n  <p>页脚</p>\n>>>>>>> REPLACE\n"
+	if _, err := file.ApplyEditOutput("12", loose); err != nil {
+		t.Fatalf("宽松匹配失败: %v", err)
+	}
+	assertFile(t, appDir, "index.html", strings.Replace(patchIndexHTML, "footer", "页脚", 1))
+}
+
+// TestParseEditPatchesTruncated SEARCH 块未结束时返回截断错误
+func TestParseEditPatchesTruncated(t *testing.T) {
+	_, err := file.ParseEditPatches("index.html\n<<<<<<< SEARCH\n<p>a</p>\n=======\n<p>")
+	var truncated *file.TruncatedOutputError
+	if !errors.As(err, &truncated) {
+		t.Fatalf("期望截断错误, 实际: %v", err)
+	}
+}
+
+// assertFile 断言应用目录下文件内容
+func assertFile(t *testing.T, appDir, relPath, want string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(appDir, filepath.FromSlash(relPath)))
+	if err != nil {
+		t.Fatalf("读取 %s 失败: %v", relPath, err)
+	}
+	if string(data) != want {
+		t.Errorf("%s 内容 = %q, 期望 %q", relPath, string(data), want)
+	}
+}
@@ -0,0 +1,121 @@
+package file_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aicode/config"
+	"aicode/file"
+	"aicode/internal/model/vo"
+)
+
+// setupStore 使用临时目录作为 store_base_path 加载配置，返回应用目录
+func setupStore(t *testing.T, appId string) string {
+	t.Helper()
+	base := t.TempDir()
+	cfgPath := filepath.Join(base, "config.yml")
+	cfg := "file:\n  store_base_path: " + base + "\n"
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0644); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+	config.LoadConfig(cfgPath)
+	return filepath.Join(base, "app", appId)
+}
+
+// projectContent 构造工程模式的模型输出
+func projectContent(t *testing.T, files map[string]string) string {
+	t.Helper()
+	result := vo.ProjectFileResult{}
+	for p, c := range files {
+		result.Files = append(result.Files, vo.ProjectFile{Path: p, Content: c})
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("序列化工程结果失败: %v", err)
+	}
+	return string(b)
+}
+
+// TestStoreToProjectFiles 正常写入文件树，重新生成时清理旧文件并保留隐藏文件
+func TestStoreToProjectFiles(t *testing.T) {
+	appDir := setupStore(t, "1")
+	ctx := context.Background()
+
+	content := projectContent(t, map[string]string{
+		"index.html":          "<html></html>",
+		"./src/main.js":       "main",
+		"src\\views\\Home.js": "home",
+	})
+	if err := file.StoreToProjectFiles(ctx, "1", content); err != nil {
+		t.Fatalf("写入工程失败: %v", err)
+	}
+	for _, p := range []string{"index.html", "src/main.js", "src/views/Home.js"} {
+		if _, err := os.Stat(filepath.Join(appDir, p)); err != nil {
+			t.Fatalf("文件 %s 未写入: %v", p, err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(appDir, ".keep"), []byte("x"), 0644); err != nil {
+		t.Fatalf("写入隐藏文件失败: %v", err)
+	}
+	content = projectContent(t, map[string]string{"index.html": "<html>v2</html>"})
+	if err := file.StoreToProjectFiles(ctx, "1", content); err != nil {
+		t.Fatalf("重新写入工程失败: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(appDir, "src")); !os.IsNotExist(err) {
+		t.Fatal("重新生成后旧文件应被清理")
+	}
+	if _, err := os.Stat(filepath.Join(appDir, ".keep")); err != nil {
+		t.Fatal("隐藏文件应保留")
+	}
+}
+
+// TestStoreToProjectFilesRejectsUnsafePath 非法路径整体拒绝，不落盘任何文件
+func TestStoreToProjectFilesRejectsUnsafePath(t *testing.T) {
+	appDir := setupStore(t, "2")
+	ctx := context.Background()
+
+	for _, bad := range []string{"../evil.js", "/etc/passwd", "src/../../evil.js", "C:/evil.js", ".env", "src/.git/config", ""} {
+		content := projectContent(t, map[string]string{
+			"index.html": "<html></html>",
+			bad:          "evil",
+		})
+		if err := file.StoreToProjectFiles(ctx, "2", content); err == nil {
+			t.Fatalf("路径 %q 应被拒绝", bad)
+		}
+	}
+	if _, err := os.Stat(appDir); !os.IsNotExist(err) {
+		t.Fatal("校验失败时不应写入任何文件")
+	}
+}
+
+// TestStoreToProjectFilesKeepsOldOnFailure 写入中途失败时保留上次生成的文件与隐藏文件，不留下临时目录
+func TestStoreToProjectFilesKeepsOldOnFailure(t *testing.T) {
+	appDir := setupStore(t, "3")
+	ctx := context.Background()
+
+	if err := file.StoreToProjectFiles(ctx, "3", projectContent(t, map[string]string{"index.html": "old"})); err != nil {
+		t.Fatalf("写入工程失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(appDir, ".keep"), []byte("k"), 0644); err != nil {
+		t.Fatalf("写入隐藏文件失败: %v", err)
+	}
+
+	// a 既是文件又是目录，无论写入顺序如何都会失败
+	content := projectContent(t, map[string]string{"index.html": "new", "a": "file", "a/b.js": "nested"})
+	if err := file.StoreToProjectFiles(ctx, "3", content); err == nil {
+		t.Fatal("路径冲突时应写入失败")
+	}
+	if b, err := os.ReadFile(filepath.Join(appDir, "index.html")); err != nil || string(b) != "old" {
+		t.Fatalf("写入失败后应保留旧代码: %q, %v", b, err)
+	}
+	if _, err := os.Stat(filepath.Join(appDir, ".keep")); err != nil {
+		t.Fatalf("隐藏文件应保留: %v", err)
+	}
+	if _, err := os.Stat(appDir + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("临时目录应被删除: %v", err)
+	}
+}
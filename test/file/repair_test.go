@@ -0,0 +1,85 @@
+package file_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"aicode/consts"
+	"aicode/file"
+	"aicode/internal/model/vo"
+)
+
+// TestRepairOutputJSON 修复 JSON 前后的说明文字、未转义换行、非法转义、未转义引号与多余逗号
+func TestRepairOutputJSON(t *testing.T) {
+	cases := map[string]string{
+		"前后说明文字": "好的，下面是代码：\n```json\n{\"html\": \"<p>hi</p>\"}\n```\n希望对你有帮助",
+		"未转义换行":  "{\"html\": \"<p>\nhi</p>\"}",
+		"非法转义":   `{"html": "<p>\shi</p>"}`,
+		"未转义引号":  `{"html": "<p class="a">hi</p>"}`,
+		"多余逗号":   `{"html": "<p>hi</p>",}`,
+	}
+	for name, content := range cases {
+		repaired, err := file.RepairOutput(consts.CodeGenarateTypeSingle, content)
+		if err != nil {
+			t.Fatalf("%s: 修复失败: %v", name, err)
+		}
+		var result vo.SingleHTMLResult
+		if err := json.Unmarshal([]byte(repaired), &result); err != nil || result.HTML == "" {
+			t.Fatalf("%s: 修复结果不合法: %s", name, repaired)
+		}
+	}
+}
+
+// TestRepairOutputFenceFallback JSON 不可用时从代码块中提取文件
+func TestRepairOutputFenceFallback(t *testing.T) {
+	content := "这是页面：\n```html\n<p>hi</p>\n```\n样式：\n```css\np{color:red}\n```\n脚本：\n```javascript\nalert(1)\n```\n"
+	repaired, err := file.RepairOutput(consts.CodeGenarateTypeMulti, content)
+	if err != nil {
+		t.Fatalf("提取代码块失败: %v", err)
+	}
+	var result vo.MultiHTMLResult
+	if err := json.Unmarshal([]byte(repaired), &result); err != nil {
+		t.Fatalf("提取结果不合法: %v", err)
+	}
+	if result.HTML != "<p>hi</p>\n" || result.CSS != "p{color:red}\n" || result.JavaScript != "alert(1)\n" {
+		t.Fatalf("提取结果错误: %+v", result)
+	}
+}
+
+// TestRepairOutputRawHTML 单文件模式下直接截取 HTML 文档
+func TestRepairOutputRawHTML(t *testing.T) {
+	content := "当然！\n<!DOCTYPE html><html><body>hi</body></html>\n以上。"
+	repaired, err := file.RepairOutput(consts.CodeGenarateTypeSingle, content)
+	if err != nil {
+		t.Fatalf("截取 HTML 失败: %v", err)
+	}
+	var result vo.SingleHTMLResult
+	if err := json.Unmarshal([]byte(repaired), &result); err != nil {
+		t.Fatalf("截取结果不合法: %v", err)
+	}
+	if result.HTML != "<!DOCTYPE html><html><body>hi</body></html>" {
+		t.Fatalf("截取结果错误: %q", result.HTML)
+	}
+}
+
+// TestRepairOutputTruncated 截断的输出返回 TruncatedOutputError，续写拼接后可正常解析
+func TestRepairOutputTruncated(t *testing.T) {
+	cases := map[consts.CodeGenarateType]string{
+		consts.CodeGenarateTypeMulti:      `{"html": "<p>hi</p>", "css": "p{col`,
+		consts.CodeGenarateTypeVueProject: "```js src/main.js\nconsole.log(1)\n",
+		consts.CodeGenarateTypeSingle:     "<html><body>hi",
+	}
+	for genType, content := range cases {
+		_, err := file.RepairOutput(genType, content)
+		var truncatedErr *file.TruncatedOutputError
+		if !errors.As(err, &truncatedErr) {
+			t.Fatalf("%s: 应返回截断错误，实际: %v", genType, err)
+		}
+	}
+
+	if _, err := file.RepairOutput(consts.CodeGenarateTypeMulti,
+		`{"html": "<p>hi</p>", "css": "p{col`+`or:red}", "javascript": ""}`); err != nil {
+		t.Fatalf("续写拼接后应能解析: %v", err)
+	}
+}
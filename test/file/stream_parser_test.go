@@ -0,0 +1,123 @@
+package file_test
+
+import (
+	"testing"
+
+	"aicode/file"
+)
+
+// parseAll 按 step 字节切分输入喂给解析器，返回已完成的文件内容与全部事件
+func parseAll(input string, step int) (map[string]string, []file.StreamEvent, *file.StreamFileParser) {
+	parser := file.NewStreamFileParser()
+	var events []file.StreamEvent
+	for i := 0; i < len(input); i += step {
+		end := i + step
+		if end > len(input) {
+			end = len(input)
+		}
+		events = append(events, parser.Feed(input[i:end])...)
+	}
+	events = append(events, parser.Close()...)
+
+	files := make(map[string]string)
+	for _, event := range events {
+		if event.Type == file.StreamEventFileCompleted {
+			files[event.Path] = event.Content
+		}
+	}
+	return files, events, parser
+}
+
+// assertChunksMatch 校验每个文件的增量内容拼接后与完整内容一致，且事件顺序为 started -> chunk* -> completed
+func assertChunksMatch(t *testing.T, events []file.StreamEvent) {
+	t.Helper()
+	chunks := make(map[string]string)
+	started := make(map[string]bool)
+	for _, event := range events {
+		switch event.Type {
+		case file.StreamEventFileStarted:
+			started[event.Path] = true
+		case file.StreamEventFileChunk:
+			if !started[event.Path] {
+				t.Fatalf("文件 %s 未开始即输出内容", event.Path)
+			}
+			chunks[event.Path] += event.Content
+		case file.StreamEventFileCompleted:
+			if chunks[event.Path] != event.Content {
+				t.Fatalf("文件 %s 增量内容 %q 与完整内容 %q 不一致", event.Path, chunks[event.Path], event.Content)
+			}
+		}
+	}
+}
+
+// TestStreamParserJSON 逐字节输入 multi 模式 JSON，正确解码转义字符与代理对
+func TestStreamParserJSON(t *testing.T) {
+	input := "```json\n{\"html\": \"<h1>\\u4f60\\u597d \\\"hi\\\"</h1>\\n\", \"css\": \"a{color:red}\", " +
+		"\"javascript\": \"alert('\\ud83d\\ude00')\"}\n```"
+	for _, step := range []int{1, 3, len(input)} {
+		files, events, parser := parseAll(input, step)
+		assertChunksMatch(t, events)
+		if parser.Truncated() {
+			t.Fatal("完整输出不应判定为截断")
+		}
+		want := map[string]string{
+			"index.html": "<h1>你好 \"hi\"</h1>\n",
+			"style.css":  "a{color:red}",
+			"script.js":  "alert('😀')",
+		}
+		for path, content := range want {
+			if files[path] != content {
+				t.Fatalf("step=%d 文件 %s 内容为 %q，期望 %q", step, path, files[path], content)
+			}
+		}
+	}
+}
+
+// TestStreamParserProjectFiles files 数组模式，兼容 content 先于 path 出现
+func TestStreamParserProjectFiles(t *testing.T) {
+	input := `{"files": [{"path": "index.html", "content": "<div id=\"app\"></div>"},` +
+		`{"content": "import App from './App.js'", "path": "src/main.js"}]}`
+	for _, step := range []int{1, 7} {
+		files, events, _ := parseAll(input, step)
+		assertChunksMatch(t, events)
+		if files["index.html"] != `<div id="app"></div>` || files["src/main.js"] != "import App from './App.js'" {
+			t.Fatalf("step=%d 解析结果错误: %v", step, files)
+		}
+	}
+}
+
+// TestStreamParserFence 代码块模式：说明文字作为 text 事件，代码块按语言或标注路径输出文件
+func TestStreamParserFence(t *testing.T) {
+	input := "下面是代码：\n```html\n<p>hi</p>\n```\n```js src/app.js\nconsole.log(1)\n```"
+	files, events, parser := parseAll(input, 2)
+	assertChunksMatch(t, events)
+	if files["index.html"] != "<p>hi</p>\n" || files["src/app.js"] != "console.log(1)\n" {
+		t.Fatalf("解析结果错误: %v", files)
+	}
+	if parser.Truncated() {
+		t.Fatal("完整输出不应判定为截断")
+	}
+	var text string
+	for _, event := range events {
+		if event.Type == file.StreamEventText {
+			text += event.Content
+		}
+	}
+	if text != "下面是代码：\n" {
+		t.Fatalf("说明文字为 %q", text)
+	}
+}
+
+// TestStreamParserTruncated 输出中途截断时，已完成的文件保留，未完成的文件不输出 completed
+func TestStreamParserTruncated(t *testing.T) {
+	files, _, parser := parseAll(`{"html": "<p>ok</p>", "css": "body{marg`, 4)
+	if !parser.Truncated() {
+		t.Fatal("未闭合的 JSON 应判定为截断")
+	}
+	if files["index.html"] != "<p>ok</p>" {
+		t.Fatalf("已完成的文件应保留: %v", files)
+	}
+	if _, ok := files["style.css"]; ok {
+		t.Fatal("未完成的文件不应输出 completed")
+	}
+}
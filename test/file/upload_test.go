@@ -0,0 +1,38 @@
+package file_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aicode/file"
+)
+
+// TestSaveAppUpload 上传文件保存在隐藏目录，只有上传文件时不视为已生成代码
+func TestSaveAppUpload(t *testing.T) {
+	appDir := setupStore(t, "21")
+	data := []byte("\x89PNG\r\n\x1a\n")
+
+	p, err := file.SaveAppUpload("21", ".png", data)
+	if err != nil {
+		t.Fatalf("保存上传文件失败: %v", err)
+	}
+	if !strings.HasPrefix(p, ".uploads/") || !strings.HasSuffix(p, ".png") {
+		t.Fatalf("保存路径不符合预期: %s", p)
+	}
+	got, err := os.ReadFile(filepath.Join(appDir, filepath.FromSlash(p)))
+	if err != nil || string(got) != string(data) {
+		t.Fatalf("上传文件内容不一致: %q, %v", got, err)
+	}
+	if file.AppCodeExists("21") {
+		t.Fatalf("只有上传文件时不应视为已生成代码")
+	}
+
+	if err := file.WriteAppFile("21", "index.html", "<html></html>"); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	if !file.AppCodeExists("21") {
+		t.Fatalf("写入代码后应视为已生成代码")
+	}
+}
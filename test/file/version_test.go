@@ -0,0 +1,86 @@
+package file_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aicode/file"
+)
+
+// TestSnapshotDiffAndRestore 快照后修改应用目录，比较两个版本的差异并回滚到旧版本
+func TestSnapshotDiffAndRestore(t *testing.T) {
+	appDir := setupStore(t, "7")
+	for name, content := range map[string]string{
+		"index.html": "<html>\n<body>v1</body>\n</html>\n",
+		"style.css":  "body {}\n",
+	} {
+		if err := file.WriteAppFile("7", name, content); err != nil {
+			t.Fatalf("写入文件失败: %v", err)
+		}
+	}
+	// 隐藏目录不属于生成代码，不进入快照且回滚时保留
+	if err := os.MkdirAll(filepath.Join(appDir, ".uploads"), 0755); err != nil {
+		t.Fatalf("创建隐藏目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(appDir, ".uploads", "logo.png"), []byte("png"), 0644); err != nil {
+		t.Fatalf("写入隐藏文件失败: %v", err)
+	}
+	if err := file.SnapshotApp("7", 1); err != nil {
+		t.Fatalf("保存快照失败: %v", err)
+	}
+
+	// 第二版：修改 index.html、删除 style.css、新增 script.js
+	if err := file.WriteAppFile("7", "index.html", "<html>\n<body>v2</body>\n</html>\n"); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	if err := file.DeleteAppFile("7", "style.css"); err != nil {
+		t.Fatalf("删除文件失败: %v", err)
+	}
+	if err := file.WriteAppFile("7", "script.js", "console.log(1)\n"); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	if err := file.SnapshotApp("7", 2); err != nil {
+		t.Fatalf("保存快照失败: %v", err)
+	}
+
+	diffs, err := file.DiffAppVersions("7", 1, 2)
+	if err != nil {
+		t.Fatalf("比较版本失败: %v", err)
+	}
+	want := map[string]string{
+		"index.html": file.VersionFileModified,
+		"script.js":  file.VersionFileAdded,
+		"style.css":  file.VersionFileRemoved,
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("变更文件数 = %d, 期望 %d: %+v", len(diffs), len(want), diffs)
+	}
+	for _, d := range diffs {
+		if want[d.Path] != d.Status {
+			t.Errorf("%s 变更类型 = %s, 期望 %s", d.Path, d.Status, want[d.Path])
+		}
+		if d.Path == "index.html" &&
+			(!strings.Contains(d.Diff, "-<body>v1</body>") || !strings.Contains(d.Diff, "+<body>v2</body>")) {
+			t.Errorf("index.html diff 不符合预期:\n%s", d.Diff)
+		}
+	}
+
+	if err := file.RestoreAppVersion("7", 1); err != nil {
+		t.Fatalf("回滚失败: %v", err)
+	}
+	content, err := file.ReadAppFile("7", "index.html")
+	if err != nil || !strings.Contains(content, "v1") {
+		t.Fatalf("回滚后 index.html = %q, err = %v", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(appDir, "style.css")); err != nil {
+		t.Errorf("回滚后 style.css 应恢复: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(appDir, "script.js")); !os.IsNotExist(err) {
+		t.Errorf("回滚后 script.js 应被删除")
+	}
+	if _, err := os.Stat(filepath.Join(appDir, ".uploads", "logo.png")); err != nil {
+		t.Errorf("回滚应保留隐藏文件: %v", err)
+	}
+}
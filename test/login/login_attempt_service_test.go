@@ -0,0 +1,245 @@
+package login_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"aicode/config"
+	"aicode/constant"
+	"aicode/internal/exception"
+	"aicode/internal/mapper"
+	"aicode/internal/model/entity"
+	"aicode/internal/service"
+	"aicode/internal/service/impl"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	testAccount = "alice"
+	testIP      = "198.51.100.7"
+	testUA      = "login-test"
+)
+
+// loginConfig 测试使用的登录防暴力破解配置：3 次失败锁定，IP 5 次失败后拒绝
+const loginConfig = `security:
+  login:
+    max_failures: 3
+    lock_minutes: 15
+    window_minutes: 15
+    delay_base_seconds: 1
+    delay_max_seconds: 30
+    ip_max_failures: 5
+`
+
+// setup 加载测试配置，使用内存计数与 SQLite 内存库创建登录防暴力破解服务，并创建一个用户
+func setup(t *testing.T) (service.LoginAttemptService, *mapper.MemoryLoginCounterMapper, *entity.User) {
+	t.Helper()
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(cfgPath, []byte(loginConfig), 0644); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+	config.LoadConfig(cfgPath)
+
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	if err := db.AutoMigrate(&entity.User{}, &entity.LoginAttempt{}); err != nil {
+		t.Fatalf("创建数据表失败: %v", err)
+	}
+	user := &entity.User{UserAccount: testAccount, UserPassword: "-", UserRole: constant.UserRole}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+
+	counters := mapper.NewMemoryLoginCounterMapper()
+	svc := impl.NewLoginAttemptService(counters, mapper.NewLoginAttemptMapper(db), mapper.NewUserMapper(db))
+	return svc, counters, user
+}
+
+// elapse 将计数的最近尝试时间提前 d，模拟等待
+func elapse(t *testing.T, counters mapper.LoginCounterMapper, key string, d time.Duration) {
+	t.Helper()
+	err := counters.Update(context.Background(), key, time.Hour, func(counter *entity.LoginCounter) error {
+		counter.LastTime = counter.LastTime.Add(-d)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("修改计数失败: %v", err)
+	}
+}
+
+// errorCode 返回业务错误码，nil 返回 0
+func errorCode(t *testing.T, err error) int {
+	t.Helper()
+	if err == nil {
+		return 0
+	}
+	bizErr, ok := err.(*exception.BusinessError)
+	if !ok {
+		t.Fatalf("应返回业务错误: %v", err)
+	}
+	return bizErr.Code()
+}
+
+// fail 完成一次失败的登录尝试，返回 RecordFailure 的错误码
+func fail(t *testing.T, svc service.LoginAttemptService, account, ip string) int {
+	t.Helper()
+	ctx := context.Background()
+	failures, err := svc.CheckLogin(ctx, account, ip, testUA)
+	if err != nil {
+		t.Fatalf("第 %d 次尝试不应被拒绝: %v", failures, err)
+	}
+	return errorCode(t, svc.RecordFailure(ctx, account, 0, ip, testUA, "password", failures))
+}
+
+// TestLoginThrottledAfterFailure 失败后等待时间未到时再次尝试返回 42900
+func TestLoginThrottledAfterFailure(t *testing.T) {
+	svc, counters, _ := setup(t)
+	if code := fail(t, svc, testAccount, testIP); code != 0 {
+		t.Fatalf("首次失败不应锁定，错误码 %d", code)
+	}
+	_, err := svc.CheckLogin(context.Background(), testAccount, testIP, testUA)
+	if code := errorCode(t, err); code != 42900 {
+		t.Fatalf("等待时间未到应返回 42900，实际 %d", code)
+	}
+
+	elapse(t, counters, "account:"+testAccount, time.Second)
+	if _, err := svc.CheckLogin(context.Background(), testAccount, testIP, testUA); err != nil {
+		t.Fatalf("等待后应允许再次尝试: %v", err)
+	}
+}
+
+// TestLoginConcurrentAttempts 并发尝试在校验密码前逐个计入，只有一个请求能通过校验
+func TestLoginConcurrentAttempts(t *testing.T) {
+	svc, _, _ := setup(t)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed, throttled := 0, 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.CheckLogin(context.Background(), testAccount, "", testUA)
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				allowed++
+			} else if bizErr, ok := err.(*exception.BusinessError); ok && bizErr.Code() == 42900 {
+				throttled++
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed != 1 || throttled != 19 {
+		t.Fatalf("应只放行 1 个请求，实际放行 %d 个，拒绝 %d 个", allowed, throttled)
+	}
+}
+
+// TestLoginLockoutAndUnlock 连续失败达到阈值后锁定返回 42300，管理员解锁后可再次尝试
+func TestLoginLockoutAndUnlock(t *testing.T) {
+	svc, counters, user := setup(t)
+	key := "account:" + testAccount
+	for i := 1; i <= 3; i++ {
+		code := fail(t, svc, testAccount, testIP)
+		if i < 3 && code != 0 {
+			t.Fatalf("第 %d 次失败不应锁定，错误码 %d", i, code)
+		}
+		if i == 3 && code != 42300 {
+			t.Fatalf("达到阈值的失败应返回 42300，实际 %d", code)
+		}
+		elapse(t, counters, key, time.Minute)
+	}
+
+	_, err := svc.CheckLogin(context.Background(), testAccount, testIP, testUA)
+	if code := errorCode(t, err); code != 42300 {
+		t.Fatalf("锁定期间应返回 42300，实际 %d", code)
+	}
+
+	admin := &entity.User{ID: 99, UserRole: constant.AdminRole}
+	ctx := context.WithValue(context.Background(), constant.UserLoginState, admin)
+	if ok, err := svc.UnlockUser(ctx, user.ID); err != nil || !ok {
+		t.Fatalf("解锁失败: ok=%v err=%v", ok, err)
+	}
+	failures, err := svc.CheckLogin(context.Background(), testAccount, testIP, testUA)
+	if err != nil || failures != 1 {
+		t.Fatalf("解锁后失败次数应重新计数: failures=%d err=%v", failures, err)
+	}
+
+	attempts, err := svc.ListLoginAttempts(ctx, user.ID)
+	if err != nil || len(attempts) == 0 || attempts[0].Result != constant.LoginAttemptUnlocked {
+		t.Fatalf("应记录解锁审计: attempts=%+v err=%v", attempts, err)
+	}
+}
+
+// TestLoginLockExpires 锁定时长过后允许再次尝试，失败次数重新计数
+func TestLoginLockExpires(t *testing.T) {
+	svc, counters, _ := setup(t)
+	key := "account:" + testAccount
+	for i := 0; i < 3; i++ {
+		fail(t, svc, testAccount, testIP)
+		elapse(t, counters, key, time.Minute)
+	}
+	elapse(t, counters, key, 15*time.Minute)
+	failures, err := svc.CheckLogin(context.Background(), testAccount, testIP, testUA)
+	if err != nil || failures != 1 {
+		t.Fatalf("锁定过期后失败次数应重新计数: failures=%d err=%v", failures, err)
+	}
+}
+
+// TestLoginResetOnSuccess 登录成功后账号的失败次数清零，本次尝试不计入 IP 的失败次数
+func TestLoginResetOnSuccess(t *testing.T) {
+	svc, counters, user := setup(t)
+	ctx := context.Background()
+	key := "account:" + testAccount
+	fail(t, svc, testAccount, testIP)
+	elapse(t, counters, key, time.Minute)
+	fail(t, svc, testAccount, testIP)
+	elapse(t, counters, key, time.Minute)
+
+	if _, err := svc.CheckLogin(ctx, testAccount, testIP, testUA); err != nil {
+		t.Fatalf("第 3 次尝试不应被拒绝: %v", err)
+	}
+	svc.RecordSuccess(ctx, testAccount, user.ID, testIP, testUA)
+
+	failures, err := svc.CheckLogin(ctx, testAccount, testIP, testUA)
+	if err != nil || failures != 1 {
+		t.Fatalf("登录成功后失败次数应清零: failures=%d err=%v", failures, err)
+	}
+	var ipCount int64
+	_ = counters.Update(ctx, "ip:"+testIP, time.Hour, func(counter *entity.LoginCounter) error {
+		ipCount = counter.Count
+		return nil
+	})
+	if ipCount != 3 {
+		t.Fatalf("IP 失败次数应为 3（2 次失败与最后一次尝试），实际 %d", ipCount)
+	}
+}
+
+// TestLoginIPThreshold 同一 IP 失败次数达到阈值后拒绝其所有账号的登录，其他 IP 不受影响
+func TestLoginIPThreshold(t *testing.T) {
+	svc, _, _ := setup(t)
+	accounts := []string{"user1", "user2", "user3", "user4", "user5"}
+	for _, account := range accounts {
+		fail(t, svc, account, testIP)
+	}
+
+	_, err := svc.CheckLogin(context.Background(), "user6", testIP, testUA)
+	if code := errorCode(t, err); code != 42900 {
+		t.Fatalf("IP 失败次数达到阈值后应返回 42900，实际 %d", code)
+	}
+	if _, err := svc.CheckLogin(context.Background(), "user6", "198.51.100.8", testUA); err != nil {
+		t.Fatalf("其他 IP 不应受影响: %v", err)
+	}
+}
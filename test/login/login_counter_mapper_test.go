@@ -0,0 +1,101 @@
+package login_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"aicode/internal/mapper"
+	"aicode/internal/model/entity"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newCounterMappers 创建内存与 Redis（miniredis）登录失败计数
+func newCounterMappers(t *testing.T) map[string]mapper.LoginCounterMapper {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return map[string]mapper.LoginCounterMapper{
+		"memory": mapper.NewMemoryLoginCounterMapper(),
+		"redis":  mapper.NewRedisLoginCounterMapper(client, "test:"),
+	}
+}
+
+// count 读取 key 当前的计数
+func count(t *testing.T, counters mapper.LoginCounterMapper, key string) int64 {
+	t.Helper()
+	var result int64
+	errRead := errors.New("read only")
+	err := counters.Update(context.Background(), key, time.Hour, func(counter *entity.LoginCounter) error {
+		result = counter.Count
+		return errRead
+	})
+	if !errors.Is(err, errRead) {
+		t.Fatalf("读取计数失败: %v", err)
+	}
+	return result
+}
+
+// TestLoginCounterConcurrentUpdate 并发更新同一 key 时每次递增都被保留
+func TestLoginCounterConcurrentUpdate(t *testing.T) {
+	for name, counters := range newCounterMappers(t) {
+		t.Run(name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					err := counters.Update(context.Background(), "k", time.Hour, func(counter *entity.LoginCounter) error {
+						counter.Count++
+						return nil
+					})
+					if err != nil {
+						t.Errorf("更新计数失败: %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+			if got := count(t, counters, "k"); got != 20 {
+				t.Fatalf("计数应为 20，实际 %d", got)
+			}
+		})
+	}
+}
+
+// TestLoginCounterRejectAndDelete fn 返回错误时不保存修改，删除后计数归零
+func TestLoginCounterRejectAndDelete(t *testing.T) {
+	ctx := context.Background()
+	for name, counters := range newCounterMappers(t) {
+		t.Run(name, func(t *testing.T) {
+			increment := func(counter *entity.LoginCounter) error {
+				counter.Count++
+				return nil
+			}
+			if err := counters.Update(ctx, "k", time.Hour, increment); err != nil {
+				t.Fatalf("更新计数失败: %v", err)
+			}
+			errReject := errors.New("reject")
+			err := counters.Update(ctx, "k", time.Hour, func(counter *entity.LoginCounter) error {
+				counter.Count += 10
+				return errReject
+			})
+			if !errors.Is(err, errReject) {
+				t.Fatalf("应原样返回 fn 的错误: %v", err)
+			}
+			if got := count(t, counters, "k"); got != 1 {
+				t.Fatalf("拒绝时不应保存修改，计数 %d", got)
+			}
+			if err := counters.Delete(ctx, "k"); err != nil {
+				t.Fatalf("删除计数失败: %v", err)
+			}
+			if got := count(t, counters, "k"); got != 0 {
+				t.Fatalf("删除后计数应为 0，实际 %d", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aicode/internal/router/middleware"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newStores 构造待测的令牌桶存储：内存实现与基于 miniredis 的 Redis 实现
+func newStores(t *testing.T) map[string]middleware.RateLimitStore {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return map[string]middleware.RateLimitStore{
+		"memory": middleware.NewMemoryRateLimitStore(),
+		"redis":  middleware.NewRedisRateLimitStore(client, "test:"),
+	}
+}
+
+// TestBurstExhausted 桶容量耗尽后拒绝请求，且不同 key 互不影响
+func TestBurstExhausted(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range newStores(t) {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 3; i++ {
+				allowed, err := store.Allow(ctx, "ai_code:user:1", 0.001, 3)
+				if err != nil {
+					t.Fatalf("取令牌失败: %v", err)
+				}
+				if !allowed {
+					t.Fatalf("第 %d 次请求应放行", i+1)
+				}
+			}
+			allowed, err := store.Allow(ctx, "ai_code:user:1", 0.001, 3)
+			if err != nil {
+				t.Fatalf("取令牌失败: %v", err)
+			}
+			if allowed {
+				t.Fatal("桶容量耗尽后应拒绝请求")
+			}
+
+			allowed, err = store.Allow(ctx, "ai_code:user:2", 0.001, 3)
+			if err != nil {
+				t.Fatalf("取令牌失败: %v", err)
+			}
+			if !allowed {
+				t.Fatal("其他用户的请求应放行")
+			}
+		})
+	}
+}
+
+// TestRefill 令牌按速率补充
+func TestRefill(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range newStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if allowed, _ := store.Allow(ctx, "ai_chat:ip:127.0.0.1", 20, 1); !allowed {
+				t.Fatal("首次请求应放行")
+			}
+			if allowed, _ := store.Allow(ctx, "ai_chat:ip:127.0.0.1", 20, 1); allowed {
+				t.Fatal("令牌未补充前应拒绝请求")
+			}
+			time.Sleep(100 * time.Millisecond)
+			allowed, err := store.Allow(ctx, "ai_chat:ip:127.0.0.1", 20, 1)
+			if err != nil {
+				t.Fatalf("取令牌失败: %v", err)
+			}
+			if !allowed {
+				t.Fatal("令牌补充后应放行")
+			}
+		})
+	}
+}
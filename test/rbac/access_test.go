@@ -0,0 +1,159 @@
+package rbac_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aicode/constant"
+	"aicode/internal/mapper"
+	"aicode/internal/model/entity"
+	"aicode/internal/router/middleware"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// fakeAuthenticator 以令牌作为用户角色返回登录用户
+type fakeAuthenticator struct{}
+
+func (fakeAuthenticator) AuthenticateBearer(_ context.Context, token string) (*entity.User, []string, error) {
+	if token == "invalid" {
+		return nil, nil, errors.New("invalid token")
+	}
+	return &entity.User{ID: 1, UserRole: token}, nil, nil
+}
+
+// sessionUserRoles session 登录用户当前的角色，模拟数据库中的用户
+var sessionUserRoles = map[int64]string{}
+
+func (fakeAuthenticator) AuthenticateSession(_ context.Context, userId int64) (*entity.User, error) {
+	role, ok := sessionUserRoles[userId]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	return &entity.User{ID: userId, UserRole: role}, nil
+}
+
+// fakePermissionChecker 只有 operator 角色拥有 prompt:manage 权限
+type fakePermissionChecker struct{}
+
+func (fakePermissionChecker) HasPermission(_ context.Context, role, permission string) (bool, error) {
+	return role == "operator" && permission == constant.PermissionPromptManage, nil
+}
+
+// newEngine 构造使用自定义 rootPath 的测试路由，各访问级别各注册一条路由
+func newEngine() *gin.Engine {
+	store := middleware.NewSessionStore(mapper.NewMemorySessionMapper(), time.Hour, time.Hour, []byte("test-secret"))
+	r := gin.New()
+	r.Use(sessions.Sessions("session_id", store))
+	r.Use(middleware.AuthMiddleware(fakeAuthenticator{}, fakePermissionChecker{}))
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"code": 0}) }
+	group := r.Group("/custom/root").Group("/demo")
+	middleware.Public(group).POST("/login", ok)
+	middleware.Public(group).POST("/session/login", func(c *gin.Context) {
+		session := sessions.Default(c)
+		session.Set(constant.UserLoginState, int64(7))
+		_ = session.Save()
+		c.JSON(http.StatusOK, gin.H{"code": 0})
+	})
+	group.GET("/me", ok)
+	middleware.Admin(group).POST("/delete", ok)
+	middleware.Permission(group, constant.PermissionPromptManage).GET("/prompt/", ok)
+	return r
+}
+
+// code 发送请求并返回响应中的业务错误码
+func code(t *testing.T, r *gin.Engine, method, path, token string) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var body struct {
+		Code int `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("解析响应失败: %v, body=%s", err, w.Body.String())
+	}
+	return body.Code
+}
+
+// TestRouteAccess 各访问级别按声明校验，路径与配置的 rootPath 无关
+func TestRouteAccess(t *testing.T) {
+	r := newEngine()
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"公开接口无需登录", http.MethodPost, "/custom/root/demo/login", "", 0},
+		{"默认需要登录", http.MethodGet, "/custom/root/demo/me", "", 40100},
+		{"无效令牌", http.MethodGet, "/custom/root/demo/me", "invalid", 40100},
+		{"登录用户可访问", http.MethodGet, "/custom/root/demo/me", "user", 0},
+		{"同路径其他方法未声明为公开", http.MethodGet, "/custom/root/demo/login", "", 40100},
+		{"普通用户不能访问管理员接口", http.MethodPost, "/custom/root/demo/delete", "user", 40101},
+		{"管理员可访问管理员接口", http.MethodPost, "/custom/root/demo/delete", "admin", 0},
+		{"无权限的角色", http.MethodGet, "/custom/root/demo/prompt/", "admin", 40101},
+		{"拥有权限的角色", http.MethodGet, "/custom/root/demo/prompt/", "operator", 0},
+		{"未登录访问权限接口", http.MethodGet, "/custom/root/demo/prompt/", "", 40100},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := code(t, r, tc.method, tc.path, tc.token); got != tc.want {
+				t.Fatalf("%s %s 错误码为 %d，期望 %d", tc.method, tc.path, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestSessionReloadsUser session 中只保存用户 id，角色变更与用户删除对已登录的 session 即时生效
+func TestSessionReloadsUser(t *testing.T) {
+	r := newEngine()
+	sessionUserRoles[7] = constant.AdminRole
+	defer delete(sessionUserRoles, 7)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/custom/root/demo/session/login", nil))
+	cookies := w.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("登录后应写入 session cookie")
+	}
+	sessionCode := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/custom/root/demo/delete", nil)
+		req.AddCookie(cookies[0])
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var body struct {
+			Code int `json:"code"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("解析响应失败: %v, body=%s", err, w.Body.String())
+		}
+		return body.Code
+	}
+
+	if got := sessionCode(); got != 0 {
+		t.Fatalf("管理员应可访问管理员接口，错误码 %d", got)
+	}
+	sessionUserRoles[7] = constant.UserRole
+	if got := sessionCode(); got != 40101 {
+		t.Fatalf("降级为普通用户后应无权访问，错误码 %d", got)
+	}
+	delete(sessionUserRoles, 7)
+	if got := sessionCode(); got != 40100 {
+		t.Fatalf("用户删除后应需要重新登录，错误码 %d", got)
+	}
+}
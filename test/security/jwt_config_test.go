@@ -0,0 +1,34 @@
+package security_test
+
+import (
+	"testing"
+
+	"aicode/config"
+)
+
+// TestJWTSecretRejectsPlaceholder 签名密钥为公开的占位值时拒绝使用，session_secret 作为后备时同样校验
+func TestJWTSecretRejectsPlaceholder(t *testing.T) {
+	cases := []struct {
+		secret, sessionSecret string
+		wantErr               bool
+	}{
+		{"change_me", "", true},
+		{" CHANGE_ME ", "", true},
+		{"", "changeme", true},
+		{"9f86d081884c7d659a2feaa0c55ad015", "change_me", false},
+		{"", "", false},
+	}
+	for _, c := range cases {
+		jwt := &config.JWTConfig{Secret: c.secret}
+		if err := jwt.CheckSecret(c.sessionSecret); (err != nil) != c.wantErr {
+			t.Errorf("secret=%q sessionSecret=%q 校验结果 %v，期望出错 %v", c.secret, c.sessionSecret, err, c.wantErr)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("占位密钥不应用于签名")
+		}
+	}()
+	(&config.JWTConfig{Secret: "change_me"}).GetSecret("")
+}
@@ -0,0 +1,38 @@
+package security_test
+
+import (
+	"testing"
+	"time"
+
+	"aicode/config"
+)
+
+// TestLoginDelay 失败后的等待时间逐次翻倍且不超过上限，未配置时使用默认值
+func TestLoginDelay(t *testing.T) {
+	cfg := &config.LoginConfig{DelayBaseSeconds: 2, DelayMaxSeconds: 10}
+	expected := map[int]time.Duration{
+		0: 0,
+		1: 2 * time.Second,
+		2: 4 * time.Second,
+		3: 8 * time.Second,
+		4: 10 * time.Second,
+		9: 10 * time.Second,
+	}
+	for failures, want := range expected {
+		if got := cfg.GetDelay(failures); got != want {
+			t.Fatalf("第 %d 次失败后等待 %v，期望 %v", failures, got, want)
+		}
+	}
+
+	defaults := &config.LoginConfig{}
+	if got := defaults.GetDelay(1); got != time.Second {
+		t.Fatalf("默认首次等待 %v，期望 1s", got)
+	}
+	if got := defaults.GetDelay(100); got != 30*time.Second {
+		t.Fatalf("默认等待上限 %v，期望 30s", got)
+	}
+	if defaults.GetMaxFailures() != 5 || defaults.GetLockDuration() != 15*time.Minute ||
+		defaults.GetIPMaxFailures() != 50 {
+		t.Fatalf("默认锁定配置错误: %+v", defaults)
+	}
+}
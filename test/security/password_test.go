@@ -0,0 +1,97 @@
+package security_test
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"aicode/security"
+)
+
+const legacySalt = "yumi123"
+
+// newEncoder 创建测试用密码编码器，使用最低强度以加快测试
+func newEncoder(t *testing.T, algorithm string) *security.PasswordEncoder {
+	t.Helper()
+	encoder, err := security.NewPasswordEncoder(algorithm, security.NewBcryptHasher(4),
+		security.NewArgon2idHasher(1024, 1, 1), legacySalt)
+	if err != nil {
+		t.Fatalf("创建密码编码器失败: %v", err)
+	}
+	return encoder
+}
+
+// TestPasswordEncoderRoundTrip 各算法生成的哈希可以校验通过，错误密码校验失败
+func TestPasswordEncoderRoundTrip(t *testing.T) {
+	for _, algorithm := range []string{security.AlgorithmBcrypt, security.AlgorithmArgon2id} {
+		encoder := newEncoder(t, algorithm)
+		encoded, err := encoder.Encode("12345678")
+		if err != nil {
+			t.Fatalf("%s 生成哈希失败: %v", algorithm, err)
+		}
+		if got := security.DetectAlgorithm(encoded); got != algorithm {
+			t.Fatalf("识别的算法为 %q，期望 %q", got, algorithm)
+		}
+		ok, needsRehash, err := encoder.Matches("12345678", encoded)
+		if err != nil || !ok || needsRehash {
+			t.Fatalf("%s 校验正确密码: ok=%v needsRehash=%v err=%v", algorithm, ok, needsRehash, err)
+		}
+		ok, _, err = encoder.Matches("87654321", encoded)
+		if err != nil || ok {
+			t.Fatalf("%s 错误密码应校验失败: ok=%v err=%v", algorithm, ok, err)
+		}
+	}
+}
+
+// TestPasswordEncoderLegacyMD5 旧版加盐 MD5 哈希可以校验，且需要重新生成
+func TestPasswordEncoderLegacyMD5(t *testing.T) {
+	sum := md5.Sum([]byte("12345678" + legacySalt))
+	legacy := hex.EncodeToString(sum[:])
+	encoder := newEncoder(t, security.AlgorithmArgon2id)
+
+	ok, needsRehash, err := encoder.Matches("12345678", legacy)
+	if err != nil || !ok || !needsRehash {
+		t.Fatalf("旧版哈希校验: ok=%v needsRehash=%v err=%v", ok, needsRehash, err)
+	}
+	if ok, _, _ := encoder.Matches("87654321", legacy); ok {
+		t.Fatalf("错误密码不应通过旧版哈希校验")
+	}
+}
+
+// TestPasswordEncoderRehash 切换算法或调整参数后，旧哈希校验通过但需要重新生成
+func TestPasswordEncoderRehash(t *testing.T) {
+	bcryptEncoded, err := newEncoder(t, security.AlgorithmBcrypt).Encode("12345678")
+	if err != nil {
+		t.Fatalf("生成哈希失败: %v", err)
+	}
+	ok, needsRehash, err := newEncoder(t, security.AlgorithmArgon2id).Matches("12345678", bcryptEncoded)
+	if err != nil || !ok || !needsRehash {
+		t.Fatalf("切换算法后: ok=%v needsRehash=%v err=%v", ok, needsRehash, err)
+	}
+
+	stronger, err := security.NewPasswordEncoder(security.AlgorithmBcrypt, security.NewBcryptHasher(5),
+		security.NewArgon2idHasher(1024, 1, 1), legacySalt)
+	if err != nil {
+		t.Fatalf("创建密码编码器失败: %v", err)
+	}
+	ok, needsRehash, err = stronger.Matches("12345678", bcryptEncoded)
+	if err != nil || !ok || !needsRehash {
+		t.Fatalf("调整 cost 后: ok=%v needsRehash=%v err=%v", ok, needsRehash, err)
+	}
+}
+
+// TestPasswordEncoderInvalid 不支持的算法与无法识别的哈希返回错误
+func TestPasswordEncoderInvalid(t *testing.T) {
+	if _, err := security.NewPasswordEncoder("scrypt", security.NewBcryptHasher(4),
+		security.NewArgon2idHasher(0, 0, 0), legacySalt); err == nil {
+		t.Fatalf("不支持的算法应返回错误")
+	}
+	encoder := newEncoder(t, security.AlgorithmBcrypt)
+	if ok, _, err := encoder.Matches("12345678", "plaintext"); ok || err == nil {
+		t.Fatalf("无法识别的哈希: ok=%v err=%v", ok, err)
+	}
+	if _, err := encoder.Encode(strings.Repeat("a", 73)); err == nil {
+		t.Fatalf("超过 72 字节的密码应返回错误")
+	}
+}
@@ -0,0 +1,212 @@
+package session_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aicode/constant"
+	"aicode/internal/mapper"
+	"aicode/internal/router/middleware"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newBackends 构造待测的 session 存储后端：内存实现与基于 miniredis 的 Redis 实现
+func newBackends(t *testing.T) map[string]mapper.SessionMapper {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return map[string]mapper.SessionMapper{
+		"memory": mapper.NewMemorySessionMapper(),
+		"redis":  mapper.NewRedisSessionMapper(client, "test:"),
+	}
+}
+
+// newEngine 构造挂载 session 中间件的测试路由：/login 写入登录用户，/me 读取登录用户，/logout 清空 session
+func newEngine(backend mapper.SessionMapper) *gin.Engine {
+	store := middleware.NewSessionStore(backend, time.Hour, 30*time.Minute, []byte("test-secret"))
+	r := gin.New()
+	r.Use(sessions.Sessions("session_id", store))
+	r.GET("/login", func(c *gin.Context) {
+		session := sessions.Default(c)
+		session.Set(constant.UserLoginState, int64(1))
+		_ = session.Save()
+		c.Status(http.StatusOK)
+	})
+	r.GET("/me", func(c *gin.Context) {
+		if _, ok := sessions.Default(c).Get(constant.UserLoginState).(int64); !ok {
+			c.Status(http.StatusUnauthorized)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	r.GET("/logout", func(c *gin.Context) {
+		session := sessions.Default(c)
+		session.Clear()
+		_ = session.Save()
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+// do 发送带 cookie 的请求，返回响应
+func do(r *gin.Engine, path string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// sessionCookie 取出响应中的 session cookie
+func sessionCookie(t *testing.T, w *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "session_id" {
+			return cookie
+		}
+	}
+	t.Fatal("响应中没有 session cookie")
+	return nil
+}
+
+// TestSessionLoginAndExpire 登录后 session 可用，按用户列出并强制失效后需要重新登录
+func TestSessionLoginAndExpire(t *testing.T) {
+	ctx := context.Background()
+	for name, backend := range newBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			r := newEngine(backend)
+			cookie := sessionCookie(t, do(r, "/login", nil))
+			if w := do(r, "/me", cookie); w.Code != http.StatusOK {
+				t.Fatalf("登录后应能读取 session，状态码 %d", w.Code)
+			}
+
+			records, err := backend.ListByUser(ctx, 1)
+			if err != nil || len(records) != 1 {
+				t.Fatalf("应有 1 个 session: records=%v err=%v", records, err)
+			}
+			if records[0].ClientIP == "" || !records[0].ExpireTime.After(time.Now()) {
+				t.Fatalf("session 记录不完整: %+v", records[0])
+			}
+
+			count, err := backend.DeleteByUser(ctx, 1)
+			if err != nil || count != 1 {
+				t.Fatalf("强制失效: count=%d err=%v", count, err)
+			}
+			if w := do(r, "/me", cookie); w.Code != http.StatusUnauthorized {
+				t.Fatalf("强制失效后应需要重新登录，状态码 %d", w.Code)
+			}
+		})
+	}
+}
+
+// TestSessionRegenerateOnLogin 登录时更换 session id，注销时删除 session
+func TestSessionRegenerateOnLogin(t *testing.T) {
+	ctx := context.Background()
+	for name, backend := range newBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			r := newEngine(backend)
+			first := sessionCookie(t, do(r, "/login", nil))
+			records, _ := backend.ListByUser(ctx, 1)
+			if len(records) != 1 {
+				t.Fatalf("应有 1 个 session，实际 %d", len(records))
+			}
+			oldId := records[0].ID
+
+			sessionCookie(t, do(r, "/logout", first))
+			if record, err := backend.Get(ctx, oldId); err != nil || record != nil {
+				t.Fatalf("注销后 session 应被删除: record=%v err=%v", record, err)
+			}
+
+			// 携带旧 cookie 再次登录，不能沿用旧 session id
+			do(r, "/login", first)
+			records, _ = backend.ListByUser(ctx, 1)
+			if len(records) != 1 || records[0].ID == oldId {
+				t.Fatalf("重新登录应生成新的 session id: %+v", records)
+			}
+		})
+	}
+}
+
+// TestSessionRecordExpired 超过过期时间的 session 读取不到
+func TestSessionRecordExpired(t *testing.T) {
+	ctx := context.Background()
+	for name, backend := range newBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			record := &mapper.SessionRecord{
+				ID:             "expired",
+				UserID:         2,
+				Data:           []byte{},
+				CreateTime:     now.Add(-2 * time.Hour),
+				LastAccessTime: now.Add(-time.Hour),
+				ExpireTime:     now.Add(-time.Second),
+			}
+			if err := backend.Save(ctx, record); err != nil {
+				t.Fatalf("保存 session 失败: %v", err)
+			}
+			if got, err := backend.Get(ctx, "expired"); err != nil || got != nil {
+				t.Fatalf("过期 session 不应被读取: got=%v err=%v", got, err)
+			}
+			if records, _ := backend.ListByUser(ctx, 2); len(records) != 0 {
+				t.Fatalf("过期 session 不应被列出: %+v", records)
+			}
+		})
+	}
+}
+
+// TestSessionClientIP session 记录的客户端 IP 只采信可信代理转发的 X-Forwarded-For
+func TestSessionClientIP(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name           string
+		trustedProxies []string
+		want           string
+	}{
+		{"未配置可信代理时使用对端地址", nil, "192.0.2.1"},
+		{"可信代理转发时使用转发的地址", []string{"192.0.2.0/24"}, "203.0.113.9"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			backend := mapper.NewMemorySessionMapper()
+			store := middleware.NewSessionStore(backend, time.Hour, 30*time.Minute, []byte("test-secret"))
+			r := gin.New()
+			if err := r.SetTrustedProxies(tc.trustedProxies); err != nil {
+				t.Fatalf("设置可信代理失败: %v", err)
+			}
+			r.Use(middleware.SessionClientIP(), sessions.Sessions("session_id", store))
+			r.GET("/login", func(c *gin.Context) {
+				session := sessions.Default(c)
+				session.Set(constant.UserLoginState, int64(1))
+				_ = session.Save()
+				c.Status(http.StatusOK)
+			})
+
+			// httptest 请求的对端地址为 192.0.2.1
+			req := httptest.NewRequest(http.MethodGet, "/login", nil)
+			req.Header.Set("X-Forwarded-For", "203.0.113.9")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			records, err := backend.ListByUser(ctx, 1)
+			if err != nil || len(records) != 1 {
+				t.Fatalf("应有 1 个 session: records=%v err=%v", records, err)
+			}
+			if records[0].ClientIP != tc.want {
+				t.Fatalf("客户端 IP 为 %s，期望 %s", records[0].ClientIP, tc.want)
+			}
+		})
+	}
+}
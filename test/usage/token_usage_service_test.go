@@ -0,0 +1,48 @@
+package usage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aicode/constant"
+	"aicode/consts"
+	"aicode/internal/mapper"
+	"aicode/internal/model/entity"
+	"aicode/internal/service/impl"
+
+	"github.com/cloudwego/eino/schema"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestRecordUsageEstimatesMissingUsage 模型未返回用量时按 prompt 与回答内容估算并记录
+func TestRecordUsageEstimatesMissingUsage(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	if err := db.AutoMigrate(&entity.TokenUsage{}); err != nil {
+		t.Fatalf("创建数据表失败: %v", err)
+	}
+	usageMapper := mapper.NewTokenUsageMapper(db)
+	svc := impl.NewTokenUsageService(usageMapper)
+
+	ctx := context.WithValue(context.Background(), constant.UserLoginState,
+		&entity.User{ID: 1, UserRole: constant.UserRole})
+	prompt := []*schema.Message{schema.SystemMessage("你是一个前端工程师"), schema.UserMessage("生成一个登录页面")}
+	svc.RecordUsage(ctx, 1, "test-model", consts.UsageBizTypeCode, prompt,
+		schema.AssistantMessage("<html><body>login</body></html>", nil))
+
+	total, err := usageMapper.SumTotalByUserSince(1, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("查询用量失败: %v", err)
+	}
+	if total <= 0 {
+		t.Fatalf("未返回用量时应按内容估算记录，实际总用量 %d", total)
+	}
+}